@@ -4,23 +4,45 @@
 package blockchain
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math/big"
+	"reflect"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/log"
-	"github.com/luxfi/sdk/internal/evm"
+	"github.com/luxfi/node/vms/secp256k1fx"
+	"github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/events"
 	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/models"
 	"github.com/luxfi/sdk/network"
 )
 
-// Builder handles blockchain creation and deployment
+// Builder handles blockchain creation and deployment.
+// It is safe for concurrent use.
 type Builder struct {
-	logger      log.Logger
+	logger log.Logger
+
+	mu          sync.RWMutex
 	blockchains map[string]*Blockchain
+	events      *events.Bus
+	sequencers  map[string]*Sequencer
+	metrics     map[string]*Metrics
+	collectors  map[string]struct{}
+	restarter   NodeRestarter
+	keychain    *secp256k1fx.Keychain
+
+	waitBootstrapped bootstrapWaiter
+	deployContract   contractDeployer
+	dialChain        chainDialer
+	newWallet        walletFactory
 }
 
 // Blockchain represents a Lux blockchain
@@ -36,6 +58,33 @@ type Blockchain struct {
 	CreatedAt   time.Time
 	DeployedAt  *time.Time
 	NetworkID   string
+
+	// VMID is the ID of the VM the chain runs
+	VMID types.ID
+	// ValidatorSet are the validators registered with the subnet of an L1
+	ValidatorSet []Validator
+	// SubnetID is the P-Chain subnet of a deployed L1
+	SubnetID types.ID
+	// BlockchainID is the P-Chain ID of a deployed chain
+	BlockchainID types.ID
+	// RPCURL is the RPC endpoint of a deployed chain
+	RPCURL string
+	// L2 describes the rollup of an L2 and the addresses of its contracts
+	L2 *models.L2Config
+	// L3 holds the endpoints and app contracts of a deployed L3
+	L3 *L3Endpoints
+	// Upgrade is the scheduled upgrade.json of the chain, see UpgradeBuilder
+	Upgrade []byte
+	// UpgradeLock is the upgrade.json last applied to the chain
+	UpgradeLock []byte
+	// Sidecar is the CLI sidecar of the chain, carried along by bundles
+	Sidecar *models.Sidecar
+	// PerNodeConfigs are the chain configs of individual nodes, by node ID
+	PerNodeConfigs map[string][]byte
+
+	wallet   PChainWallet
+	l2Config *L2Config
+	l3Config *L3Config
 }
 
 // BlockchainType defines the type of blockchain
@@ -58,6 +107,10 @@ const (
 	VMTypeMorpheusVM VMType = "morpheusvm"
 )
 
+// DefaultChainID is the chain ID of chains created without one, the same
+// as the default of EVM genesis
+const DefaultChainID = 99999
+
 // BlockchainStatus defines the status of a blockchain
 type BlockchainStatus string
 
@@ -73,18 +126,25 @@ const (
 // NewBuilder creates a new blockchain builder
 func NewBuilder(logger log.Logger) *Builder {
 	return &Builder{
-		logger:      logger,
-		blockchains: make(map[string]*Blockchain),
+		logger:           logger,
+		blockchains:      make(map[string]*Blockchain),
+		sequencers:       make(map[string]*Sequencer),
+		metrics:          make(map[string]*Metrics),
+		collectors:       make(map[string]struct{}),
+		waitBootstrapped: waitForEVMBootstrapped,
+		deployContract:   contract.DeployContract,
+		dialChain:        dialEVMChain,
+		newWallet:        newNodeWallet,
 	}
 }
 
-// CreateBlockchain creates a new blockchain
+// CreateBlockchain creates a new blockchain. Its genesis and chain config
+// are validated first, violations are reported as FieldErrors. Creating a
+// definition again returns the blockchain created first, creating another
+// definition under the same name and chain ID is an ErrBlockchainConflict.
 func (b *Builder) CreateBlockchain(ctx context.Context, params *CreateParams) (*Blockchain, error) {
 	b.logger.Info("creating blockchain", "name", params.Name, "type", params.Type, "vm", params.VMType)
 
-	// Generate chain ID
-	chainID := types.GenerateTestID()
-
 	// Create genesis based on VM type
 	genesis, err := b.createGenesis(params)
 	if err != nil {
@@ -96,78 +156,274 @@ func (b *Builder) CreateBlockchain(ctx context.Context, params *CreateParams) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chain config: %w", err)
 	}
+	if err := b.validate(params.VMType, genesis, chainConfig); err != nil {
+		return nil, err
+	}
+
+	vmID := params.VMID
+	if vmID == types.Empty {
+		if vmID, err = vmIDFromType(params.VMType); err != nil {
+			return nil, err
+		}
+	}
+
+	// Derive the IDs from the chain definition, so the same definition
+	// always yields the same IDs
+	chainID := ComputeChainID(genesis, vmID)
 
 	// Create blockchain object
 	blockchain := &Blockchain{
-		ID:          types.GenerateTestID().String(),
-		Name:        params.Name,
-		Type:        params.Type,
-		VMType:      params.VMType,
-		ChainID:     chainID,
-		Genesis:     genesis,
-		ChainConfig: chainConfig,
-		Status:      StatusCreated,
-		CreatedAt:   time.Now(),
-	}
-
-	b.blockchains[blockchain.ID] = blockchain
+		ID:           blockchainKey(params.Name, chainID),
+		Name:         params.Name,
+		Type:         params.Type,
+		VMType:       params.VMType,
+		ChainID:      chainID,
+		Genesis:      genesis,
+		ChainConfig:  chainConfig,
+		Status:       StatusCreated,
+		CreatedAt:    time.Now(),
+		VMID:         vmID,
+		ValidatorSet: slices.Clone(params.ValidatorSet),
+		wallet:       params.Wallet,
+	}
+	if params.Sidecar != nil {
+		sidecar := *params.Sidecar
+		blockchain.Sidecar = &sidecar
+	}
+	blockchain.PerNodeConfigs = clonePerNodeConfigs(params.PerNodeConfigs)
+	if params.Type == TypeL2 && params.L2Config != nil {
+		config := *params.L2Config
+		blockchain.l2Config = &config
+		blockchain.L2 = newL2Info(params.Name, params.ChainID, &config)
+	}
+	if params.Type == TypeL3 && params.L3Config != nil {
+		config := *params.L3Config
+		config.AppConfig = maps.Clone(config.AppConfig)
+		config.Contracts = maps.Clone(config.Contracts)
+		blockchain.l3Config = &config
+	}
+
+	if existing, ok := b.insert(blockchain); !ok {
+		return b.recreated(existing, blockchain)
+	}
 	return blockchain, nil
 }
 
-// Deploy deploys a blockchain to a network
+// Deploy deploys a blockchain to a network, once its genesis and chain
+// config pass validation
 func (b *Builder) Deploy(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
 	b.logger.Info("deploying blockchain", "blockchain", blockchain.Name, "network", network.Name)
 
+	if err := b.validate(blockchain.VMType, blockchain.Genesis, blockchain.ChainConfig); err != nil {
+		return err
+	}
+
 	blockchain.Status = StatusDeploying
+	b.store(blockchain)
 
 	// Deploy based on blockchain type
+	var err error
 	switch blockchain.Type {
 	case TypeL1:
-		if err := b.deployL1(ctx, blockchain, network); err != nil {
-			blockchain.Status = StatusError
-			return err
-		}
+		err = b.deployL1(ctx, blockchain, network)
 	case TypeL2:
-		if err := b.deployL2(ctx, blockchain, network); err != nil {
-			blockchain.Status = StatusError
-			return err
-		}
+		err = b.deployL2(ctx, blockchain, network)
 	case TypeL3:
-		if err := b.deployL3(ctx, blockchain, network); err != nil {
-			blockchain.Status = StatusError
-			return err
-		}
+		err = b.deployL3(ctx, blockchain, network)
 	default:
+		err = fmt.Errorf("unsupported blockchain type: %s", blockchain.Type)
+	}
+	if err != nil {
 		blockchain.Status = StatusError
-		return fmt.Errorf("unsupported blockchain type: %s", blockchain.Type)
+		b.store(blockchain)
+		return err
 	}
 
 	now := time.Now()
 	blockchain.DeployedAt = &now
 	blockchain.NetworkID = network.ID
 	blockchain.Status = StatusDeployed
+	b.store(blockchain)
 
 	return nil
 }
 
-// GetBlockchain returns a blockchain by ID
+// GetBlockchain returns a snapshot of a blockchain by ID
 func (b *Builder) GetBlockchain(blockchainID string) (*Blockchain, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	blockchain, ok := b.blockchains[blockchainID]
 	if !ok {
 		return nil, fmt.Errorf("blockchain %s not found", blockchainID)
 	}
-	return blockchain, nil
+	return blockchain.clone(), nil
 }
 
-// ListBlockchains returns all blockchains
+// ListBlockchains returns snapshots of all blockchains
 func (b *Builder) ListBlockchains() []*Blockchain {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	blockchains := make([]*Blockchain, 0, len(b.blockchains))
 	for _, blockchain := range b.blockchains {
-		blockchains = append(blockchains, blockchain)
+		blockchains = append(blockchains, blockchain.clone())
 	}
 	return blockchains
 }
 
+// RemoveBlockchain removes a blockchain from the builder, stopping its
+// local sequencer
+func (b *Builder) RemoveBlockchain(blockchainID string) error {
+	b.mu.Lock()
+	_, ok := b.blockchains[blockchainID]
+	sequencer := b.sequencers[blockchainID]
+	delete(b.blockchains, blockchainID)
+	delete(b.sequencers, blockchainID)
+	delete(b.metrics, blockchainID)
+	b.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("blockchain %s not found", blockchainID)
+	}
+	if sequencer != nil {
+		if err := sequencer.Stop(); err != nil {
+			return fmt.Errorf("failed to stop sequencer of %s: %w", blockchainID, err)
+		}
+	}
+	return nil
+}
+
+// SetEventBus sets the bus blockchain status transitions are published on
+func (b *Builder) SetEventBus(bus *events.Bus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = bus
+}
+
+// store records a copy of the blockchain, so later changes made by the
+// caller only become visible through another store. Status changes are
+// published on the event bus.
+func (b *Builder) store(blockchain *Blockchain) {
+	stored := blockchain.clone()
+
+	b.mu.Lock()
+	var previous BlockchainStatus
+	if old, ok := b.blockchains[stored.ID]; ok {
+		previous = old.Status
+	}
+	b.blockchains[stored.ID] = stored
+	bus := b.events
+	b.mu.Unlock()
+
+	if previous != stored.Status {
+		publish(bus, stored, previous)
+	}
+}
+
+// insert records a copy of a new blockchain. If a blockchain with the same
+// ID was already created, a snapshot of it is returned and nothing is
+// recorded.
+func (b *Builder) insert(blockchain *Blockchain) (*Blockchain, bool) {
+	stored := blockchain.clone()
+
+	b.mu.Lock()
+	if existing, ok := b.blockchains[stored.ID]; ok {
+		b.mu.Unlock()
+		return existing.clone(), false
+	}
+	b.blockchains[stored.ID] = stored
+	bus := b.events
+	b.mu.Unlock()
+
+	publish(bus, stored, "")
+	return blockchain, true
+}
+
+// recreated returns the existing blockchain a definition was created or
+// imported again as, failing if the two definitions differ
+func (b *Builder) recreated(existing, blockchain *Blockchain) (*Blockchain, error) {
+	if !sameDefinition(existing, blockchain) {
+		return nil, fmt.Errorf("blockchain %s (%s): %w", blockchain.Name, blockchain.ID, ErrBlockchainConflict)
+	}
+	b.logger.Info("blockchain already exists", "name", existing.Name, "id", existing.ID)
+	return existing, nil
+}
+
+// sameDefinition reports whether two blockchains were created from the same
+// definition. State set by deployments and upgrades is not compared.
+func sameDefinition(a, b *Blockchain) bool {
+	return a.Name == b.Name &&
+		a.Type == b.Type &&
+		a.VMType == b.VMType &&
+		a.VMID == b.VMID &&
+		a.ChainID == b.ChainID &&
+		bytes.Equal(a.Genesis, b.Genesis) &&
+		bytes.Equal(a.ChainConfig, b.ChainConfig) &&
+		slices.Equal(a.ValidatorSet, b.ValidatorSet) &&
+		reflect.DeepEqual(a.Sidecar, b.Sidecar) &&
+		reflect.DeepEqual(a.PerNodeConfigs, b.PerNodeConfigs) &&
+		reflect.DeepEqual(a.l2Config, b.l2Config) &&
+		reflect.DeepEqual(a.l3Config, b.l3Config) &&
+		sameWallet(a.wallet, b.wallet)
+}
+
+// sameWallet reports whether two wallets are the same wallet
+func sameWallet(a, b PChainWallet) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// publish publishes the status transition of a blockchain
+func publish(bus *events.Bus, blockchain *Blockchain, previous BlockchainStatus) {
+	bus.Publish(events.Event{
+		Kind:     events.KindBlockchain,
+		ID:       blockchain.ID,
+		Name:     blockchain.Name,
+		Previous: string(previous),
+		Status:   string(blockchain.Status),
+	})
+}
+
+// clone returns a deep copy of the blockchain
+func (bc *Blockchain) clone() *Blockchain {
+	c := *bc
+	c.Genesis = slices.Clone(bc.Genesis)
+	c.ChainConfig = slices.Clone(bc.ChainConfig)
+	c.ValidatorSet = slices.Clone(bc.ValidatorSet)
+	c.Upgrade = slices.Clone(bc.Upgrade)
+	c.UpgradeLock = slices.Clone(bc.UpgradeLock)
+	c.PerNodeConfigs = clonePerNodeConfigs(bc.PerNodeConfigs)
+	if bc.DeployedAt != nil {
+		deployedAt := *bc.DeployedAt
+		c.DeployedAt = &deployedAt
+	}
+	if bc.L2 != nil {
+		l2 := *bc.L2
+		l2.EnabledBridges = slices.Clone(bc.L2.EnabledBridges)
+		l2.IBCChannels = slices.Clone(bc.L2.IBCChannels)
+		c.L2 = &l2
+	}
+	if bc.L3 != nil {
+		c.L3 = bc.L3.clone()
+	}
+	if bc.Sidecar != nil {
+		sidecar := *bc.Sidecar
+		sidecar.Networks = maps.Clone(bc.Sidecar.Networks)
+		sidecar.ElasticSubnet = maps.Clone(bc.Sidecar.ElasticSubnet)
+		c.Sidecar = &sidecar
+	}
+	return &c
+}
+
 // GenerateGenesis generates a genesis file for a blockchain
 func (b *Builder) GenerateGenesis(params *GenesisParams) ([]byte, error) {
 	switch params.VMType {
@@ -177,27 +433,36 @@ func (b *Builder) GenerateGenesis(params *GenesisParams) ([]byte, error) {
 		return b.generateWASMGenesis(params)
 	case VMTypeTokenVM:
 		return b.generateTokenVMGenesis(params)
+	case VMTypeMorpheusVM:
+		return b.generateMorpheusVMGenesis(params)
+	case VMTypeCustom:
+		return b.generateCustomGenesis(params)
 	default:
 		return nil, fmt.Errorf("unsupported VM type: %s", params.VMType)
 	}
 }
 
-// ValidateConfig validates a chain configuration
+// ValidateConfig validates a chain configuration against the ChainConfig
+// schema. Every violation is reported as a FieldError.
 func (b *Builder) ValidateConfig(config []byte) error {
-	// Parse and validate configuration
-	var cfg map[string]interface{}
-	if err := json.Unmarshal(config, &cfg); err != nil {
-		return fmt.Errorf("invalid configuration format: %w", err)
-	}
+	return validateChainConfig(config)
+}
 
-	// Validate required fields
-	requiredFields := []string{"chainId", "consensus", "vm"}
-	for _, field := range requiredFields {
-		if _, ok := cfg[field]; !ok {
-			return fmt.Errorf("missing required field: %s", field)
-		}
-	}
+// ValidateGenesis validates the genesis of a VM against the schema of the
+// VM. Violations are reported as FieldErrors wrapping ErrInvalidGenesis.
+func (b *Builder) ValidateGenesis(vmType VMType, genesis []byte) error {
+	return validateGenesis(vmType, genesis)
+}
 
+// validate validates the genesis and chain configuration of a chain,
+// reporting violations as FieldErrors
+func (b *Builder) validate(vmType VMType, genesis []byte, chainConfig []byte) error {
+	if err := b.ValidateGenesis(vmType, genesis); err != nil {
+		return err
+	}
+	if err := b.ValidateConfig(chainConfig); err != nil {
+		return fmt.Errorf("invalid chain config: %w", err)
+	}
 	return nil
 }
 
@@ -209,11 +474,18 @@ func (b *Builder) createGenesis(params *CreateParams) ([]byte, error) {
 
 	// Generate default genesis based on VM type
 	genesisParams := &GenesisParams{
-		VMType:        params.VMType,
-		ChainID:       params.ChainID,
-		Allocations:   params.Allocations,
-		ValidatorSet:  params.ValidatorSet,
-		InitialSupply: params.InitialSupply,
+		VMType:         params.VMType,
+		ChainID:        params.ChainID,
+		Allocations:    params.Allocations,
+		ValidatorSet:   params.ValidatorSet,
+		InitialSupply:  params.InitialSupply,
+		Assets:         params.Assets,
+		Fees:           params.Fees,
+		AllocationHook: params.AllocationHook,
+		Generator:      params.GenesisGenerator,
+		FeePreset:      params.FeePreset,
+		Precompiles:    params.Precompiles,
+		Admins:         params.Admins,
 	}
 
 	return b.GenerateGenesis(genesisParams)
@@ -225,121 +497,39 @@ func (b *Builder) createChainConfig(params *CreateParams) ([]byte, error) {
 		return params.ChainConfig, nil
 	}
 
+	chainID := params.ChainID
+	if chainID == nil {
+		chainID = big.NewInt(DefaultChainID)
+	}
+
 	// Create default configuration
-	config := map[string]interface{}{
-		"chainId": params.ChainID,
-		"consensus": map[string]interface{}{
-			"type": "lux",
-			"parameters": map[string]interface{}{
-				"k":            21,
-				"alpha":        13,
-				"beta":         8,
-				"maxBlockTime": "10s",
-				"minBlockTime": "1s",
+	config := ChainConfig{
+		ChainID: chainID,
+		Consensus: ConsensusConfig{
+			Type: "lux",
+			Parameters: &ConsensusParameters{
+				K:            21,
+				Alpha:        13,
+				Beta:         8,
+				MaxBlockTime: Duration(10 * time.Second),
+				MinBlockTime: Duration(time.Second),
 			},
 		},
-		"vm": map[string]interface{}{
-			"type":   string(params.VMType),
-			"config": params.VMConfig,
+		VM: VMConfig{
+			Type:   params.VMType,
+			Config: params.VMConfig,
 		},
-		"network": map[string]interface{}{
-			"minStake":         2000,
-			"maxStake":         3000000,
-			"minDelegation":    25,
-			"minDelegationFee": 2,
+		Network: &StakingConfig{
+			MinStake:         2000,
+			MaxStake:         3000000,
+			MinDelegation:    25,
+			MinDelegationFee: 2,
 		},
 	}
 
 	return json.Marshal(config)
 }
 
-// deployL1 deploys an L1 blockchain
-func (b *Builder) deployL1(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
-	// L1 deployment logic
-	b.logger.Info("deploying L1 blockchain", "chain", blockchain.Name)
-
-	// TODO: Implement actual L1 deployment using netrunner
-	// This would involve:
-	// 1. Creating subnet
-	// 2. Adding validators
-	// 3. Creating blockchain in subnet
-	// 4. Starting blockchain
-
-	return nil
-}
-
-// deployL2 deploys an L2 blockchain
-func (b *Builder) deployL2(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
-	// L2 deployment logic
-	b.logger.Info("deploying L2 blockchain", "chain", blockchain.Name)
-
-	// TODO: Implement L2 deployment
-	// This would involve:
-	// 1. Setting up sequencer
-	// 2. Configuring DA layer
-	// 3. Setting up bridge contracts
-	// 4. Starting L2 chain
-
-	return nil
-}
-
-// deployL3 deploys an L3 blockchain
-func (b *Builder) deployL3(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
-	// L3 deployment logic
-	b.logger.Info("deploying L3 blockchain", "chain", blockchain.Name)
-
-	// TODO: Implement L3 deployment
-	// This would involve:
-	// 1. Connecting to L2
-	// 2. Deploying app-specific contracts
-	// 3. Starting L3 chain
-
-	return nil
-}
-
-// generateEVMGenesis generates EVM genesis
-func (b *Builder) generateEVMGenesis(params *GenesisParams) ([]byte, error) {
-	// Convert allocations to evm.GenesisAccount
-	evmAlloc := make(map[common.Address]evm.GenesisAccount)
-	for addr, account := range params.Allocations {
-		evmAlloc[addr] = evm.GenesisAccount{
-			Balance: account.Balance,
-			Code:    account.Code,
-			Storage: account.Storage,
-		}
-	}
-
-	genesis := evm.Genesis{
-		Config: &evm.ChainConfig{
-			ChainID: params.ChainID,
-		},
-		Alloc:     evmAlloc,
-		Timestamp: uint64(time.Now().Unix()),
-		GasLimit:  8000000,
-	}
-
-	return json.Marshal(genesis)
-}
-
-// generateWASMGenesis generates WASM genesis
-func (b *Builder) generateWASMGenesis(params *GenesisParams) ([]byte, error) {
-	// TODO: Implement WASM genesis generation
-	return json.Marshal(map[string]interface{}{
-		"chainID": params.ChainID,
-		"vmType":  "wasm",
-	})
-}
-
-// generateTokenVMGenesis generates TokenVM genesis
-func (b *Builder) generateTokenVMGenesis(params *GenesisParams) ([]byte, error) {
-	// TODO: Implement TokenVM genesis generation
-	return json.Marshal(map[string]interface{}{
-		"chainID": params.ChainID,
-		"vmType":  "tokenvm",
-		"supply":  params.InitialSupply,
-	})
-}
-
 // CreateParams defines parameters for creating a blockchain
 type CreateParams struct {
 	Name          string
@@ -352,8 +542,27 @@ type CreateParams struct {
 	Allocations   map[common.Address]GenesisAccount
 	ValidatorSet  []Validator
 	InitialSupply *big.Int
-	L2Config      *L2Config
-	L3Config      *L3Config
+	// Assets, Fees, AllocationHook and GenesisGenerator are passed to the
+	// genesis generator, see GenesisParams
+	Assets           []AssetDefinition
+	Fees             *FeeRules
+	AllocationHook   AllocationHook
+	GenesisGenerator GenesisGenerator
+	// FeePreset, Precompiles and Admins configure an EVM genesis
+	FeePreset   FeePreset
+	Precompiles EVMPrecompiles
+	Admins      []common.Address
+	L2Config    *L2Config
+	L3Config    *L3Config
+	// VMID overrides the VM ID derived from VMType
+	VMID types.ID
+	// Wallet issues the P-Chain transactions deploying an L1. Without it
+	// the builder keychain pays for them, see SetKeychain.
+	Wallet PChainWallet
+	// Sidecar is the CLI sidecar of the chain, carried along by bundles
+	Sidecar *models.Sidecar
+	// PerNodeConfigs are the chain configs of individual nodes, by node ID
+	PerNodeConfigs map[string][]byte
 }
 
 // L1Params defines parameters for L1 creation
@@ -385,17 +594,50 @@ type L3Params struct {
 
 // L2Config defines L2-specific configuration
 type L2Config struct {
-	SequencerType   string
-	DALayer         string
+	// SequencerType is SequencerCentralized or SequencerBased
+	SequencerType string
+	DALayer       string
+	// RollupType is the proof system of the rollup, such as optimistic or zk
+	RollupType string
+	// SettlementChain is the RPC URL of the settlement chain, or the ID or
+	// name of a deployed blockchain of the builder
 	SettlementChain string
-	BridgeContract  string
+	// BridgeContract is the address of an existing bridge to reuse instead
+	// of deploying one
+	BridgeContract string
+	// Contracts is the bytecode of the rollup contracts to deploy. It is
+	// required, the SDK has no default rollup contracts.
+	Contracts *RollupContracts
+	// PrivateKey is the hex encoded key deploying the contracts
+	PrivateKey string
+	// Sequencer runs the sequencer of a centralized L2
+	Sequencer *SequencerConfig
+	// RPCURL is the RPC endpoint of a based L2, whose transactions are
+	// ordered by the settlement chain
+	RPCURL string
 }
 
 // L3Config defines L3-specific configuration
 type L3Config struct {
-	L2Chain   string
-	AppType   string
+	// L2Chain is the ID or name of the parent L2 in the builder
+	L2Chain string
+	// AppType selects the contract bundle, see AppTypeToken, AppTypeDEX
+	// and AppTypeNFT
+	AppType string
+	// AppConfig holds the settings of the bundle: the owner address of
+	// every app, the symbol and supply of a token, the name and symbol of
+	// an NFT collection and an optional existing weth of a DEX
 	AppConfig map[string]interface{}
+	// Contracts maps the contracts of the bundle to their hex encoded
+	// bytecode. The token contract defaults to the SDK's ERC20, the
+	// contracts of the dex and nft bundles must be given.
+	Contracts map[string][]byte
+	// PrivateKey is the hex encoded key deploying the contracts
+	PrivateKey string
+	// RPCURL is the RPC endpoint the contracts are deployed through, the
+	// one of the parent L2 if empty. No separate chain is started for an
+	// L3.
+	RPCURL string
 }
 
 // GenesisParams defines parameters for genesis generation
@@ -405,4 +647,22 @@ type GenesisParams struct {
 	Allocations   map[common.Address]GenesisAccount
 	ValidatorSet  []Validator
 	InitialSupply *big.Int
+	// Timestamp is the genesis block time in Unix seconds. It defaults to
+	// zero so that the same parameters always produce the same genesis.
+	Timestamp uint64
+	// Assets are the TokenVM assets created at genesis
+	Assets []AssetDefinition
+	// Fees are the fee rules of non-EVM chains, DefaultFeeRules if nil
+	Fees *FeeRules
+	// AllocationHook adds allocations to non-EVM chains, typically for
+	// addresses in the VM's own format
+	AllocationHook AllocationHook
+	// Generator generates the genesis of a custom VM
+	Generator GenesisGenerator
+	// FeePreset selects the fee config of an EVM chain, low by default
+	FeePreset FeePreset
+	// Precompiles are the precompiles enabled on an EVM chain
+	Precompiles EVMPrecompiles
+	// Admins administer the enabled precompiles of an EVM chain
+	Admins []common.Address
 }
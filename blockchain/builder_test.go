@@ -6,11 +6,14 @@ package blockchain
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/events"
 	"github.com/luxfi/sdk/network"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +27,8 @@ func TestBuilder_CreateBlockchain(t *testing.T) {
 		name    string
 		params  *CreateParams
 		wantErr bool
+		// errorPath is the path of the FieldError expected
+		errorPath string
 	}{
 		{
 			name: "create EVM L1",
@@ -80,10 +85,21 @@ func TestBuilder_CreateBlockchain(t *testing.T) {
 				Name:    "test-custom-genesis",
 				Type:    TypeL1,
 				VMType:  VMTypeEVM,
-				Genesis: []byte(`{"chainId": 99999}`),
+				Genesis: []byte(`{"config": {"chainId": 99999, "feeConfig": {}}, "gasLimit": 8000000, "alloc": {}}`),
 			},
 			wantErr: false,
 		},
+		{
+			name: "with invalid custom genesis",
+			params: &CreateParams{
+				Name:    "test-invalid-genesis",
+				Type:    TypeL1,
+				VMType:  VMTypeEVM,
+				Genesis: []byte(`{"chainId": 99999}`),
+			},
+			wantErr:   true,
+			errorPath: "$.config",
+		},
 		{
 			name: "with custom chain config",
 			params: &CreateParams{
@@ -95,13 +111,36 @@ func TestBuilder_CreateBlockchain(t *testing.T) {
 					"consensus": {
 						"type": "lux",
 						"parameters": {
-							"k": 15
+							"k": 15,
+							"alpha": 10,
+							"beta": 8
 						}
-					}
+					},
+					"vm": {"type": "evm"}
 				}`),
 			},
 			wantErr: false,
 		},
+		{
+			name: "with invalid custom chain config",
+			params: &CreateParams{
+				Name:   "test-invalid-config",
+				Type:   TypeL1,
+				VMType: VMTypeEVM,
+				ChainConfig: []byte(`{
+					"chainId": 88888,
+					"consensus": {
+						"type": "lux",
+						"parameters": {
+							"k": 15
+						}
+					},
+					"vm": {"type": "evm"}
+				}`),
+			},
+			wantErr:   true,
+			errorPath: "$.consensus.parameters.alpha",
+		},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +151,11 @@ func TestBuilder_CreateBlockchain(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, blockchain)
+				if tt.errorPath != "" {
+					var fieldErr *FieldError
+					require.ErrorAs(t, err, &fieldErr)
+					assert.Equal(t, tt.errorPath, fieldErr.Path)
+				}
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, blockchain)
@@ -154,17 +198,14 @@ func TestBuilder_Deploy(t *testing.T) {
 		Status: network.NetworkStatusRunning,
 	}
 
-	t.Run("deploy L1", func(t *testing.T) {
+	t.Run("deploy L1 without wallet", func(t *testing.T) {
 		err := builder.Deploy(ctx, blockchain, testNetwork)
-		// Since actual deployment is not implemented, we expect no error
-		// but the blockchain should be marked as deployed
-		assert.NoError(t, err)
-		assert.Equal(t, StatusDeployed, blockchain.Status)
-		assert.NotNil(t, blockchain.DeployedAt)
-		assert.Equal(t, testNetwork.ID, blockchain.NetworkID)
+		assert.ErrorIs(t, err, ErrNoWallet)
+		assert.Equal(t, StatusError, blockchain.Status)
+		assert.Nil(t, blockchain.DeployedAt)
 	})
 
-	t.Run("deploy L2", func(t *testing.T) {
+	t.Run("deploy L2 without settlement chain", func(t *testing.T) {
 		l2Blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{
 			Name:   "l2-deploy-test",
 			Type:   TypeL2,
@@ -176,11 +217,11 @@ func TestBuilder_Deploy(t *testing.T) {
 		require.NoError(t, err)
 
 		err = builder.Deploy(ctx, l2Blockchain, testNetwork)
-		assert.NoError(t, err)
-		assert.Equal(t, StatusDeployed, l2Blockchain.Status)
+		assert.ErrorIs(t, err, ErrNoSettlementChain)
+		assert.Equal(t, StatusError, l2Blockchain.Status)
 	})
 
-	t.Run("deploy L3", func(t *testing.T) {
+	t.Run("deploy L3 without parent L2", func(t *testing.T) {
 		l3Blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{
 			Name:   "l3-deploy-test",
 			Type:   TypeL3,
@@ -193,8 +234,8 @@ func TestBuilder_Deploy(t *testing.T) {
 		require.NoError(t, err)
 
 		err = builder.Deploy(ctx, l3Blockchain, testNetwork)
-		assert.NoError(t, err)
-		assert.Equal(t, StatusDeployed, l3Blockchain.Status)
+		assert.ErrorIs(t, err, ErrParentL2NotFound)
+		assert.Equal(t, StatusError, l3Blockchain.Status)
 	})
 
 	t.Run("deploy error recovery", func(t *testing.T) {
@@ -209,6 +250,18 @@ func TestBuilder_Deploy(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, StatusError, errorBlockchain.Status)
 	})
+
+	t.Run("deploy invalid chain config", func(t *testing.T) {
+		invalid := *blockchain
+		invalid.Status = StatusCreated
+		invalid.ChainConfig = []byte(`{"chainId": 0, "consensus": {"type": "lux"}, "vm": {"type": "evm"}}`)
+
+		err := builder.Deploy(ctx, &invalid, testNetwork)
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "$.chainId", fieldErr.Path)
+		assert.Equal(t, StatusCreated, invalid.Status)
+	})
 }
 
 func TestBuilder_GenerateGenesis(t *testing.T) {
@@ -373,6 +426,54 @@ func TestBuilder_ValidateConfig(t *testing.T) {
 			wantErr: true,
 			errMsg:  "missing required field",
 		},
+		{
+			name:    "alpha exceeds k",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux", "parameters": {"k": 5, "alpha": 6, "beta": 1, "minBlockTime": "1s", "maxBlockTime": "2s"}}, "vm": {"type": "evm"}}`),
+			wantErr: true,
+			errMsg:  "$.consensus.parameters.alpha: 6 exceeds k (5)",
+		},
+		{
+			name:    "beta below 1",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux", "parameters": {"k": 5, "alpha": 3, "beta": 0, "minBlockTime": "1s", "maxBlockTime": "2s"}}, "vm": {"type": "evm"}}`),
+			wantErr: true,
+			errMsg:  "$.consensus.parameters.beta: 0 must be at least 1",
+		},
+		{
+			name:    "min block time above max",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux", "parameters": {"k": 5, "alpha": 3, "beta": 1, "minBlockTime": "3s", "maxBlockTime": "2s"}}, "vm": {"type": "evm"}}`),
+			wantErr: true,
+			errMsg:  "$.consensus.parameters.minBlockTime: 3s exceeds maxBlockTime (2s)",
+		},
+		{
+			name:    "min stake above max",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux"}, "vm": {"type": "evm"}, "network": {"minStake": 10, "maxStake": 5}}`),
+			wantErr: true,
+			errMsg:  "$.network.minStake: 10 exceeds maxStake (5)",
+		},
+		{
+			name:    "delegation fee out of range",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux"}, "vm": {"type": "evm"}, "network": {"minStake": 1, "maxStake": 5, "minDelegationFee": 101}}`),
+			wantErr: true,
+			errMsg:  "$.network.minDelegationFee: 101 exceeds 100%",
+		},
+		{
+			name:    "unsupported VM",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux"}, "vm": {"type": "jvm"}}`),
+			wantErr: true,
+			errMsg:  "$.vm.type: unsupported VM type jvm",
+		},
+		{
+			name:    "invalid EVM setting",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux"}, "vm": {"type": "evm", "config": {"log-level": "loud", "pruning-enabled": true}}}`),
+			wantErr: true,
+			errMsg:  "$.vm.config.log-level: must be one of trace, debug, info, warn, error, crit",
+		},
+		{
+			name:    "wrong type",
+			config:  []byte(`{"chainId": 1, "consensus": {"type": "lux", "parameters": {"k": "many"}}, "vm": {"type": "evm"}}`),
+			wantErr: true,
+			errMsg:  "$.consensus.parameters.k: expected int, got string",
+		},
 	}
 
 	for _, tt := range tests {
@@ -497,4 +598,141 @@ func TestBuilder_CreateChainConfig(t *testing.T) {
 	vmConfig, ok := vm["config"].(map[string]interface{})
 	assert.True(t, ok)
 	assert.Equal(t, float64(15000000), vmConfig["gasLimit"])
+
+	// chains created without a chain ID get the default one
+	blockchain, err = builder.CreateBlockchain(ctx, &CreateParams{
+		Name:   "default-chain-id",
+		Type:   TypeL1,
+		VMType: VMTypeEVM,
+	})
+	require.NoError(t, err)
+	require.NoError(t, builder.ValidateConfig(blockchain.ChainConfig))
+	var defaultConfig ChainConfig
+	require.NoError(t, json.Unmarshal(blockchain.ChainConfig, &defaultConfig))
+	assert.Equal(t, big.NewInt(DefaultChainID), defaultConfig.ChainID)
+}
+
+func TestBuilder_Snapshots(t *testing.T) {
+	builder := newTestBuilder()
+	ctx := context.Background()
+
+	blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:   "snapshot-test",
+		Type:   TypeL1,
+		VMType: VMTypeEVM,
+		Wallet: &fakePChainWallet{},
+	})
+	require.NoError(t, err)
+
+	// changes to returned values stay local until the builder records them
+	blockchain.Status = StatusRunning
+	got, err := builder.GetBlockchain(blockchain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCreated, got.Status)
+
+	got.Genesis[0] = 'x'
+	list := builder.ListBlockchains()
+	require.Len(t, list, 1)
+	assert.NotEqual(t, byte('x'), list[0].Genesis[0])
+
+	// Deploy records the outcome
+	require.NoError(t, builder.Deploy(ctx, blockchain, newTestNetwork()))
+	got, err = builder.GetBlockchain(blockchain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDeployed, got.Status)
+	assert.Equal(t, "test-network", got.NetworkID)
+	require.NotNil(t, got.DeployedAt)
+}
+
+func TestBuilder_ConcurrentUse(t *testing.T) {
+	builder := newTestBuilder()
+	ctx := context.Background()
+	testNetwork := newTestNetwork()
+	wallet := &fakePChainWallet{}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+
+			blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{
+				Name:    fmt.Sprintf("parallel-%d", i),
+				Type:    TypeL1,
+				VMType:  VMTypeEVM,
+				ChainID: big.NewInt(int64(1000 + i)),
+				Wallet:  wallet,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, builder.Deploy(ctx, blockchain, testNetwork))
+		}(i)
+		go func() {
+			defer wg.Done()
+
+			for _, blockchain := range builder.ListBlockchains() {
+				blockchain.Status = StatusError
+				_, err := builder.GetBlockchain(blockchain.ID)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	list := builder.ListBlockchains()
+	require.Len(t, list, workers)
+	for _, blockchain := range list {
+		assert.Equal(t, StatusDeployed, blockchain.Status)
+	}
+}
+
+func TestBuilder_Events(t *testing.T) {
+	builder := newTestBuilder()
+	bus := events.NewBus()
+	builder.SetEventBus(bus)
+	ch := bus.Subscribe(events.Filter{Kinds: []events.Kind{events.KindBlockchain}})
+	ctx := context.Background()
+
+	testNetwork := newTestNetwork()
+
+	deployed, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:    "events-test",
+		Type:    TypeL1,
+		VMType:  VMTypeEVM,
+		ChainID: big.NewInt(55555),
+		Wallet:  &fakePChainWallet{},
+	})
+	require.NoError(t, err)
+	require.NoError(t, builder.Deploy(ctx, deployed, testNetwork))
+
+	failed, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:   "events-error",
+		Type:   BlockchainType("invalid"),
+		VMType: VMTypeEVM,
+	})
+	require.NoError(t, err)
+	require.Error(t, builder.Deploy(ctx, failed, testNetwork))
+
+	transitions := []struct {
+		id       string
+		previous BlockchainStatus
+		status   BlockchainStatus
+	}{
+		{deployed.ID, "", StatusCreated},
+		{deployed.ID, StatusCreated, StatusDeploying},
+		{deployed.ID, StatusDeploying, StatusDeployed},
+		{failed.ID, "", StatusCreated},
+		{failed.ID, StatusCreated, StatusDeploying},
+		{failed.ID, StatusDeploying, StatusError},
+	}
+	for _, want := range transitions {
+		event := <-ch
+		assert.Equal(t, events.KindBlockchain, event.Kind)
+		assert.Equal(t, want.id, event.ID)
+		assert.Equal(t, string(want.previous), event.Previous)
+		assert.Equal(t, string(want.status), event.Status)
+	}
+	assert.Empty(t, ch)
 }
@@ -0,0 +1,352 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/constants"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/models"
+)
+
+// BundleVersion is the version of the bundles written by Export
+const BundleVersion = 1
+
+// Files of a bundle
+const (
+	bundleManifestFile   = "manifest.json"
+	bundleBlockchainFile = "blockchain.json"
+	bundleSidecarFile    = "sidecar.json"
+	bundleGenesisFile    = "genesis.json"
+	bundleConfigFile     = "config.json"
+	bundleUpgradeFile    = constants.UpgradeBytesFileName
+	bundleUpgradeLock    = constants.UpgradeBytesFileName + constants.UpgradeBytesLockExtension
+	bundleNodesDir       = "nodes"
+)
+
+// maxBundleFileSize bounds the size of a file read from a bundle
+const maxBundleFileSize = 64 << 20
+
+var (
+	// ErrUnsupportedBundle is returned when a bundle has an unknown version
+	ErrUnsupportedBundle = errors.New("unsupported bundle version")
+	// ErrInvalidBundle is returned when a bundle is malformed
+	ErrInvalidBundle = errors.New("invalid bundle")
+	// ErrChecksumMismatch is returned when a file of a bundle does not match
+	// its checksum in the manifest
+	ErrChecksumMismatch = errors.New("bundle checksum mismatch")
+)
+
+// BundleManifest lists the files of a bundle with their sha256 checksums
+type BundleManifest struct {
+	Version int          `json:"version"`
+	Name    string       `json:"name"`
+	ChainID string       `json:"chainId"`
+	Files   []BundleFile `json:"files"`
+}
+
+// BundleFile is a file of a bundle
+type BundleFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// bundleBlockchain is the definition of a blockchain stored in a bundle
+type bundleBlockchain struct {
+	Name         string         `json:"name"`
+	Type         BlockchainType `json:"type"`
+	VMType       VMType         `json:"vmType"`
+	VMID         string         `json:"vmId"`
+	ChainID      string         `json:"chainId"`
+	ValidatorSet []Validator    `json:"validatorSet,omitempty"`
+}
+
+// Export writes a bundle of a blockchain to w: a tar archive holding its
+// definition, sidecar, genesis, chain config, upgrade file, upgrade lock
+// and per-node configs, listed with their checksums in a manifest. Deployment state and
+// deploy-time settings such as wallets and keys are not exported.
+func (b *Builder) Export(blockchainID string, w io.Writer) error {
+	blockchain, err := b.GetBlockchain(blockchainID)
+	if err != nil {
+		return err
+	}
+
+	definition, err := json.Marshal(bundleBlockchain{
+		Name:         blockchain.Name,
+		Type:         blockchain.Type,
+		VMType:       blockchain.VMType,
+		VMID:         blockchain.VMID.CB58(),
+		ChainID:      blockchain.ChainID.CB58(),
+		ValidatorSet: blockchain.ValidatorSet,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode blockchain: %w", err)
+	}
+	files := map[string][]byte{
+		bundleBlockchainFile: definition,
+		bundleGenesisFile:    blockchain.Genesis,
+		bundleConfigFile:     blockchain.ChainConfig,
+	}
+	if blockchain.Sidecar != nil {
+		sidecar, err := json.Marshal(blockchain.Sidecar)
+		if err != nil {
+			return fmt.Errorf("failed to encode sidecar: %w", err)
+		}
+		files[bundleSidecarFile] = sidecar
+	}
+	if len(blockchain.Upgrade) > 0 {
+		files[bundleUpgradeFile] = blockchain.Upgrade
+	}
+	if len(blockchain.UpgradeLock) > 0 {
+		files[bundleUpgradeLock] = blockchain.UpgradeLock
+	}
+	for nodeID, config := range blockchain.PerNodeConfigs {
+		files[nodeConfigFile(nodeID)] = config
+	}
+
+	names := slices.Sorted(maps.Keys(files))
+	manifest := BundleManifest{
+		Version: BundleVersion,
+		Name:    blockchain.Name,
+		ChainID: blockchain.ChainID.CB58(),
+		Files:   make([]BundleFile, 0, len(names)),
+	}
+	for _, name := range names {
+		sum := sha256.Sum256(files[name])
+		manifest.Files = append(manifest.Files, BundleFile{
+			Name:   name,
+			Size:   int64(len(files[name])),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	// the manifest comes first, so readers learn the version before the files
+	tw := tar.NewWriter(w)
+	for _, name := range append([]string{bundleManifestFile}, names...) {
+		data := manifestBytes
+		if name != bundleManifestFile {
+			data = files[name]
+		}
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: time.Unix(0, 0),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return tw.Close()
+}
+
+// Import reads a bundle written by Export and registers its blockchain,
+// which is created anew from the bundled definition. Importing a blockchain
+// the builder already has returns the existing one, importing another
+// definition under its ID is an ErrBlockchainConflict. Bundles of an unknown
+// version, with files missing from or not matching the manifest are
+// rejected.
+func (b *Builder) Import(r io.Reader) (*Blockchain, error) {
+	files, err := readBundle(r)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestBytes, ok := files[bundleManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidBundle, bundleManifestFile)
+	}
+	delete(files, bundleManifestFile)
+	var manifest BundleManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode manifest: %w", ErrInvalidBundle, err)
+	}
+	if manifest.Version != BundleVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedBundle, manifest.Version)
+	}
+	if err := verifyBundle(&manifest, files); err != nil {
+		return nil, err
+	}
+
+	blockchain, err := bundledBlockchain(files)
+	if err != nil {
+		return nil, err
+	}
+	if blockchain.ChainID.CB58() != manifest.ChainID {
+		return nil, fmt.Errorf("%w: manifest chain ID %s does not match %s", ErrInvalidBundle, manifest.ChainID, blockchain.ChainID.CB58())
+	}
+	if existing, ok := b.insert(blockchain); !ok {
+		return b.recreated(existing, blockchain)
+	}
+	b.logger.Info("imported blockchain", "name", blockchain.Name, "id", blockchain.ID)
+	return blockchain, nil
+}
+
+// readBundle reads the files of a bundle
+func readBundle(r io.Reader) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: %s is not a regular file", ErrInvalidBundle, header.Name)
+		}
+		if header.Size > maxBundleFileSize {
+			return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrInvalidBundle, header.Name, maxBundleFileSize)
+		}
+		if _, ok := files[header.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate file %s", ErrInvalidBundle, header.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read %s: %w", ErrInvalidBundle, header.Name, err)
+		}
+		files[header.Name] = data
+	}
+}
+
+// verifyBundle checks the files of a bundle are the ones of its manifest
+func verifyBundle(manifest *BundleManifest, files map[string][]byte) error {
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		listed[file.Name] = true
+		data, ok := files[file.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing %s", ErrInvalidBundle, file.Name)
+		}
+		sum := sha256.Sum256(data)
+		if int64(len(data)) != file.Size || hex.EncodeToString(sum[:]) != file.SHA256 {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, file.Name)
+		}
+	}
+	for name := range files {
+		if !listed[name] {
+			return fmt.Errorf("%w: %s is not in the manifest", ErrInvalidBundle, name)
+		}
+	}
+	return nil
+}
+
+// bundledBlockchain builds the blockchain defined by the files of a bundle
+func bundledBlockchain(files map[string][]byte) (*Blockchain, error) {
+	var definition bundleBlockchain
+	if err := json.Unmarshal(files[bundleBlockchainFile], &definition); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode %s: %w", ErrInvalidBundle, bundleBlockchainFile, err)
+	}
+	decodedVMID, err := ids.FromString(definition.VMID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid VM ID: %w", ErrInvalidBundle, err)
+	}
+	vmID := types.ID(decodedVMID)
+	genesis, ok := files[bundleGenesisFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidBundle, bundleGenesisFile)
+	}
+
+	// the chain ID is derived again, so it always matches the genesis
+	chainID := ComputeChainID(genesis, vmID)
+	if chainID.CB58() != definition.ChainID {
+		return nil, fmt.Errorf("%w: chain ID %s does not match the genesis", ErrInvalidBundle, definition.ChainID)
+	}
+
+	blockchain := &Blockchain{
+		ID:           blockchainKey(definition.Name, chainID),
+		Name:         definition.Name,
+		Type:         definition.Type,
+		VMType:       definition.VMType,
+		ChainID:      chainID,
+		Genesis:      genesis,
+		ChainConfig:  files[bundleConfigFile],
+		Status:       StatusCreated,
+		CreatedAt:    time.Now(),
+		VMID:         vmID,
+		ValidatorSet: definition.ValidatorSet,
+		Upgrade:      files[bundleUpgradeFile],
+		UpgradeLock:  files[bundleUpgradeLock],
+	}
+	if sidecar, ok := files[bundleSidecarFile]; ok {
+		blockchain.Sidecar = &models.Sidecar{}
+		if err := json.Unmarshal(sidecar, blockchain.Sidecar); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode %s: %w", ErrInvalidBundle, bundleSidecarFile, err)
+		}
+	}
+
+	var nodeIDs []string
+	for name := range files {
+		if strings.HasPrefix(name, bundleNodesDir+"/") {
+			nodeIDs = append(nodeIDs, name)
+		}
+	}
+	sort.Strings(nodeIDs)
+	for _, name := range nodeIDs {
+		nodeID, ok := nodeIDFromConfigFile(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidBundle, name)
+		}
+		if blockchain.PerNodeConfigs == nil {
+			blockchain.PerNodeConfigs = make(map[string][]byte)
+		}
+		blockchain.PerNodeConfigs[nodeID] = files[name]
+	}
+	return blockchain, nil
+}
+
+// nodeConfigFile returns the bundle file of the config of a node
+func nodeConfigFile(nodeID string) string {
+	return path.Join(bundleNodesDir, nodeID, bundleConfigFile)
+}
+
+// nodeIDFromConfigFile returns the node of a bundled node config file
+func nodeIDFromConfigFile(name string) (string, bool) {
+	nodeID, ok := strings.CutPrefix(name, bundleNodesDir+"/")
+	if !ok {
+		return "", false
+	}
+	nodeID, ok = strings.CutSuffix(nodeID, "/"+bundleConfigFile)
+	if !ok || nodeID == "" || strings.ContainsAny(nodeID, `/\`) || nodeID == "." || nodeID == ".." {
+		return "", false
+	}
+	return nodeID, true
+}
+
+// clonePerNodeConfigs returns a deep copy of per-node configs
+func clonePerNodeConfigs(configs map[string][]byte) map[string][]byte {
+	if configs == nil {
+		return nil
+	}
+	c := make(map[string][]byte, len(configs))
+	for nodeID, config := range configs {
+		c[nodeID] = bytes.Clone(config)
+	}
+	return c
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBundledBlockchain creates a blockchain with every bundled file set
+func newBundledBlockchain(t *testing.T, builder *Builder) *Blockchain {
+	t.Helper()
+	blockchain, err := builder.CreateBlockchain(context.Background(), &CreateParams{
+		Name:    "bundled",
+		Type:    TypeL1,
+		VMType:  VMTypeEVM,
+		Sidecar: &models.Sidecar{Name: "bundled", TokenSymbol: "BND"},
+		PerNodeConfigs: map[string][]byte{
+			"NodeID-1": []byte(`{"log-level":"debug"}`),
+			"NodeID-2": []byte(`{"log-level":"info"}`),
+		},
+	})
+	require.NoError(t, err)
+	blockchain.ChainConfig = []byte(`{"pruning-enabled":false}`)
+	blockchain.Upgrade = []byte(`{"precompileUpgrades":[{"feeManagerConfig":{"blockTimestamp":1}}]}`)
+	blockchain.UpgradeLock = []byte(`{"precompileUpgrades":[]}`)
+	builder.store(blockchain)
+	return blockchain
+}
+
+// readTar returns the files of a tar archive in order
+func readTar(t *testing.T, bundle []byte) ([]string, map[string][]byte) {
+	t.Helper()
+	var names []string
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(bundle))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names, files
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		names = append(names, header.Name)
+		files[header.Name] = data
+	}
+}
+
+// writeTar writes files to a tar archive in order
+func writeTar(t *testing.T, names []string, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))}))
+		_, err := tw.Write(files[name])
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestBuilder_ExportImport(t *testing.T) {
+	source := NewBuilder(log.NewNoOpLogger())
+	blockchain := newBundledBlockchain(t, source)
+
+	var bundle bytes.Buffer
+	require.NoError(t, source.Export(blockchain.ID, &bundle))
+
+	names, files := readTar(t, bundle.Bytes())
+	assert.Equal(t, []string{
+		"manifest.json",
+		"blockchain.json",
+		"config.json",
+		"genesis.json",
+		"nodes/NodeID-1/config.json",
+		"nodes/NodeID-2/config.json",
+		"sidecar.json",
+		"upgrade.json",
+		"upgrade.json.lock",
+	}, names)
+	var manifest BundleManifest
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, BundleVersion, manifest.Version)
+	assert.Equal(t, blockchain.ChainID.CB58(), manifest.ChainID)
+	assert.Len(t, manifest.Files, len(names)-1)
+
+	// exports are deterministic
+	var again bytes.Buffer
+	require.NoError(t, source.Export(blockchain.ID, &again))
+	assert.Equal(t, bundle.Bytes(), again.Bytes())
+
+	target := NewBuilder(log.NewNoOpLogger())
+	imported, err := target.Import(bytes.NewReader(bundle.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, blockchain.ID, imported.ID)
+	assert.Equal(t, blockchain.Name, imported.Name)
+	assert.Equal(t, blockchain.Type, imported.Type)
+	assert.Equal(t, blockchain.VMType, imported.VMType)
+	assert.Equal(t, blockchain.VMID, imported.VMID)
+	assert.Equal(t, blockchain.ChainID, imported.ChainID)
+	assert.Equal(t, blockchain.Genesis, imported.Genesis)
+	assert.Equal(t, blockchain.ChainConfig, imported.ChainConfig)
+	assert.Equal(t, blockchain.Upgrade, imported.Upgrade)
+	assert.Equal(t, blockchain.Sidecar, imported.Sidecar)
+	assert.Equal(t, blockchain.PerNodeConfigs, imported.PerNodeConfigs)
+	assert.Equal(t, StatusCreated, imported.Status)
+	assert.Equal(t, blockchain.UpgradeLock, imported.UpgradeLock)
+
+	stored, err := target.GetBlockchain(imported.ID)
+	require.NoError(t, err)
+	assert.Equal(t, imported.Genesis, stored.Genesis)
+
+	reimported, err := target.Import(bytes.NewReader(bundle.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, imported.ID, reimported.ID)
+	assert.Len(t, target.ListBlockchains(), 1)
+}
+
+func TestBuilder_ImportErrors(t *testing.T) {
+	source := NewBuilder(log.NewNoOpLogger())
+	blockchain := newBundledBlockchain(t, source)
+	var bundle bytes.Buffer
+	require.NoError(t, source.Export(blockchain.ID, &bundle))
+
+	tests := []struct {
+		name   string
+		modify func(names []string, files map[string][]byte) []string
+		err    error
+		errMsg string
+	}{
+		{
+			name: "tampered genesis",
+			modify: func(names []string, files map[string][]byte) []string {
+				files["genesis.json"] = append(files["genesis.json"], ' ')
+				return names
+			},
+			err:    ErrChecksumMismatch,
+			errMsg: "genesis.json",
+		},
+		{
+			name: "tampered node config",
+			modify: func(names []string, files map[string][]byte) []string {
+				files["nodes/NodeID-2/config.json"] = []byte(`{"log-level":"trace"}`)
+				return names
+			},
+			err: ErrChecksumMismatch,
+		},
+		{
+			name: "unsupported version",
+			modify: func(names []string, files map[string][]byte) []string {
+				files["manifest.json"] = bytes.Replace(files["manifest.json"], []byte(`"version": 1`), []byte(`"version": 2`), 1)
+				return names
+			},
+			err: ErrUnsupportedBundle,
+		},
+		{
+			name: "missing manifest",
+			modify: func(names []string, _ map[string][]byte) []string {
+				return names[1:]
+			},
+			err:    ErrInvalidBundle,
+			errMsg: "missing manifest.json",
+		},
+		{
+			name: "missing file",
+			modify: func(names []string, _ map[string][]byte) []string {
+				return names[:len(names)-1]
+			},
+			err:    ErrInvalidBundle,
+			errMsg: "missing upgrade.json",
+		},
+		{
+			name: "unlisted file",
+			modify: func(names []string, files map[string][]byte) []string {
+				files["nodes/../../etc/config.json"] = []byte("{}")
+				return append(names, "nodes/../../etc/config.json")
+			},
+			err:    ErrInvalidBundle,
+			errMsg: "not in the manifest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, files := readTar(t, bundle.Bytes())
+			names = tt.modify(names, files)
+
+			target := NewBuilder(log.NewNoOpLogger())
+			_, err := target.Import(bytes.NewReader(writeTar(t, names, files)))
+			assert.ErrorIs(t, err, tt.err)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+			assert.Empty(t, target.ListBlockchains())
+		})
+	}
+
+	t.Run("not a tar archive", func(t *testing.T) {
+		_, err := NewBuilder(log.NewNoOpLogger()).Import(bytes.NewReader([]byte("not a bundle")))
+		assert.ErrorIs(t, err, ErrInvalidBundle)
+	})
+}
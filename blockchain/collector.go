@@ -0,0 +1,330 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/evm"
+)
+
+const (
+	// tpsWindow is the number of recent blocks TPS is computed over
+	tpsWindow = 10
+	// maxHeadBackfill bounds the blocks recorded when heads were missed
+	maxHeadBackfill = 128
+)
+
+// metricsPollInterval is the interval between peer count updates, and
+// between height checks of chains served without websockets
+var metricsPollInterval = 2 * time.Second
+
+var (
+	// ErrNoRPCEndpoint is returned when a blockchain has no RPC endpoint
+	ErrNoRPCEndpoint = errors.New("blockchain has no RPC endpoint")
+	// ErrNoMetrics is returned when no metrics were collected for a blockchain
+	ErrNoMetrics = errors.New("no metrics collected")
+	// ErrMetricsCollecting is returned when metrics of a blockchain are
+	// already being collected
+	ErrMetricsCollecting = errors.New("metrics already being collected")
+)
+
+// chainClient is the part of evm.Client metrics are collected with
+type chainClient interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	BlockNumber() (uint64, error)
+	HeaderByNumber(n *big.Int) (*types.Header, error)
+	BlockReceipts(n uint64) ([]*types.Receipt, error)
+	PeerCount() (uint64, error)
+	Close()
+}
+
+// chainDialer connects to the RPC endpoint of a chain
+type chainDialer func(rpcURL string) (chainClient, error)
+
+// dialEVMChain connects an evm.Client to the websocket endpoint of a chain,
+// so its new heads can be subscribed to. The client falls back to the RPC
+// endpoint, and to polling, when the websocket endpoint cannot be dialed.
+func dialEVMChain(rpcURL string) (chainClient, error) {
+	if wsURL, ok := websocketURL(rpcURL); ok {
+		if client, err := evm.GetClient(wsURL); err == nil {
+			return client, nil
+		}
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// websocketURL returns the websocket endpoint of a chain served over HTTP:
+// the /ws endpoint next to its /rpc one, as nodes serve them, or the same
+// path otherwise
+func websocketURL(rpcURL string) (string, bool) {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return "", false
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return "", false
+	}
+	if path, ok := strings.CutSuffix(u.Path, "/rpc"); ok {
+		u.Path = path + "/ws"
+	}
+	return u.String(), true
+}
+
+// CollectMetrics follows the new heads of a deployed blockchain and records
+// its blocks, transactions and peers in the metrics of the blockchain until
+// ctx is done. Heads are subscribed to over websockets and polled for
+// otherwise. Blocks produced before collection starts are not recorded.
+func (b *Builder) CollectMetrics(ctx context.Context, blockchainID string) (*Metrics, error) {
+	blockchain, err := b.GetBlockchain(blockchainID)
+	if err != nil {
+		return nil, err
+	}
+	if blockchain.RPCURL == "" {
+		return nil, fmt.Errorf("failed to collect metrics of %s: %w", blockchain.Name, ErrNoRPCEndpoint)
+	}
+
+	b.mu.Lock()
+	if _, ok := b.collectors[blockchain.ID]; ok {
+		b.mu.Unlock()
+		return nil, fmt.Errorf("blockchain %s: %w", blockchain.Name, ErrMetricsCollecting)
+	}
+	b.collectors[blockchain.ID] = struct{}{}
+	metrics, ok := b.metrics[blockchain.ID]
+	if !ok {
+		metrics = NewMetrics()
+		b.metrics[blockchain.ID] = metrics
+	}
+	b.mu.Unlock()
+
+	collector, err := b.newMetricsCollector(blockchain, metrics)
+	if err != nil {
+		b.stopCollecting(blockchain.ID)
+		return nil, err
+	}
+	go func() {
+		defer b.stopCollecting(blockchain.ID)
+		collector.run(ctx)
+	}()
+	return metrics, nil
+}
+
+// Metrics returns the metrics collected for a blockchain
+func (b *Builder) Metrics(blockchainID string) (*Metrics, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	metrics, ok := b.metrics[blockchainID]
+	if !ok {
+		return nil, fmt.Errorf("blockchain %s: %w", blockchainID, ErrNoMetrics)
+	}
+	return metrics, nil
+}
+
+// stopCollecting records the metrics of a blockchain are no longer collected
+func (b *Builder) stopCollecting(blockchainID string) {
+	b.mu.Lock()
+	delete(b.collectors, blockchainID)
+	b.mu.Unlock()
+}
+
+// blockSample is a recorded block TPS is computed from
+type blockSample struct {
+	time time.Time
+	txs  int
+}
+
+// metricsCollector records the blocks of a chain in its metrics
+type metricsCollector struct {
+	logger  log.Logger
+	name    string
+	client  chainClient
+	metrics *Metrics
+	// pollInterval is the interval between peer count updates and polls
+	pollInterval time.Duration
+
+	// last is the height of the last recorded block
+	last uint64
+	// window holds the last tpsWindow recorded blocks
+	window []blockSample
+}
+
+// newMetricsCollector connects to a blockchain and starts from its current
+// head
+func (b *Builder) newMetricsCollector(blockchain *Blockchain, metrics *Metrics) (*metricsCollector, error) {
+	client, err := b.dialChain(blockchain.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", blockchain.Name, err)
+	}
+	height, err := client.BlockNumber()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	head, err := client.HeaderByNumber(new(big.Int).SetUint64(height))
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &metricsCollector{
+		logger:       b.logger,
+		name:         blockchain.Name,
+		client:       client,
+		metrics:      metrics,
+		pollInterval: metricsPollInterval,
+		last:         height,
+		window:       []blockSample{{time: headerTime(head)}},
+	}, nil
+}
+
+// run records new heads until ctx is done. It polls for them when the
+// client cannot subscribe, or once the subscription fails.
+func (c *metricsCollector) run(ctx context.Context) {
+	defer c.client.Close()
+
+	heads := make(chan *types.Header, tpsWindow)
+	sub, err := c.client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		c.logger.Debug("polling for new heads", "chain", c.name, "error", err)
+		sub = nil
+	}
+	var subErr <-chan error
+	if sub != nil {
+		subErr = sub.Err()
+	}
+	defer func() {
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+	}()
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	c.updateNetwork()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case head := <-heads:
+			c.recordHead(head)
+		case err := <-subErr:
+			c.logger.Warn("new heads subscription failed, polling", "chain", c.name, "error", err)
+			sub.Unsubscribe()
+			sub, subErr = nil, nil
+		case <-ticker.C:
+			if sub == nil {
+				c.poll()
+			}
+			c.updateNetwork()
+		}
+	}
+}
+
+// poll records the blocks produced since the last recorded one
+func (c *metricsCollector) poll() {
+	height, err := c.client.BlockNumber()
+	if err != nil {
+		c.logger.Warn("failed to poll height", "chain", c.name, "error", err)
+		return
+	}
+	if height <= c.last {
+		return
+	}
+	head, err := c.client.HeaderByNumber(new(big.Int).SetUint64(height))
+	if err != nil {
+		c.logger.Warn("failed to fetch head", "chain", c.name, "height", height, "error", err)
+		return
+	}
+	c.recordHead(head)
+}
+
+// recordHead records a new head, and the blocks before it that were missed
+func (c *metricsCollector) recordHead(head *types.Header) {
+	height := head.Number.Uint64()
+	if height <= c.last {
+		return
+	}
+	from := c.last + 1
+	if height-from > maxHeadBackfill {
+		c.logger.Warn("skipping missed blocks", "chain", c.name, "from", from, "to", height-maxHeadBackfill-1)
+		from = height - maxHeadBackfill
+	}
+	for n := from; n < height; n++ {
+		header, err := c.client.HeaderByNumber(new(big.Int).SetUint64(n))
+		if err != nil {
+			c.logger.Warn("failed to fetch header", "chain", c.name, "height", n, "error", err)
+			return
+		}
+		if !c.recordBlock(header) {
+			return
+		}
+	}
+	c.recordBlock(head)
+}
+
+// recordBlock records a block and its transactions, reporting whether it
+// succeeded
+func (c *metricsCollector) recordBlock(header *types.Header) bool {
+	height := header.Number.Uint64()
+	receipts, err := c.client.BlockReceipts(height)
+	if err != nil {
+		c.logger.Warn("failed to fetch receipts", "chain", c.name, "height", height, "error", err)
+		return false
+	}
+	for _, receipt := range receipts {
+		c.metrics.RecordTransaction(receipt.Status == types.ReceiptStatusSuccessful)
+	}
+	blockTime := headerTime(header)
+	c.metrics.RecordBlock(blockTime)
+	c.last = height
+
+	c.window = append(c.window, blockSample{time: blockTime, txs: len(receipts)})
+	if len(c.window) > tpsWindow {
+		c.window = c.window[len(c.window)-tpsWindow:]
+	}
+	// the txs of the oldest block were produced before the window started
+	var txs int
+	for _, sample := range c.window[1:] {
+		txs += sample.txs
+	}
+	if elapsed := blockTime.Sub(c.window[0].time); elapsed > 0 {
+		c.metrics.UpdateTPS(float64(txs) / elapsed.Seconds())
+	}
+	return true
+}
+
+// updateNetwork records the peers of the node serving the chain, and the
+// latency of the request
+func (c *metricsCollector) updateNetwork() {
+	start := time.Now()
+	peers, err := c.client.PeerCount()
+	if err != nil {
+		c.logger.Debug("failed to fetch peer count", "chain", c.name, "error", err)
+		return
+	}
+	c.metrics.UpdateNetwork(int(peers), time.Since(start))
+}
+
+// headerTime returns the timestamp of a block
+func headerTime(header *types.Header) time.Time {
+	return time.Unix(int64(header.Time), 0)
+}
@@ -0,0 +1,382 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/luxfi/geth"
+	"github.com/luxfi/geth/common/hexutil"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rpc"
+	"github.com/luxfi/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const genesisTime = 1700000000
+
+// fakeSubscription is a new heads subscription of a fakeRPCChain
+type fakeSubscription struct {
+	errc chan error
+	once sync.Once
+}
+
+func (s *fakeSubscription) Unsubscribe() {
+	s.once.Do(func() { close(s.errc) })
+}
+
+func (s *fakeSubscription) Err() <-chan error {
+	return s.errc
+}
+
+// fakeRPCChain serves blocks to the metrics collector
+type fakeRPCChain struct {
+	mu        sync.Mutex
+	websocket bool
+	headers   []*types.Header
+	receipts  map[uint64][]*types.Receipt
+	peers     uint64
+	heads     chan<- *types.Header
+	sub       *fakeSubscription
+	closed    bool
+}
+
+func newFakeRPCChain(websocket bool) *fakeRPCChain {
+	return &fakeRPCChain{
+		websocket: websocket,
+		headers:   []*types.Header{{Number: big.NewInt(0), Time: genesisTime}},
+		receipts:  make(map[uint64][]*types.Receipt),
+		peers:     4,
+	}
+}
+
+// addBlock adds a block produced after the seconds, with a tx of each status
+func (c *fakeRPCChain) addBlock(seconds uint64, statuses ...uint64) *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := &types.Header{Number: big.NewInt(int64(len(c.headers))), Time: genesisTime + seconds}
+	c.headers = append(c.headers, header)
+	for _, status := range statuses {
+		c.receipts[header.Number.Uint64()] = append(c.receipts[header.Number.Uint64()], &types.Receipt{Status: status})
+	}
+	return header
+}
+
+func (c *fakeRPCChain) SubscribeNewHead(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.websocket {
+		return nil, errors.New("notifications not supported")
+	}
+	c.heads = ch
+	c.sub = &fakeSubscription{errc: make(chan error, 1)}
+	return c.sub, nil
+}
+
+func (c *fakeRPCChain) BlockNumber() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *fakeRPCChain) HeaderByNumber(n *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n.Uint64() >= uint64(len(c.headers)) {
+		return nil, errors.New("not found")
+	}
+	return c.headers[n.Uint64()], nil
+}
+
+func (c *fakeRPCChain) BlockReceipts(n uint64) ([]*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receipts[n], nil
+}
+
+func (c *fakeRPCChain) PeerCount() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.peers, nil
+}
+
+func (c *fakeRPCChain) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+}
+
+func (c *fakeRPCChain) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// collectedMetrics are the collected values of a Metrics
+type collectedMetrics struct {
+	blocks uint64
+	txs    uint64
+	failed uint64
+	tps    float64
+	peers  int
+}
+
+func readMetrics(m *Metrics) collectedMetrics {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return collectedMetrics{
+		blocks: m.BlocksProduced,
+		txs:    m.TxProcessed,
+		failed: m.TxFailed,
+		tps:    m.TPS,
+		peers:  m.PeersConnected,
+	}
+}
+
+// newCollectedBuilder returns a builder serving a deployed chain from the
+// fake chain
+func newCollectedBuilder(t *testing.T, chain *fakeRPCChain) (*Builder, *Blockchain) {
+	t.Helper()
+	builder := NewBuilder(log.NewNoOpLogger())
+	builder.dialChain = func(string) (chainClient, error) {
+		return chain, nil
+	}
+	blockchain, err := builder.CreateBlockchain(context.Background(), &CreateParams{Name: "metered", Type: TypeL1, VMType: VMTypeEVM})
+	require.NoError(t, err)
+	blockchain.Status = StatusRunning
+	blockchain.RPCURL = "ws://127.0.0.1:9650/ext/bc/metered/ws"
+	builder.store(blockchain)
+	return builder, blockchain
+}
+
+func TestBuilder_CollectMetricsSubscription(t *testing.T) {
+	chain := newFakeRPCChain(true)
+	builder, blockchain := newCollectedBuilder(t, chain)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	metrics, err := builder.CollectMetrics(ctx, blockchain.ID)
+	require.NoError(t, err)
+
+	// block 1 was missed by the subscription and is fetched with block 2
+	chain.addBlock(2, types.ReceiptStatusSuccessful, types.ReceiptStatusFailed)
+	head := chain.addBlock(4, types.ReceiptStatusSuccessful)
+	require.Eventually(t, func() bool {
+		chain.mu.Lock()
+		defer chain.mu.Unlock()
+		return chain.heads != nil
+	}, time.Second, time.Millisecond)
+	chain.heads <- head
+
+	require.Eventually(t, func() bool {
+		return readMetrics(metrics).blocks == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, collectedMetrics{blocks: 2, txs: 2, failed: 1, tps: 0.75, peers: 4}, readMetrics(metrics))
+	stored, err := builder.Metrics(blockchain.ID)
+	require.NoError(t, err)
+	assert.Same(t, metrics, stored)
+
+	_, err = builder.CollectMetrics(ctx, blockchain.ID)
+	assert.ErrorIs(t, err, ErrMetricsCollecting)
+
+	// collection can be restarted once stopped
+	cancel()
+	require.Eventually(t, chain.isClosed, time.Second, time.Millisecond)
+	restart, stop := context.WithCancel(context.Background())
+	defer stop()
+	require.Eventually(t, func() bool {
+		_, err := builder.CollectMetrics(restart, blockchain.ID)
+		return !errors.Is(err, ErrMetricsCollecting)
+	}, time.Second, time.Millisecond)
+}
+
+func TestBuilder_CollectMetricsPolling(t *testing.T) {
+	original := metricsPollInterval
+	metricsPollInterval = time.Millisecond
+	defer func() {
+		metricsPollInterval = original
+	}()
+
+	tests := []struct {
+		name      string
+		websocket bool
+	}{
+		{name: "without websocket"},
+		{name: "after subscription failure", websocket: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newFakeRPCChain(tt.websocket)
+			builder, blockchain := newCollectedBuilder(t, chain)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			metrics, err := builder.CollectMetrics(ctx, blockchain.ID)
+			require.NoError(t, err)
+			if tt.websocket {
+				require.Eventually(t, func() bool {
+					chain.mu.Lock()
+					defer chain.mu.Unlock()
+					return chain.sub != nil
+				}, time.Second, time.Millisecond)
+				chain.sub.errc <- errors.New("connection reset")
+			}
+
+			for i := uint64(1); i <= 4; i++ {
+				chain.addBlock(i, types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful)
+			}
+			require.Eventually(t, func() bool {
+				return readMetrics(metrics).blocks == 4
+			}, time.Second, time.Millisecond)
+			assert.Equal(t, collectedMetrics{blocks: 4, txs: 8, tps: 2, peers: 4}, readMetrics(metrics))
+
+			cancel()
+			require.Eventually(t, chain.isClosed, time.Second, time.Millisecond)
+		})
+	}
+}
+
+func TestBuilder_CollectMetricsErrors(t *testing.T) {
+	builder, blockchain := newCollectedBuilder(t, newFakeRPCChain(false))
+
+	_, err := builder.CollectMetrics(context.Background(), "missing")
+	assert.ErrorContains(t, err, "blockchain missing not found")
+
+	pending, err := builder.CreateBlockchain(context.Background(), &CreateParams{Name: "pending", Type: TypeL1, VMType: VMTypeEVM})
+	require.NoError(t, err)
+	_, err = builder.CollectMetrics(context.Background(), pending.ID)
+	assert.ErrorIs(t, err, ErrNoRPCEndpoint)
+
+	builder.dialChain = func(string) (chainClient, error) {
+		return nil, errors.New("connection refused")
+	}
+	_, err = builder.CollectMetrics(context.Background(), blockchain.ID)
+	assert.ErrorContains(t, err, "failed to connect to metered: connection refused")
+
+	// a failed start does not hold the blockchain
+	builder.dialChain = func(string) (chainClient, error) {
+		return newFakeRPCChain(false), nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = builder.CollectMetrics(ctx, blockchain.ID)
+	require.NoError(t, err)
+
+	_, err = builder.Metrics(pending.ID)
+	assert.ErrorIs(t, err, ErrNoMetrics)
+}
+
+// newHeadsService serves the height and new heads of a chain over JSON-RPC
+type newHeadsService struct {
+	head *types.Header
+}
+
+func (s *newHeadsService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.head.Number.Uint64())
+}
+
+func (s *newHeadsService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		_ = notifier.Notify(sub.ID, s.head)
+	}()
+	return sub, nil
+}
+
+// newRPCServer serves a chain at /ext/bc/chain/rpc and, with websocket,
+// at /ext/bc/chain/ws, as nodes do
+func newRPCServer(t *testing.T, head *types.Header, websocket bool) string {
+	t.Helper()
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &newHeadsService{head: head}))
+	mux := http.NewServeMux()
+	mux.Handle("/ext/bc/chain/rpc", server)
+	if websocket {
+		mux.Handle("/ext/bc/chain/ws", server.WebsocketHandler([]string{"*"}))
+	}
+	httpServer := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL + "/ext/bc/chain/rpc"
+}
+
+func TestDialEVMChain(t *testing.T) {
+	head := &types.Header{Number: big.NewInt(7), Time: genesisTime, Difficulty: big.NewInt(0)}
+
+	t.Run("websocket", func(t *testing.T) {
+		client, err := dialEVMChain(newRPCServer(t, head, true))
+		require.NoError(t, err)
+		defer client.Close()
+
+		height, err := client.BlockNumber()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), height)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		heads := make(chan *types.Header, 1)
+		sub, err := client.SubscribeNewHead(ctx, heads)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+		select {
+		case received := <-heads:
+			assert.Equal(t, head.Number, received.Number)
+			assert.Equal(t, head.Time, received.Time)
+		case err := <-sub.Err():
+			require.NoError(t, err)
+		case <-ctx.Done():
+			require.FailNow(t, "no new head received")
+		}
+	})
+
+	t.Run("polling", func(t *testing.T) {
+		client, err := dialEVMChain(newRPCServer(t, head, false))
+		require.NoError(t, err)
+		defer client.Close()
+
+		// without a websocket endpoint the client polls over HTTP
+		height, err := client.BlockNumber()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), height)
+		_, err = client.SubscribeNewHead(context.Background(), make(chan *types.Header))
+		assert.ErrorContains(t, err, rpc.ErrNotificationsUnsupported.Error())
+	})
+}
+
+func TestWebsocketURL(t *testing.T) {
+	tests := []struct {
+		rpcURL string
+		wsURL  string
+	}{
+		{rpcURL: "http://127.0.0.1:9650/ext/bc/C/rpc", wsURL: "ws://127.0.0.1:9650/ext/bc/C/ws"},
+		{rpcURL: "https://api.lux.network/ext/bc/2Xk/rpc", wsURL: "wss://api.lux.network/ext/bc/2Xk/ws"},
+		{rpcURL: "http://127.0.0.1:8545", wsURL: "ws://127.0.0.1:8545"},
+		{rpcURL: "ws://127.0.0.1:9650/ext/bc/C/ws"},
+		{rpcURL: "127.0.0.1:9650"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rpcURL, func(t *testing.T) {
+			wsURL, ok := websocketURL(tt.rpcURL)
+			assert.Equal(t, tt.wsURL != "", ok)
+			assert.Equal(t, tt.wsURL, wsURL)
+		})
+	}
+}
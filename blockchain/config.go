@@ -0,0 +1,293 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luxfi/sdk/chainconfig"
+)
+
+// MaxDelegationFee is the highest delegation fee, in percent
+const MaxDelegationFee = 100
+
+// ChainConfig is the chain configuration of a blockchain
+type ChainConfig struct {
+	ChainID   *big.Int        `json:"chainId"`
+	Consensus ConsensusConfig `json:"consensus"`
+	VM        VMConfig        `json:"vm"`
+	Network   *StakingConfig  `json:"network,omitempty"`
+}
+
+// ConsensusConfig selects and tunes the consensus engine
+type ConsensusConfig struct {
+	Type       string               `json:"type"`
+	Parameters *ConsensusParameters `json:"parameters,omitempty"`
+}
+
+// ConsensusParameters are the sampling parameters of the consensus engine
+type ConsensusParameters struct {
+	// K is the number of validators sampled per poll
+	K int `json:"k"`
+	// Alpha is the number of votes of a poll needed to change preference
+	Alpha int `json:"alpha"`
+	// Beta is the number of consecutive successful polls needed to finalize
+	Beta         int      `json:"beta"`
+	MaxBlockTime Duration `json:"maxBlockTime"`
+	MinBlockTime Duration `json:"minBlockTime"`
+}
+
+// VMConfig selects the VM of the chain and holds its settings
+type VMConfig struct {
+	Type   VMType                 `json:"type"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// StakingConfig bounds the stake of the chain's validators and delegators
+type StakingConfig struct {
+	MinStake      uint64 `json:"minStake"`
+	MaxStake      uint64 `json:"maxStake"`
+	MinDelegation uint64 `json:"minDelegation"`
+	// MinDelegationFee is the lowest fee, in percent, a validator may charge
+	MinDelegationFee uint64 `json:"minDelegationFee"`
+}
+
+// Duration is a time.Duration encoded as a string such as "10s"
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// FieldError is a validation error located by the JSON path of the
+// offending field
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+func fieldError(path, format string, args ...any) error {
+	return &FieldError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+// validateChainConfig validates a chain configuration against its schema
+func validateChainConfig(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("invalid configuration format: %w", err)
+	}
+
+	var errs []error
+	for _, field := range []string{"chainId", "consensus", "vm"} {
+		if _, ok := fields[field]; !ok {
+			errs = append(errs, fieldError("$", "missing required field: %s", field))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	var config ChainConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fieldError("$."+typeErr.Field, "expected %s, got %s", typeErr.Type, typeErr.Value)
+		}
+		return fmt.Errorf("invalid configuration format: %w", err)
+	}
+	return config.Validate()
+}
+
+// Validate checks the consensus parameters, the staking bounds and the VM
+// section of the configuration
+func (c *ChainConfig) Validate() error {
+	var errs []error
+	if c.ChainID == nil || c.ChainID.Sign() <= 0 {
+		errs = append(errs, fieldError("$.chainId", "must be a positive integer"))
+	}
+
+	if c.Consensus.Type == "" {
+		errs = append(errs, fieldError("$.consensus.type", "missing"))
+	}
+	if p := c.Consensus.Parameters; p != nil {
+		const path = "$.consensus.parameters"
+		if p.K < 1 {
+			errs = append(errs, fieldError(path+".k", "%d must be at least 1", p.K))
+		}
+		switch {
+		case p.Alpha > p.K:
+			errs = append(errs, fieldError(path+".alpha", "%d exceeds k (%d)", p.Alpha, p.K))
+		case p.Alpha <= p.K/2:
+			errs = append(errs, fieldError(path+".alpha", "%d must be a majority of k (%d)", p.Alpha, p.K))
+		}
+		if p.Beta < 1 {
+			errs = append(errs, fieldError(path+".beta", "%d must be at least 1", p.Beta))
+		}
+		if p.MinBlockTime < 0 {
+			errs = append(errs, fieldError(path+".minBlockTime", "must not be negative"))
+		}
+		if p.MinBlockTime > p.MaxBlockTime {
+			errs = append(errs, fieldError(path+".minBlockTime", "%s exceeds maxBlockTime (%s)",
+				time.Duration(p.MinBlockTime), time.Duration(p.MaxBlockTime)))
+		}
+	}
+
+	if s := c.Network; s != nil {
+		const path = "$.network"
+		if s.MinStake == 0 {
+			errs = append(errs, fieldError(path+".minStake", "must be positive"))
+		}
+		if s.MinStake > s.MaxStake {
+			errs = append(errs, fieldError(path+".minStake", "%d exceeds maxStake (%d)", s.MinStake, s.MaxStake))
+		}
+		if s.MinDelegationFee > MaxDelegationFee {
+			errs = append(errs, fieldError(path+".minDelegationFee", "%d exceeds %d%%", s.MinDelegationFee, MaxDelegationFee))
+		}
+	}
+
+	errs = append(errs, c.VM.validate())
+	return errors.Join(errs...)
+}
+
+// evmConfigKeys type checks the settings of the EVM config section known
+// to subnet-EVM; other settings are passed through unchecked
+var evmConfigKeys = map[string]func(value any) error{
+	"pruning-enabled": func(value any) error {
+		if _, ok := value.(bool); !ok {
+			return errors.New("must be a boolean")
+		}
+		return nil
+	},
+	"eth-apis": func(value any) error {
+		apis, ok := value.([]any)
+		if !ok {
+			return errors.New("must be a list of API names")
+		}
+		for _, api := range apis {
+			if _, ok := api.(string); !ok {
+				return errors.New("must be a list of API names")
+			}
+		}
+		return nil
+	},
+	"log-level": func(value any) error {
+		levels := []string{"trace", "debug", "info", "warn", "error", "crit"}
+		if level, ok := value.(string); !ok || !slices.Contains(levels, level) {
+			return fmt.Errorf("must be one of %s", strings.Join(levels, ", "))
+		}
+		return nil
+	},
+	"rpc-gas-cap": func(value any) error {
+		if gasCap, ok := value.(float64); !ok || gasCap <= 0 {
+			return errors.New("must be a positive number")
+		}
+		return nil
+	},
+}
+
+// validate checks the VM type and the VM specific settings
+func (c VMConfig) validate() error {
+	switch c.Type {
+	case VMTypeEVM:
+		var errs []error
+		keys := make([]string, 0, len(c.Config))
+		for key := range c.Config {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if check, ok := evmConfigKeys[key]; ok {
+				if err := check(c.Config[key]); err != nil {
+					errs = append(errs, fieldError("$.vm.config."+key, "%s", err))
+				}
+			}
+		}
+		return errors.Join(errs...)
+	case VMTypeWASM, VMTypeTokenVM, VMTypeMorpheusVM, VMTypeCustom:
+		return nil
+	case "":
+		return fieldError("$.vm.type", "missing")
+	default:
+		return fieldError("$.vm.type", "unsupported VM type %s", c.Type)
+	}
+}
+
+// validateGenesis validates the genesis of a VM against its schema
+func validateGenesis(vmType VMType, genesis []byte) error {
+	var err error
+	switch vmType {
+	case VMTypeEVM:
+		err = validateEVMGenesis(genesis)
+	case VMTypeTokenVM:
+		_, err = ParseTokenVMGenesis(genesis)
+	case VMTypeMorpheusVM:
+		_, err = ParseMorpheusVMGenesis(genesis)
+	case VMTypeWASM:
+		_, err = ParseWASMGenesis(genesis)
+	case VMTypeCustom:
+		// custom VMs define their own genesis format
+		if len(genesis) == 0 {
+			err = fmt.Errorf("%w: empty genesis", ErrInvalidGenesis)
+		}
+	default:
+		err = fmt.Errorf("unsupported VM type: %s", vmType)
+	}
+	return err
+}
+
+// validateEVMGenesis checks a subnet-EVM genesis
+func validateEVMGenesis(data []byte) error {
+	genesis, err := chainconfig.ParseGenesis(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode genesis: %w", err)
+	}
+
+	var errs []error
+	switch err := chainconfig.ValidateGenesis(genesis); {
+	case errors.Is(err, chainconfig.ErrInvalidChainConfig):
+		errs = append(errs, fieldError("$.config", "missing"))
+	case errors.Is(err, chainconfig.ErrMissingChainID):
+		errs = append(errs, fieldError("$.config.chainId", "missing"))
+	case errors.Is(err, chainconfig.ErrInvalidGasLimit):
+		errs = append(errs, fieldError("$.gasLimit", "must be positive"))
+	case err != nil:
+		errs = append(errs, err)
+	}
+
+	// subnet-EVM refuses to start without a fee config
+	var raw struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &raw); err == nil && raw.Config != nil {
+		if _, ok := raw.Config["feeConfig"]; !ok {
+			errs = append(errs, fieldError("$.config.feeConfig", "missing"))
+		}
+	}
+	return genesisError(errs)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_ValidateConfigDefault(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+
+	config, err := builder.createChainConfig(&CreateParams{
+		VMType:   VMTypeEVM,
+		ChainID:  big.NewInt(12345),
+		VMConfig: map[string]interface{}{"eth-apis": []interface{}{"eth", "net"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, builder.ValidateConfig(config))
+
+	// every violation is reported with its location
+	err = builder.ValidateConfig([]byte(`{
+		"chainId": 0,
+		"consensus": {"type": "lux", "parameters": {"k": 20, "alpha": 10, "beta": 1, "minBlockTime": "1s", "maxBlockTime": "1s"}},
+		"vm": {"type": "evm", "config": {"rpc-gas-cap": -1}}
+	}`))
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "$.chainId", fieldErr.Path)
+	assert.ErrorContains(t, err, "$.consensus.parameters.alpha: 10 must be a majority of k (20)")
+	assert.ErrorContains(t, err, "$.vm.config.rpc-gas-cap: must be a positive number")
+}
+
+func TestBuilder_ValidateGenesis(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+
+	for _, vmType := range []VMType{VMTypeEVM, VMTypeTokenVM, VMTypeMorpheusVM, VMTypeWASM} {
+		t.Run(string(vmType), func(t *testing.T) {
+			genesis, err := builder.GenerateGenesis(&GenesisParams{VMType: vmType, ChainID: big.NewInt(12345)})
+			require.NoError(t, err)
+			assert.NoError(t, builder.ValidateGenesis(vmType, genesis))
+		})
+	}
+
+	tests := []struct {
+		name    string
+		vmType  VMType
+		genesis string
+		errMsg  string
+	}{
+		{"EVM without fee config", VMTypeEVM, `{"config": {"chainId": 1}, "gasLimit": 8000000, "alloc": {}}`, "$.config.feeConfig: missing"},
+		{"EVM without config", VMTypeEVM, `{"gasLimit": 8000000, "alloc": {}}`, "$.config: missing"},
+		{"EVM without gas limit", VMTypeEVM, `{"config": {"chainId": 1, "feeConfig": {}}, "alloc": {}}`, "$.gasLimit: must be positive"},
+		{"TokenVM", VMTypeTokenVM, `{"vmType": "tokenvm", "initialRules": {}}`, "$.initialRules.minUnitPrice: must be positive"},
+		{"MorpheusVM", VMTypeMorpheusVM, `{"vmType": "morpheusvm"}`, "$.stateBranchFactor: 0 is below 2"},
+		{"WASM", VMTypeWASM, `{"vmType": "tokenvm"}`, `$.vmType: is "tokenvm", expected "wasm"`},
+		{"custom", VMTypeCustom, ``, "empty genesis"},
+		{"unsupported", VMType("jvm"), `{}`, "unsupported VM type: jvm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := builder.ValidateGenesis(tt.vmType, []byte(tt.genesis))
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+
+	err := builder.ValidateGenesis(VMTypeMorpheusVM, []byte(`{"vmType": "morpheusvm"}`))
+	assert.ErrorIs(t, err, ErrInvalidGenesis)
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.NoError(t, builder.ValidateGenesis(VMTypeCustom, []byte("anything")))
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/chainconfig"
+	"github.com/luxfi/sdk/fees"
+)
+
+// FeePreset selects the fee configuration of an EVM chain
+type FeePreset string
+
+const (
+	FeePresetLow    FeePreset = "low"
+	FeePresetMedium FeePreset = "medium"
+	FeePresetHigh   FeePreset = "high"
+)
+
+// ErrNoAdmins is returned when precompiles are enabled without admins
+var ErrNoAdmins = errors.New("enabled precompiles require at least one admin")
+
+// EVMPrecompiles toggles the stateful precompiles of an EVM chain. Every
+// enabled precompile is administered by the admins of the genesis
+// parameters. Warp messaging is always enabled.
+type EVMPrecompiles struct {
+	ContractDeployerAllowList bool
+	TxAllowList               bool
+	NativeMinter              bool
+	FeeManager                bool
+	RewardManager             bool
+}
+
+// addresses returns the addresses of the enabled precompiles
+func (p EVMPrecompiles) addresses() []common.Address {
+	var addresses []common.Address
+	for _, precompile := range []struct {
+		enabled bool
+		address common.Address
+	}{
+		{p.ContractDeployerAllowList, chainconfig.ContractDeployerAllowListAddress},
+		{p.TxAllowList, chainconfig.TxAllowListAddress},
+		{p.NativeMinter, chainconfig.NativeMinterAddress},
+		{p.FeeManager, chainconfig.FeeManagerAddress},
+		{p.RewardManager, chainconfig.RewardManagerAddress},
+	} {
+		if precompile.enabled {
+			addresses = append(addresses, precompile.address)
+		}
+	}
+	return addresses
+}
+
+// evmUpgrades are the network upgrades an EVM chain activates at genesis
+var evmUpgrades = []string{"subnetEVMTimestamp", "durangoTimestamp"}
+
+// generateEVMGenesis generates a subnet-EVM genesis
+func (b *Builder) generateEVMGenesis(params *GenesisParams) ([]byte, error) {
+	switch params.FeePreset {
+	case "", FeePresetLow, FeePresetMedium, FeePresetHigh:
+	default:
+		return nil, fmt.Errorf("unknown fee preset: %s", params.FeePreset)
+	}
+	feeConfig := fees.NewFeeConfigBuilderForThroughput(string(params.FeePreset)).Build()
+
+	configBuilder := chainconfig.NewChainConfigBuilder().WithFeeConfig(feeConfig)
+	if params.ChainID != nil {
+		configBuilder.WithChainID(params.ChainID)
+	}
+	for _, upgrade := range evmUpgrades {
+		configBuilder.WithNetworkUpgrade(upgrade, big.NewInt(0))
+	}
+	configBuilder.WithPrecompile(chainconfig.WarpAddress, chainconfig.NewWarpConfig(params.Timestamp))
+
+	precompiles := params.Precompiles.addresses()
+	if len(precompiles) > 0 && len(params.Admins) == 0 {
+		return nil, ErrNoAdmins
+	}
+	for _, address := range precompiles {
+		config := chainconfig.NewAllowListConfig(params.Timestamp, params.Admins...)
+		if address == chainconfig.NativeMinterAddress {
+			configBuilder.WithPrecompile(address, &chainconfig.NativeMinterConfig{AllowListConfig: *config})
+			continue
+		}
+		configBuilder.WithPrecompile(address, config)
+	}
+
+	genesisBuilder := chainconfig.NewGenesisBuilder().
+		WithChainConfigBuilder(configBuilder).
+		WithGasLimit(feeConfig.GasLimit.Uint64()).
+		WithTimestamp(params.Timestamp)
+	for address, account := range params.Allocations {
+		balance := account.Balance
+		if balance == nil {
+			balance = new(big.Int)
+		}
+		genesisBuilder.WithContract(address, balance, account.Code, account.Storage)
+	}
+
+	if err := chainconfig.ValidateGenesis(genesisBuilder.Build()); err != nil {
+		return nil, fmt.Errorf("invalid EVM genesis: %w", err)
+	}
+	return genesisBuilder.ToJSON()
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/chainconfig"
+	"github.com/luxfi/sdk/fees"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evmGenesisConfig decodes the chain config of an EVM genesis
+func evmGenesisConfig(t *testing.T, data []byte) map[string]json.RawMessage {
+	var genesis struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(data, &genesis))
+	return genesis.Config
+}
+
+func TestBuilder_GenerateEVMGenesis(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	admin := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	data, err := builder.GenerateGenesis(&GenesisParams{
+		VMType:    VMTypeEVM,
+		ChainID:   big.NewInt(12345),
+		FeePreset: FeePresetHigh,
+		Precompiles: EVMPrecompiles{
+			TxAllowList:  true,
+			NativeMinter: true,
+		},
+		Admins: []common.Address{admin},
+		Allocations: map[common.Address]GenesisAccount{
+			admin: {Balance: big.NewInt(1000000)},
+		},
+	})
+	require.NoError(t, err)
+
+	genesis, err := chainconfig.ParseGenesis(data)
+	require.NoError(t, err)
+	require.NoError(t, chainconfig.ValidateGenesis(genesis))
+	assert.Equal(t, big.NewInt(12345), genesis.Config.ChainID)
+	assert.Equal(t, big.NewInt(0), genesis.Config.LondonBlock)
+	assert.Equal(t, fees.HighThroughputConfig.GasLimit.Uint64(), genesis.GasLimit)
+	assert.Equal(t, big.NewInt(1000000), genesis.Alloc[admin].Balance)
+
+	config := evmGenesisConfig(t, data)
+	feeConfig, err := json.Marshal(fees.HighThroughputConfig)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(feeConfig), string(config["feeConfig"]))
+	assert.JSONEq(t, `0`, string(config["subnetEVMTimestamp"]))
+	assert.JSONEq(t, `0`, string(config["durangoTimestamp"]))
+	assert.JSONEq(t, `{"blockTimestamp":0,"quorumNumerator":67}`, string(config["warpConfig"]))
+	allowList := `{"blockTimestamp":0,"adminAddresses":["` + admin.Hex() + `"]}`
+	assert.JSONEq(t, allowList, string(config["txAllowListConfig"]))
+	assert.JSONEq(t, allowList, string(config["contractNativeMinterConfig"]))
+	assert.NotContains(t, config, "contractDeployerAllowListConfig")
+	assert.NotContains(t, config, "feeManagerConfig")
+
+	again, err := builder.GenerateGenesis(&GenesisParams{
+		VMType:      VMTypeEVM,
+		ChainID:     big.NewInt(12345),
+		FeePreset:   FeePresetHigh,
+		Precompiles: EVMPrecompiles{NativeMinter: true, TxAllowList: true},
+		Admins:      []common.Address{admin},
+		Allocations: map[common.Address]GenesisAccount{admin: {Balance: big.NewInt(1000000)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestBuilder_GenerateEVMGenesisDefaults(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+
+	data, err := builder.GenerateGenesis(&GenesisParams{VMType: VMTypeEVM})
+	require.NoError(t, err)
+	genesis, err := chainconfig.ParseGenesis(data)
+	require.NoError(t, err)
+	assert.Equal(t, chainconfig.DefaultChainConfig().ChainID, genesis.Config.ChainID)
+	assert.Equal(t, fees.DefaultFeeConfig.GasLimit.Uint64(), genesis.GasLimit)
+
+	config := evmGenesisConfig(t, data)
+	assert.Contains(t, config, "warpConfig")
+	assert.NotContains(t, config, "txAllowListConfig")
+
+	_, err = builder.GenerateGenesis(&GenesisParams{VMType: VMTypeEVM, FeePreset: "extreme"})
+	assert.ErrorContains(t, err, "unknown fee preset: extreme")
+
+	_, err = builder.GenerateGenesis(&GenesisParams{VMType: VMTypeEVM, Precompiles: EVMPrecompiles{FeeManager: true}})
+	assert.ErrorIs(t, err, ErrNoAdmins)
+}
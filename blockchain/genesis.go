@@ -0,0 +1,502 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+const (
+	// NativeAsset is the asset allocations without an explicit asset hold
+	NativeAsset = "native"
+	// DefaultStateBranchFactor is the branch factor of the MorpheusVM state trie
+	DefaultStateBranchFactor = 16
+)
+
+var (
+	// ErrNoGenesisGenerator is returned when a custom VM genesis is requested
+	// without a generator
+	ErrNoGenesisGenerator = errors.New("custom VM requires a genesis generator")
+	// ErrInvalidGenesis is returned when a genesis fails validation
+	ErrInvalidGenesis = errors.New("invalid genesis")
+)
+
+// AssetDefinition defines an asset created at genesis
+type AssetDefinition struct {
+	ID       string `json:"id"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name,omitempty"`
+	Decimals uint8  `json:"decimals"`
+	// MaxSupply caps the total allocated and minted amount, nil for no cap
+	MaxSupply *big.Int `json:"maxSupply,omitempty"`
+	// Owner is the address allowed to mint the asset
+	Owner string `json:"owner,omitempty"`
+}
+
+// Allocation is the initial balance of an address
+type Allocation struct {
+	Address string   `json:"address"`
+	Asset   string   `json:"asset,omitempty"`
+	Balance *big.Int `json:"balance"`
+}
+
+// FeeRules defines the fee market of a chain
+type FeeRules struct {
+	// BaseFee is charged for every transaction
+	BaseFee uint64 `json:"baseFee"`
+	// MinUnitPrice is the lowest price of a unit of compute
+	MinUnitPrice uint64 `json:"minUnitPrice"`
+	// UnitPriceChangeDenominator bounds the unit price change per block
+	UnitPriceChangeDenominator uint64 `json:"unitPriceChangeDenominator"`
+	// WindowTargetUnits is the number of units targeted per fee window
+	WindowTargetUnits uint64 `json:"windowTargetUnits"`
+	// MaxBlockUnits is the most units a block may consume
+	MaxBlockUnits uint64 `json:"maxBlockUnits"`
+}
+
+// AllocationHook returns allocations added to the ones of the genesis
+// parameters, for addresses that are not EVM addresses
+type AllocationHook func(params *GenesisParams) ([]Allocation, error)
+
+// GenesisGenerator generates the genesis of a custom VM
+type GenesisGenerator func(params *GenesisParams) ([]byte, error)
+
+// DefaultFeeRules returns the fee rules used when none are given
+func DefaultFeeRules() FeeRules {
+	return FeeRules{
+		BaseFee:                    100,
+		MinUnitPrice:               1,
+		UnitPriceChangeDenominator: 48,
+		WindowTargetUnits:          20_000_000,
+		MaxBlockUnits:              1_800_000,
+	}
+}
+
+// Validate checks the fee rules are usable
+func (r FeeRules) Validate() error {
+	return r.validate("$")
+}
+
+// validate checks the fee rules found at the JSON path
+func (r FeeRules) validate(path string) error {
+	var errs []error
+	if r.MinUnitPrice == 0 {
+		errs = append(errs, fieldError(path+".minUnitPrice", "must be positive"))
+	}
+	if r.UnitPriceChangeDenominator == 0 {
+		errs = append(errs, fieldError(path+".unitPriceChangeDenominator", "must be positive"))
+	}
+	if r.MaxBlockUnits == 0 {
+		errs = append(errs, fieldError(path+".maxBlockUnits", "must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// TokenVMGenesis is the genesis of a TokenVM chain
+type TokenVMGenesis struct {
+	ChainID   *big.Int `json:"chainID"`
+	VMType    VMType   `json:"vmType"`
+	Timestamp uint64   `json:"timestamp"`
+	// Supply is the total supply of the native asset
+	Supply           *big.Int          `json:"supply"`
+	Assets           []AssetDefinition `json:"assets"`
+	CustomAllocation []Allocation      `json:"customAllocation"`
+	Validators       []Validator       `json:"validators,omitempty"`
+	Rules            FeeRules          `json:"initialRules"`
+}
+
+// Validate checks the assets, allocations, validators and fee rules
+func (g *TokenVMGenesis) Validate() error {
+	var errs []error
+	if g.VMType != VMTypeTokenVM {
+		errs = append(errs, fieldError("$.vmType", "is %q, expected %q", g.VMType, VMTypeTokenVM))
+	}
+	errs = append(errs, validateChainID(g.ChainID))
+
+	assets := make(map[string]*big.Int, len(g.Assets))
+	for i, asset := range g.Assets {
+		path := fmt.Sprintf("$.assets[%d]", i)
+		switch {
+		case asset.ID == "":
+			errs = append(errs, fieldError(path+".id", "missing"))
+		case asset.ID == NativeAsset:
+			errs = append(errs, fieldError(path+".id", "%s is reserved for the native asset", NativeAsset))
+		case asset.Symbol == "":
+			errs = append(errs, fieldError(path+".symbol", "missing"))
+		case asset.MaxSupply != nil && asset.MaxSupply.Sign() < 0:
+			errs = append(errs, fieldError(path+".maxSupply", "negative"))
+		}
+		if _, ok := assets[asset.ID]; ok {
+			errs = append(errs, fieldError(path+".id", "duplicate id %s", asset.ID))
+		}
+		assets[asset.ID] = asset.MaxSupply
+	}
+	assets[NativeAsset] = g.Supply
+	if g.Supply != nil && g.Supply.Sign() < 0 {
+		errs = append(errs, fieldError("$.supply", "negative"))
+	}
+
+	errs = append(errs, validateAllocations("$.customAllocation", g.CustomAllocation, assets))
+	errs = append(errs, validateValidators(g.Validators))
+	errs = append(errs, g.Rules.validate("$.initialRules"))
+	return genesisError(errs)
+}
+
+// MorpheusVMGenesis is the genesis of a MorpheusVM chain
+type MorpheusVMGenesis struct {
+	ChainID           *big.Int     `json:"chainID"`
+	VMType            VMType       `json:"vmType"`
+	Timestamp         uint64       `json:"timestamp"`
+	StateBranchFactor int          `json:"stateBranchFactor"`
+	CustomAllocation  []Allocation `json:"customAllocation"`
+	Validators        []Validator  `json:"validators,omitempty"`
+	Rules             FeeRules     `json:"initialRules"`
+}
+
+// Validate checks the allocations, validators and fee rules
+func (g *MorpheusVMGenesis) Validate() error {
+	var errs []error
+	if g.VMType != VMTypeMorpheusVM {
+		errs = append(errs, fieldError("$.vmType", "is %q, expected %q", g.VMType, VMTypeMorpheusVM))
+	}
+	errs = append(errs, validateChainID(g.ChainID))
+	if g.StateBranchFactor < 2 {
+		errs = append(errs, fieldError("$.stateBranchFactor", "%d is below 2", g.StateBranchFactor))
+	}
+	// MorpheusVM only knows its native asset
+	errs = append(errs, validateAllocations("$.customAllocation", g.CustomAllocation, map[string]*big.Int{NativeAsset: nil}))
+	errs = append(errs, validateValidators(g.Validators))
+	errs = append(errs, g.Rules.validate("$.initialRules"))
+	return genesisError(errs)
+}
+
+// WASMGenesis is the genesis of a WASM chain
+type WASMGenesis struct {
+	ChainID     *big.Int       `json:"chainID"`
+	VMType      VMType         `json:"vmType"`
+	Timestamp   uint64         `json:"timestamp"`
+	Allocations []Allocation   `json:"allocations"`
+	Contracts   []WASMContract `json:"contracts,omitempty"`
+	Validators  []Validator    `json:"validators,omitempty"`
+	Fees        FeeRules       `json:"fees"`
+}
+
+// WASMContract is a contract deployed at genesis
+type WASMContract struct {
+	Address string `json:"address"`
+	Code    []byte `json:"code"`
+}
+
+// wasmMagic starts every WASM module
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+// Validate checks the allocations, contracts, validators and fee rules
+func (g *WASMGenesis) Validate() error {
+	var errs []error
+	if g.VMType != VMTypeWASM {
+		errs = append(errs, fieldError("$.vmType", "is %q, expected %q", g.VMType, VMTypeWASM))
+	}
+	errs = append(errs, validateChainID(g.ChainID))
+	errs = append(errs, validateAllocations("$.allocations", g.Allocations, map[string]*big.Int{NativeAsset: nil}))
+
+	seen := make(map[string]bool, len(g.Contracts))
+	for i, contract := range g.Contracts {
+		path := fmt.Sprintf("$.contracts[%d]", i)
+		switch {
+		case contract.Address == "":
+			errs = append(errs, fieldError(path+".address", "missing"))
+		case seen[contract.Address]:
+			errs = append(errs, fieldError(path+".address", "duplicate address %s", contract.Address))
+		case !bytes.HasPrefix(contract.Code, wasmMagic):
+			errs = append(errs, fieldError(path+".code", "not a WASM module"))
+		}
+		seen[contract.Address] = true
+	}
+
+	errs = append(errs, validateValidators(g.Validators))
+	errs = append(errs, g.Fees.validate("$.fees"))
+	return genesisError(errs)
+}
+
+// ParseTokenVMGenesis decodes and validates a TokenVM genesis
+func ParseTokenVMGenesis(data []byte) (*TokenVMGenesis, error) {
+	genesis := &TokenVMGenesis{}
+	if err := decodeGenesis(data, genesis); err != nil {
+		return nil, err
+	}
+	if err := genesis.Validate(); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// ParseMorpheusVMGenesis decodes and validates a MorpheusVM genesis
+func ParseMorpheusVMGenesis(data []byte) (*MorpheusVMGenesis, error) {
+	genesis := &MorpheusVMGenesis{}
+	if err := decodeGenesis(data, genesis); err != nil {
+		return nil, err
+	}
+	if err := genesis.Validate(); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// ParseWASMGenesis decodes and validates a WASM genesis
+func ParseWASMGenesis(data []byte) (*WASMGenesis, error) {
+	genesis := &WASMGenesis{}
+	if err := decodeGenesis(data, genesis); err != nil {
+		return nil, err
+	}
+	if err := genesis.Validate(); err != nil {
+		return nil, err
+	}
+	return genesis, nil
+}
+
+// generateTokenVMGenesis generates TokenVM genesis
+func (b *Builder) generateTokenVMGenesis(params *GenesisParams) ([]byte, error) {
+	allocations, err := genesisAllocations(params)
+	if err != nil {
+		return nil, err
+	}
+
+	supply := params.InitialSupply
+	if supply == nil {
+		// without an explicit supply, the native asset is what is allocated
+		supply = new(big.Int)
+		for _, allocation := range allocations {
+			if allocation.Asset == NativeAsset {
+				supply.Add(supply, allocation.Balance)
+			}
+		}
+	}
+
+	genesis := &TokenVMGenesis{
+		ChainID:          params.ChainID,
+		VMType:           VMTypeTokenVM,
+		Timestamp:        params.Timestamp,
+		Supply:           supply,
+		Assets:           params.Assets,
+		CustomAllocation: allocations,
+		Validators:       params.ValidatorSet,
+		Rules:            feeRules(params),
+	}
+	return encodeGenesis(genesis)
+}
+
+// generateMorpheusVMGenesis generates MorpheusVM genesis
+func (b *Builder) generateMorpheusVMGenesis(params *GenesisParams) ([]byte, error) {
+	allocations, err := genesisAllocations(params)
+	if err != nil {
+		return nil, err
+	}
+
+	genesis := &MorpheusVMGenesis{
+		ChainID:           params.ChainID,
+		VMType:            VMTypeMorpheusVM,
+		Timestamp:         params.Timestamp,
+		StateBranchFactor: DefaultStateBranchFactor,
+		CustomAllocation:  allocations,
+		Validators:        params.ValidatorSet,
+		Rules:             feeRules(params),
+	}
+	return encodeGenesis(genesis)
+}
+
+// generateWASMGenesis generates WASM genesis. EVM allocations carrying code
+// become genesis contracts.
+func (b *Builder) generateWASMGenesis(params *GenesisParams) ([]byte, error) {
+	allocations, err := genesisAllocations(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var contracts []WASMContract
+	for addr, account := range params.Allocations {
+		if len(account.Code) > 0 {
+			contracts = append(contracts, WASMContract{Address: addr.Hex(), Code: account.Code})
+		}
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Address < contracts[j].Address
+	})
+
+	genesis := &WASMGenesis{
+		ChainID:     params.ChainID,
+		VMType:      VMTypeWASM,
+		Timestamp:   params.Timestamp,
+		Allocations: allocations,
+		Contracts:   contracts,
+		Validators:  params.ValidatorSet,
+		Fees:        feeRules(params),
+	}
+	return encodeGenesis(genesis)
+}
+
+// generateCustomGenesis generates the genesis of a custom VM through the
+// generator of the parameters
+func (b *Builder) generateCustomGenesis(params *GenesisParams) ([]byte, error) {
+	if params.Generator == nil {
+		return nil, ErrNoGenesisGenerator
+	}
+	genesis, err := params.Generator(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate custom genesis: %w", err)
+	}
+	if len(genesis) == 0 {
+		return nil, fmt.Errorf("%w: custom generator returned an empty genesis", ErrInvalidGenesis)
+	}
+	return genesis, nil
+}
+
+// genesisAllocations merges the EVM allocations of the parameters with the
+// ones returned by their allocation hook, sorted by address and asset so the
+// same parameters always produce the same genesis
+func genesisAllocations(params *GenesisParams) ([]Allocation, error) {
+	allocations := make([]Allocation, 0, len(params.Allocations))
+	for addr, account := range params.Allocations {
+		if account.Balance == nil {
+			continue
+		}
+		allocations = append(allocations, Allocation{
+			Address: addr.Hex(),
+			Asset:   NativeAsset,
+			Balance: account.Balance,
+		})
+	}
+
+	if params.AllocationHook != nil {
+		extra, err := params.AllocationHook(params)
+		if err != nil {
+			return nil, fmt.Errorf("allocation hook failed: %w", err)
+		}
+		for _, allocation := range extra {
+			if allocation.Asset == "" {
+				allocation.Asset = NativeAsset
+			}
+			allocations = append(allocations, allocation)
+		}
+	}
+
+	sort.SliceStable(allocations, func(i, j int) bool {
+		if allocations[i].Address != allocations[j].Address {
+			return allocations[i].Address < allocations[j].Address
+		}
+		return allocations[i].Asset < allocations[j].Asset
+	})
+	return allocations, nil
+}
+
+// feeRules returns the fee rules of the parameters or the defaults
+func feeRules(params *GenesisParams) FeeRules {
+	if params.Fees != nil {
+		return *params.Fees
+	}
+	return DefaultFeeRules()
+}
+
+// validateAllocations checks every allocation holds a positive balance of a
+// known asset, once per address, without exceeding the asset supply
+func validateAllocations(path string, allocations []Allocation, supplies map[string]*big.Int) error {
+	var errs []error
+	type holding struct{ address, asset string }
+	seen := make(map[holding]bool, len(allocations))
+	totals := make(map[string]*big.Int, len(supplies))
+	for i, allocation := range allocations {
+		path := fmt.Sprintf("%s[%d]", path, i)
+		asset := allocation.Asset
+		if asset == "" {
+			asset = NativeAsset
+		}
+		switch {
+		case allocation.Address == "":
+			errs = append(errs, fieldError(path+".address", "missing"))
+			continue
+		case allocation.Balance == nil || allocation.Balance.Sign() <= 0:
+			errs = append(errs, fieldError(path+".balance", "balance of %s must be positive", allocation.Address))
+			continue
+		}
+		if _, ok := supplies[asset]; !ok {
+			errs = append(errs, fieldError(path+".asset", "unknown asset %s", asset))
+			continue
+		}
+		key := holding{address: allocation.Address, asset: asset}
+		if seen[key] {
+			errs = append(errs, fieldError(path, "duplicate allocation of %s to %s", asset, allocation.Address))
+			continue
+		}
+		seen[key] = true
+
+		if totals[asset] == nil {
+			totals[asset] = new(big.Int)
+		}
+		totals[asset].Add(totals[asset], allocation.Balance)
+	}
+
+	for asset, total := range totals {
+		if supply := supplies[asset]; supply != nil && total.Cmp(supply) > 0 {
+			errs = append(errs, fieldError(path, "%s allocations total %s, above the supply of %s", asset, total, supply))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateValidators checks the genesis validators
+func validateValidators(validators []Validator) error {
+	var errs []error
+	seen := make(map[string]bool, len(validators))
+	for i, validator := range validators {
+		path := fmt.Sprintf("$.validators[%d]", i)
+		switch {
+		case !strings.HasPrefix(validator.NodeID, "NodeID-"):
+			errs = append(errs, fieldError(path+".nodeId", "invalid node ID %q", validator.NodeID))
+		case validator.Weight == 0:
+			errs = append(errs, fieldError(path+".weight", "must be positive"))
+		case seen[validator.NodeID]:
+			errs = append(errs, fieldError(path+".nodeId", "duplicate node ID %s", validator.NodeID))
+		}
+		seen[validator.NodeID] = true
+	}
+	return errors.Join(errs...)
+}
+
+func validateChainID(chainID *big.Int) error {
+	if chainID != nil && chainID.Sign() <= 0 {
+		return fieldError("$.chainID", "%s is not positive", chainID)
+	}
+	return nil
+}
+
+// genesisError joins validation errors under ErrInvalidGenesis
+func genesisError(errs []error) error {
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGenesis, err)
+	}
+	return nil
+}
+
+// encodeGenesis validates a genesis and encodes it to JSON
+func encodeGenesis(genesis interface{ Validate() error }) ([]byte, error) {
+	if err := genesis.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(genesis)
+}
+
+// decodeGenesis decodes a genesis, rejecting unknown fields
+func decodeGenesis(data []byte, genesis any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(genesis); err != nil {
+		return fmt.Errorf("failed to decode genesis: %w", err)
+	}
+	return nil
+}
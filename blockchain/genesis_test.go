@@ -0,0 +1,258 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice = common.HexToAddress("0x0a")
+	bob   = common.HexToAddress("0x0b")
+)
+
+func TestBuilder_GenerateTokenVMGenesis(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	params := &GenesisParams{
+		VMType:  VMTypeTokenVM,
+		ChainID: big.NewInt(34567),
+		Allocations: map[common.Address]GenesisAccount{
+			bob:   {Balance: big.NewInt(300)},
+			alice: {Balance: big.NewInt(700)},
+		},
+		ValidatorSet: []Validator{{NodeID: "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", Weight: 100}},
+		Assets: []AssetDefinition{
+			{ID: "usd", Symbol: "USD", Name: "Dollar", Decimals: 6, MaxSupply: big.NewInt(5000), Owner: alice.Hex()},
+		},
+		AllocationHook: func(*GenesisParams) ([]Allocation, error) {
+			return []Allocation{
+				{Address: "token1alice", Asset: "usd", Balance: big.NewInt(5000)},
+				{Address: "token1carol", Balance: big.NewInt(50)},
+			}, nil
+		},
+	}
+
+	data, err := builder.GenerateGenesis(params)
+	require.NoError(t, err)
+
+	genesis, err := ParseTokenVMGenesis(data)
+	require.NoError(t, err)
+	assert.Equal(t, VMTypeTokenVM, genesis.VMType)
+	assert.Equal(t, big.NewInt(34567), genesis.ChainID)
+	// without an initial supply, the native supply is the allocated amount
+	assert.Equal(t, big.NewInt(1050), genesis.Supply)
+	assert.Equal(t, params.Assets, genesis.Assets)
+	assert.Equal(t, params.ValidatorSet, genesis.Validators)
+	assert.Equal(t, DefaultFeeRules(), genesis.Rules)
+	assert.Equal(t, []Allocation{
+		{Address: alice.Hex(), Asset: NativeAsset, Balance: big.NewInt(700)},
+		{Address: bob.Hex(), Asset: NativeAsset, Balance: big.NewInt(300)},
+		{Address: "token1alice", Asset: "usd", Balance: big.NewInt(5000)},
+		{Address: "token1carol", Asset: NativeAsset, Balance: big.NewInt(50)},
+	}, genesis.CustomAllocation)
+
+	// the parsed genesis encodes back to the generated one
+	encoded, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+
+	again, err := builder.GenerateGenesis(params)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestBuilder_GenerateMorpheusVMGenesis(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	fees := FeeRules{MinUnitPrice: 10, UnitPriceChangeDenominator: 8, MaxBlockUnits: 100}
+	data, err := builder.GenerateGenesis(&GenesisParams{
+		VMType:  VMTypeMorpheusVM,
+		ChainID: big.NewInt(45678),
+		Allocations: map[common.Address]GenesisAccount{
+			alice: {Balance: big.NewInt(1000)},
+		},
+		Fees:      &fees,
+		Timestamp: 1700000000,
+	})
+	require.NoError(t, err)
+
+	genesis, err := ParseMorpheusVMGenesis(data)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStateBranchFactor, genesis.StateBranchFactor)
+	assert.Equal(t, uint64(1700000000), genesis.Timestamp)
+	assert.Equal(t, fees, genesis.Rules)
+	assert.Equal(t, []Allocation{{Address: alice.Hex(), Asset: NativeAsset, Balance: big.NewInt(1000)}}, genesis.CustomAllocation)
+
+	encoded, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+}
+
+func TestBuilder_GenerateWASMGenesis(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	code := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+	data, err := builder.GenerateGenesis(&GenesisParams{
+		VMType:  VMTypeWASM,
+		ChainID: big.NewInt(23456),
+		Allocations: map[common.Address]GenesisAccount{
+			alice: {Balance: big.NewInt(1000)},
+			bob:   {Code: code},
+		},
+	})
+	require.NoError(t, err)
+
+	genesis, err := ParseWASMGenesis(data)
+	require.NoError(t, err)
+	assert.Equal(t, []Allocation{{Address: alice.Hex(), Asset: NativeAsset, Balance: big.NewInt(1000)}}, genesis.Allocations)
+	assert.Equal(t, []WASMContract{{Address: bob.Hex(), Code: code}}, genesis.Contracts)
+
+	encoded, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+
+	_, err = builder.GenerateGenesis(&GenesisParams{
+		VMType: VMTypeWASM,
+		Allocations: map[common.Address]GenesisAccount{
+			bob: {Code: []byte{0x60, 0x80}},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidGenesis)
+	assert.ErrorContains(t, err, "$.contracts[0].code: not a WASM module")
+}
+
+func TestBuilder_GenerateCustomGenesis(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+
+	_, err := builder.GenerateGenesis(&GenesisParams{VMType: VMTypeCustom})
+	assert.ErrorIs(t, err, ErrNoGenesisGenerator)
+
+	data, err := builder.GenerateGenesis(&GenesisParams{
+		VMType:  VMTypeCustom,
+		ChainID: big.NewInt(7),
+		Generator: func(params *GenesisParams) ([]byte, error) {
+			return json.Marshal(map[string]any{"network": params.ChainID})
+		},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"network":7}`, string(data))
+
+	_, err = builder.GenerateGenesis(&GenesisParams{
+		VMType:    VMTypeCustom,
+		Generator: func(*GenesisParams) ([]byte, error) { return nil, errors.New("boom") },
+	})
+	assert.ErrorContains(t, err, "failed to generate custom genesis: boom")
+}
+
+func TestGenesis_Validate(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+
+	tests := []struct {
+		name   string
+		params *GenesisParams
+		errMsg string
+	}{
+		{
+			name:   "negative chain ID",
+			params: &GenesisParams{VMType: VMTypeTokenVM, ChainID: big.NewInt(-1)},
+			errMsg: "$.chainID: -1 is not positive",
+		},
+		{
+			name: "over supply",
+			params: &GenesisParams{
+				VMType:        VMTypeTokenVM,
+				InitialSupply: big.NewInt(10),
+				Allocations:   map[common.Address]GenesisAccount{alice: {Balance: big.NewInt(11)}},
+			},
+			errMsg: "$.customAllocation: native allocations total 11, above the supply of 10",
+		},
+		{
+			name: "unknown asset",
+			params: &GenesisParams{
+				VMType: VMTypeMorpheusVM,
+				AllocationHook: func(*GenesisParams) ([]Allocation, error) {
+					return []Allocation{{Address: "morpheus1alice", Asset: "usd", Balance: big.NewInt(1)}}, nil
+				},
+			},
+			errMsg: "$.customAllocation[0].asset: unknown asset usd",
+		},
+		{
+			name: "duplicate allocation",
+			params: &GenesisParams{
+				VMType: VMTypeWASM,
+				AllocationHook: func(*GenesisParams) ([]Allocation, error) {
+					return []Allocation{
+						{Address: "wasm1alice", Balance: big.NewInt(1)},
+						{Address: "wasm1alice", Balance: big.NewInt(2)},
+					}, nil
+				},
+			},
+			errMsg: "$.allocations[1]: duplicate allocation of native to wasm1alice",
+		},
+		{
+			name: "zero balance",
+			params: &GenesisParams{
+				VMType: VMTypeTokenVM,
+				AllocationHook: func(*GenesisParams) ([]Allocation, error) {
+					return []Allocation{{Address: "token1alice", Balance: big.NewInt(0)}}, nil
+				},
+			},
+			errMsg: "$.customAllocation[0].balance: balance of token1alice must be positive",
+		},
+		{
+			name: "reserved asset",
+			params: &GenesisParams{
+				VMType: VMTypeTokenVM,
+				Assets: []AssetDefinition{{ID: NativeAsset, Symbol: "LUX"}},
+			},
+			errMsg: "$.assets[0].id: native is reserved for the native asset",
+		},
+		{
+			name: "invalid validator",
+			params: &GenesisParams{
+				VMType:       VMTypeMorpheusVM,
+				ValidatorSet: []Validator{{NodeID: "node1", Weight: 1}},
+			},
+			errMsg: `$.validators[0].nodeId: invalid node ID "node1"`,
+		},
+		{
+			name:   "invalid fees",
+			params: &GenesisParams{VMType: VMTypeWASM, Fees: &FeeRules{}},
+			errMsg: "$.fees.minUnitPrice: must be positive",
+		},
+		{
+			name: "hook error",
+			params: &GenesisParams{
+				VMType:         VMTypeTokenVM,
+				AllocationHook: func(*GenesisParams) ([]Allocation, error) { return nil, errors.New("no keys") },
+			},
+			errMsg: "allocation hook failed: no keys",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			genesis, err := builder.GenerateGenesis(tt.params)
+			assert.ErrorContains(t, err, tt.errMsg)
+			assert.Nil(t, genesis)
+		})
+	}
+}
+
+func TestParseGenesis_Errors(t *testing.T) {
+	_, err := ParseTokenVMGenesis([]byte(`{"vmType":"tokenvm","unknown":1}`))
+	assert.ErrorContains(t, err, `unknown field "unknown"`)
+
+	_, err = ParseMorpheusVMGenesis([]byte(`{"vmType":"tokenvm","stateBranchFactor":16,"initialRules":{"minUnitPrice":1,"unitPriceChangeDenominator":1,"maxBlockUnits":1}}`))
+	assert.ErrorIs(t, err, ErrInvalidGenesis)
+	assert.ErrorContains(t, err, `$.vmType: is "tokenvm", expected "morpheusvm"`)
+
+	_, err = ParseWASMGenesis([]byte(`not json`))
+	assert.ErrorContains(t, err, "failed to decode genesis")
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/luxfi/sdk/internal/types"
+)
+
+// ErrBlockchainConflict is returned when a blockchain is created or imported
+// under the ID of a blockchain with a different definition
+var ErrBlockchainConflict = errors.New("blockchain already exists with a different definition")
+
+// ComputeChainID returns the chain ID of a chain definition, the SHA-256
+// hash of its genesis followed by its VM ID
+func ComputeChainID(genesis []byte, vmID types.ID) types.ID {
+	hash := sha256.New()
+	hash.Write(genesis)
+	hash.Write(vmID[:])
+	var chainID types.ID
+	copy(chainID[:], hash.Sum(nil))
+	return chainID
+}
+
+// blockchainKey returns the ID the builder tracks a blockchain under,
+// derived from its name and chain ID
+func blockchainKey(name string, chainID types.ID) string {
+	hash := sha256.New()
+	hash.Write(chainID[:])
+	hash.Write([]byte(name))
+	var key types.ID
+	copy(key[:], hash.Sum(nil))
+	return key.CB58()
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/network"
+)
+
+// DefaultBootstrapTimeout is how long a deployed chain is given to bootstrap
+// when the deploy context has no deadline
+const DefaultBootstrapTimeout = 2 * time.Minute
+
+var (
+	// ErrNoWallet is returned when an L1 is deployed without a P-Chain
+	// wallet, and the builder has no keychain to pay for it
+	ErrNoWallet = errors.New("no P-Chain wallet configured")
+	// ErrDryRunUnsupported is returned when the wallet cannot build
+	// transactions without issuing them
+	ErrDryRunUnsupported = errors.New("wallet does not support dry runs")
+	// ErrNoNodes is returned when a chain is deployed to a network without nodes
+	ErrNoNodes = errors.New("network has no nodes")
+)
+
+// PChainWallet issues the P-Chain transactions of an L1 deployment and
+// waits for them to be accepted. It is backed by the P-Chain wallet of the
+// deployer's keychain, which pays the transaction fees and owns the subnet.
+type PChainWallet interface {
+	// IssueCreateSubnetTx creates a subnet owned by the wallet. The ID of
+	// the accepted transaction is the subnet ID.
+	IssueCreateSubnetTx(ctx context.Context) (types.ID, error)
+	// IssueAddSubnetValidatorTx adds a primary network validator to the subnet
+	IssueAddSubnetValidatorTx(ctx context.Context, subnetID types.ID, validator Validator) (types.ID, error)
+	// IssueCreateChainTx creates a chain running the VM in the subnet. The ID
+	// of the accepted transaction is the blockchain ID.
+	IssueCreateChainTx(ctx context.Context, subnetID types.ID, genesis []byte, vmID types.ID, chainName string) (types.ID, error)
+}
+
+// PChainTxBuilder is implemented by wallets that can build the P-Chain
+// transactions of an L1 deployment without issuing them, returning the IDs
+// the transactions would have from the current wallet state. NodeWallet
+// implements it.
+type PChainTxBuilder interface {
+	BuildCreateSubnetTx(ctx context.Context) (types.ID, error)
+	BuildCreateChainTx(ctx context.Context, subnetID types.ID, genesis []byte, vmID types.ID, chainName string) (types.ID, error)
+}
+
+// DeployPlan describes the outcome of an L1 deployment predicted by a dry run
+type DeployPlan struct {
+	ChainID      types.ID
+	VMID         types.ID
+	SubnetID     types.ID
+	BlockchainID types.ID
+	RPCURL       string
+	Validators   []Validator
+}
+
+// bootstrapWaiter waits for the EVM chain served at rpcURL to bootstrap
+type bootstrapWaiter func(rpcURL string, timeout time.Duration) error
+
+// bootstrapTimeout returns how long a chain is given to bootstrap, what is
+// left of the deadline of ctx or else DefaultBootstrapTimeout. It fails when
+// ctx is done or its deadline has passed.
+func bootstrapTimeout(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return DefaultBootstrapTimeout, nil
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+	return timeout, nil
+}
+
+// waitForEVMBootstrapped waits for an EVM chain through its RPC endpoint
+func waitForEVMBootstrapped(rpcURL string, timeout time.Duration) error {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.WaitForEVMBootstrapped(timeout)
+}
+
+// deployL1 creates a subnet, creates the chain in it and registers the
+// validators through the P-Chain, then waits for the chain to bootstrap
+func (b *Builder) deployL1(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
+	b.logger.Info("deploying L1 blockchain", "chain", blockchain.Name)
+
+	wallet, err := b.pChainWallet(ctx, blockchain, network)
+	if err != nil {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, err)
+	}
+	if len(network.Nodes) == 0 {
+		return fmt.Errorf("failed to deploy %s to network %s: %w", blockchain.Name, network.ID, ErrNoNodes)
+	}
+
+	subnetID, err := wallet.IssueCreateSubnetTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create subnet: %w", err)
+	}
+	blockchain.SubnetID = subnetID
+	b.logger.Info("created subnet", "chain", blockchain.Name, "subnet", subnetID.CB58())
+
+	// the chain is created before the validators are added, so the chain
+	// tx spends the outputs a dry run predicts it with
+	blockchainID, err := wallet.IssueCreateChainTx(ctx, subnetID, blockchain.Genesis, blockchain.VMID, blockchain.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create chain in subnet %s: %w", subnetID.CB58(), err)
+	}
+	blockchain.BlockchainID = blockchainID
+	blockchain.RPCURL = chainRPCURL(network, blockchainID)
+	b.logger.Info("created chain", "chain", blockchain.Name, "blockchain", blockchainID.CB58())
+
+	for _, validator := range blockchain.ValidatorSet {
+		if _, err := wallet.IssueAddSubnetValidatorTx(ctx, subnetID, validator); err != nil {
+			return fmt.Errorf("failed to add validator %s to subnet %s: %w", validator.NodeID, subnetID.CB58(), err)
+		}
+	}
+
+	if blockchain.VMType != VMTypeEVM {
+		return nil
+	}
+
+	timeout, err := bootstrapTimeout(ctx)
+	if err != nil {
+		return fmt.Errorf("chain %s did not bootstrap: %w", blockchainID.CB58(), err)
+	}
+	if err := b.waitBootstrapped(blockchain.RPCURL, timeout); err != nil {
+		return fmt.Errorf("chain %s did not bootstrap: %w", blockchainID.CB58(), err)
+	}
+	return nil
+}
+
+// DryRun predicts the subnet and blockchain IDs an L1 deployment would
+// produce, without issuing any transaction. The prediction holds as long
+// as the wallet state does not change before the deployment.
+func (b *Builder) DryRun(ctx context.Context, blockchain *Blockchain, network *network.Network) (*DeployPlan, error) {
+	if blockchain.Type != TypeL1 {
+		return nil, fmt.Errorf("dry run is not supported for %s blockchains", blockchain.Type)
+	}
+	wallet, err := b.pChainWallet(ctx, blockchain, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan %s: %w", blockchain.Name, err)
+	}
+	txBuilder, ok := wallet.(PChainTxBuilder)
+	if !ok {
+		return nil, fmt.Errorf("failed to plan %s: %w", blockchain.Name, ErrDryRunUnsupported)
+	}
+
+	subnetID, err := txBuilder.BuildCreateSubnetTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build subnet tx: %w", err)
+	}
+	blockchainID, err := txBuilder.BuildCreateChainTx(ctx, subnetID, blockchain.Genesis, blockchain.VMID, blockchain.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build chain tx: %w", err)
+	}
+
+	plan := &DeployPlan{
+		ChainID:      blockchain.ChainID,
+		VMID:         blockchain.VMID,
+		SubnetID:     subnetID,
+		BlockchainID: blockchainID,
+		Validators:   slices.Clone(blockchain.ValidatorSet),
+	}
+	if len(network.Nodes) > 0 {
+		plan.RPCURL = chainRPCURL(network, blockchainID)
+	}
+	return plan, nil
+}
+
+// chainRPCURL returns the RPC endpoint of a chain on the first node of the network
+func chainRPCURL(network *network.Network, blockchainID types.ID) string {
+	endpoint := strings.TrimSuffix(network.Nodes[0].Endpoint, "/")
+	return fmt.Sprintf("%s/ext/bc/%s/rpc", endpoint, blockchainID.CB58())
+}
+
+// IDs of the VMs chains are created with by default. A VM plugin is
+// registered under its name zero padded to 32 bytes.
+var (
+	// EVMID is the ID of subnet-EVM, the VM of EVM chains. The C-Chain VM
+	// is registered as "evm" and must not be used for subnet chains.
+	EVMID = types.ID{'s', 'u', 'b', 'n', 'e', 't', 'e', 'v', 'm'}
+	// TokenVMID is the ID of the hypersdk TokenVM
+	TokenVMID = types.ID{'t', 'o', 'k', 'e', 'n', 'v', 'm'}
+	// MorpheusVMID is the ID of the hypersdk MorpheusVM
+	MorpheusVMID = types.ID{'m', 'o', 'r', 'p', 'h', 'e', 'u', 's', 'v', 'm'}
+	// WASMVMID is the ID of the WASM VM
+	WASMVMID = types.ID{'w', 'a', 's', 'm'}
+)
+
+// ErrNoVMID is returned when a chain of a custom VM is created without the
+// ID of its VM
+var ErrNoVMID = errors.New("custom VM chains need a VM ID")
+
+// vmIDFromType returns the ID of the VM of a VM type
+func vmIDFromType(vmType VMType) (types.ID, error) {
+	switch vmType {
+	case VMTypeEVM:
+		return EVMID, nil
+	case VMTypeTokenVM:
+		return TokenVMID, nil
+	case VMTypeMorpheusVM:
+		return MorpheusVMID, nil
+	case VMTypeWASM:
+		return WASMVMID, nil
+	case VMTypeCustom:
+		return types.ID{}, ErrNoVMID
+	default:
+		return types.ID{}, fmt.Errorf("unsupported VM type: %s", vmType)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/node/vms/secp256k1fx"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePChainWallet records the P-Chain transactions it is asked to issue
+type fakePChainWallet struct {
+	mu         sync.Mutex
+	nextID     byte
+	built      byte
+	validators []Validator
+	chains     []fakeChain
+	subnetErr  error
+	chainErr   error
+}
+
+type fakeChain struct {
+	subnetID types.ID
+	genesis  []byte
+	vmID     types.ID
+	name     string
+}
+
+func (w *fakePChainWallet) txID() types.ID {
+	w.nextID++
+	w.built = 0
+	return types.ID{w.nextID}
+}
+
+// BuildCreateSubnetTx and BuildCreateChainTx predict the IDs of the next
+// transactions, as long as nothing is issued in between
+func (w *fakePChainWallet) BuildCreateSubnetTx(context.Context) (types.ID, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.built++
+	return types.ID{w.nextID + w.built}, nil
+}
+
+func (w *fakePChainWallet) BuildCreateChainTx(context.Context, types.ID, []byte, types.ID, string) (types.ID, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.built++
+	return types.ID{w.nextID + w.built}, nil
+}
+
+func (w *fakePChainWallet) IssueCreateSubnetTx(context.Context) (types.ID, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.subnetErr != nil {
+		return types.ID{}, w.subnetErr
+	}
+	return w.txID(), nil
+}
+
+func (w *fakePChainWallet) IssueAddSubnetValidatorTx(_ context.Context, _ types.ID, validator Validator) (types.ID, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.validators = append(w.validators, validator)
+	return w.txID(), nil
+}
+
+func (w *fakePChainWallet) IssueCreateChainTx(_ context.Context, subnetID types.ID, genesis []byte, vmID types.ID, chainName string) (types.ID, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.chainErr != nil {
+		return types.ID{}, w.chainErr
+	}
+	w.chains = append(w.chains, fakeChain{subnetID: subnetID, genesis: genesis, vmID: vmID, name: chainName})
+	return w.txID(), nil
+}
+
+// newTestBuilder returns a builder whose chains bootstrap immediately
+func newTestBuilder() *Builder {
+	builder := NewBuilder(log.NewNoOpLogger())
+	builder.waitBootstrapped = func(string, time.Duration) error { return nil }
+	return builder
+}
+
+// newTestNetwork returns a running network with a single node
+func newTestNetwork() *network.Network {
+	return &network.Network{
+		ID:     "test-network",
+		Name:   "Test Network",
+		Type:   network.NetworkTypeLocal,
+		Status: network.NetworkStatusRunning,
+		Nodes:  []*network.Node{{ID: "node1", Endpoint: "http://127.0.0.1:9650"}},
+	}
+}
+
+func TestBuilder_DeployL1(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	var waited []string
+	builder.waitBootstrapped = func(rpcURL string, timeout time.Duration) error {
+		waited = append(waited, rpcURL)
+		assert.Equal(t, DefaultBootstrapTimeout, timeout)
+		return nil
+	}
+	ctx := context.Background()
+	wallet := &fakePChainWallet{}
+
+	validators := []Validator{
+		{NodeID: "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", Weight: 100},
+		{NodeID: "NodeID-MFrZFVCXPv5iCn6M9K6XduxGTYp891xXZ", Weight: 100},
+	}
+	blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:         "l1",
+		Type:         TypeL1,
+		VMType:       VMTypeEVM,
+		ChainID:      big.NewInt(96369),
+		ValidatorSet: validators,
+		Wallet:       wallet,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, EVMID, blockchain.VMID)
+
+	plan, err := builder.DryRun(ctx, blockchain, newTestNetwork())
+	require.NoError(t, err)
+	assert.Empty(t, wallet.chains)
+
+	require.NoError(t, builder.Deploy(ctx, blockchain, newTestNetwork()))
+	assert.Equal(t, types.ID{1}, blockchain.SubnetID)
+	assert.Equal(t, types.ID{2}, blockchain.BlockchainID)
+	assert.Equal(t, &DeployPlan{
+		ChainID:      blockchain.ChainID,
+		VMID:         blockchain.VMID,
+		SubnetID:     blockchain.SubnetID,
+		BlockchainID: blockchain.BlockchainID,
+		RPCURL:       blockchain.RPCURL,
+		Validators:   validators,
+	}, plan)
+	assert.Equal(t, validators, wallet.validators)
+	require.Len(t, wallet.chains, 1)
+	assert.Equal(t, fakeChain{
+		subnetID: blockchain.SubnetID,
+		genesis:  blockchain.Genesis,
+		vmID:     blockchain.VMID,
+		name:     "l1",
+	}, wallet.chains[0])
+
+	rpcURL := "http://127.0.0.1:9650/ext/bc/" + types.ID{2}.CB58() + "/rpc"
+	assert.Equal(t, rpcURL, blockchain.RPCURL)
+	assert.Equal(t, []string{rpcURL}, waited)
+
+	got, err := builder.GetBlockchain(blockchain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDeployed, got.Status)
+	assert.Equal(t, blockchain.BlockchainID, got.BlockchainID)
+	assert.Equal(t, blockchain.SubnetID, got.SubnetID)
+}
+
+func TestBuilder_DeployL1Errors(t *testing.T) {
+	ctx := context.Background()
+	bootstrapErr := errors.New("no blocks")
+
+	tests := []struct {
+		name     string
+		wallet   *fakePChainWallet
+		network  *network.Network
+		waitErr  error
+		errMsg   string
+		subnetID types.ID
+	}{
+		{
+			name:    "no nodes",
+			wallet:  &fakePChainWallet{},
+			network: &network.Network{ID: "empty"},
+			errMsg:  "network has no nodes",
+		},
+		{
+			name:    "subnet",
+			wallet:  &fakePChainWallet{subnetErr: errors.New("insufficient funds")},
+			network: newTestNetwork(),
+			errMsg:  "failed to create subnet: insufficient funds",
+		},
+		{
+			name:     "chain",
+			wallet:   &fakePChainWallet{chainErr: errors.New("rejected")},
+			network:  newTestNetwork(),
+			errMsg:   "rejected",
+			subnetID: types.ID{1},
+		},
+		{
+			name:     "bootstrap",
+			wallet:   &fakePChainWallet{},
+			network:  newTestNetwork(),
+			waitErr:  bootstrapErr,
+			errMsg:   "did not bootstrap: no blocks",
+			subnetID: types.ID{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(log.NewNoOpLogger())
+			builder.waitBootstrapped = func(string, time.Duration) error { return tt.waitErr }
+
+			blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{
+				Name:   "l1",
+				Type:   TypeL1,
+				VMType: VMTypeEVM,
+				Wallet: tt.wallet,
+			})
+			require.NoError(t, err)
+
+			err = builder.Deploy(ctx, blockchain, tt.network)
+			assert.ErrorContains(t, err, tt.errMsg)
+			assert.Equal(t, StatusError, blockchain.Status)
+			// a subnet created before the failure is kept for inspection
+			assert.Equal(t, tt.subnetID, blockchain.SubnetID)
+		})
+	}
+}
+
+func TestBuilder_DeployL1WithKeychain(t *testing.T) {
+	ctx := context.Background()
+	builder := newTestBuilder()
+	wallet := &fakePChainWallet{}
+	var walletURIs []string
+	builder.newWallet = func(_ context.Context, uri string, keychain *secp256k1fx.Keychain) (PChainWallet, error) {
+		assert.NotNil(t, keychain)
+		walletURIs = append(walletURIs, uri)
+		return wallet, nil
+	}
+
+	blockchain, err := builder.CreateBlockchain(ctx, &CreateParams{Name: "l1", Type: TypeL1, VMType: VMTypeEVM})
+	require.NoError(t, err)
+	err = builder.Deploy(ctx, blockchain, newTestNetwork())
+	require.ErrorIs(t, err, ErrNoWallet)
+
+	// the keychain pays for chains without a wallet of their own
+	builder.SetKeychain(secp256k1fx.NewKeychain())
+	plan, err := builder.DryRun(ctx, blockchain, newTestNetwork())
+	require.NoError(t, err)
+	require.NoError(t, builder.Deploy(ctx, blockchain, newTestNetwork()))
+	assert.Equal(t, []string{"http://127.0.0.1:9650", "http://127.0.0.1:9650"}, walletURIs)
+	assert.Equal(t, plan.SubnetID, blockchain.SubnetID)
+	assert.Equal(t, plan.BlockchainID, blockchain.BlockchainID)
+	require.Len(t, wallet.chains, 1)
+	assert.Equal(t, EVMID, wallet.chains[0].vmID)
+}
+
+func TestIDCB58(t *testing.T) {
+	// the all zero ID is the primary network ID
+	assert.Equal(t, "11111111111111111111111111111111LpoYY", types.Empty.CB58())
+}
+
+// pChainWallet hides the dry run support of a fake wallet
+type pChainWallet struct {
+	PChainWallet
+}
+
+func TestBuilder_DryRunErrors(t *testing.T) {
+	builder := newTestBuilder()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		params *CreateParams
+		err    error
+		errMsg string
+	}{
+		{"no wallet", &CreateParams{Name: "a", Type: TypeL1, VMType: VMTypeEVM}, ErrNoWallet, ""},
+		{"no dry run support", &CreateParams{Name: "b", Type: TypeL1, VMType: VMTypeEVM, Wallet: pChainWallet{&fakePChainWallet{}}}, ErrDryRunUnsupported, ""},
+		{"L2", &CreateParams{Name: "c", Type: TypeL2, VMType: VMTypeEVM, Wallet: &fakePChainWallet{}}, nil, "not supported for L2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockchain, err := builder.CreateBlockchain(ctx, tt.params)
+			require.NoError(t, err)
+			_, err = builder.DryRun(ctx, blockchain, newTestNetwork())
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestBuilder_DeterministicIDs(t *testing.T) {
+	ctx := context.Background()
+	params := &CreateParams{
+		Name:    "stable",
+		Type:    TypeL1,
+		VMType:  VMTypeEVM,
+		ChainID: big.NewInt(96369),
+	}
+
+	first, err := newTestBuilder().CreateBlockchain(ctx, params)
+	require.NoError(t, err)
+	second, err := newTestBuilder().CreateBlockchain(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, first.ChainID, second.ChainID)
+	assert.Equal(t, first.Genesis, second.Genesis)
+	assert.Equal(t, ComputeChainID(first.Genesis, first.VMID), first.ChainID)
+
+	// the chain ID follows the genesis and the VM, the ID also the name
+	builder := newTestBuilder()
+	created, err := builder.CreateBlockchain(ctx, params)
+	require.NoError(t, err)
+	recreated, err := builder.CreateBlockchain(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, recreated.ID)
+	assert.Equal(t, created.CreatedAt, recreated.CreatedAt)
+	assert.Len(t, builder.ListBlockchains(), 1)
+
+	// another definition under the same name and chain ID is a conflict
+	conflicts := []struct {
+		name   string
+		change func(*CreateParams)
+	}{
+		{"type", func(p *CreateParams) { p.Type = TypeL3 }},
+		{"chain config", func(p *CreateParams) { p.VMConfig = map[string]interface{}{"pruning-enabled": false} }},
+		{"validator set", func(p *CreateParams) { p.ValidatorSet = []Validator{{NodeID: "NodeID-1", Weight: 20}} }},
+		{"wallet", func(p *CreateParams) { p.Wallet = &fakePChainWallet{} }},
+		{"per node configs", func(p *CreateParams) { p.PerNodeConfigs = map[string][]byte{"NodeID-1": []byte("{}")} }},
+	}
+	for _, tt := range conflicts {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicting := *params
+			tt.change(&conflicting)
+			_, err := builder.CreateBlockchain(ctx, &conflicting)
+			assert.ErrorIs(t, err, ErrBlockchainConflict)
+			assert.Len(t, builder.ListBlockchains(), 1)
+		})
+	}
+
+	app := &CreateParams{Name: "app", Type: TypeL3, VMType: VMTypeEVM, ChainID: big.NewInt(96371), L3Config: &L3Config{AppType: "token"}}
+	_, err = builder.CreateBlockchain(ctx, app)
+	require.NoError(t, err)
+	app.L3Config = &L3Config{AppType: "nft"}
+	_, err = builder.CreateBlockchain(ctx, app)
+	assert.ErrorIs(t, err, ErrBlockchainConflict)
+
+	renamed, err := builder.CreateBlockchain(ctx, &CreateParams{Name: "renamed", Type: TypeL1, VMType: VMTypeEVM, ChainID: big.NewInt(96369)})
+	require.NoError(t, err)
+	assert.Equal(t, first.ChainID, renamed.ChainID)
+	assert.NotEqual(t, first.ID, renamed.ID)
+
+	otherGenesis, err := builder.CreateBlockchain(ctx, &CreateParams{Name: "stable", Type: TypeL1, VMType: VMTypeEVM, ChainID: big.NewInt(96370)})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ChainID, otherGenesis.ChainID)
+
+	otherVM, err := builder.CreateBlockchain(ctx, &CreateParams{Name: "stable", Type: TypeL1, VMType: VMTypeEVM, ChainID: big.NewInt(96369), VMID: types.ID{'e', 'v', 'm'}})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ChainID, otherVM.ChainID)
+
+	// custom VMs have no default VM ID
+	_, err = builder.CreateBlockchain(ctx, &CreateParams{Name: "custom", Type: TypeL1, VMType: VMTypeCustom, Genesis: []byte("{}")})
+	assert.ErrorIs(t, err, ErrNoVMID)
+}
+
+func TestBootstrapTimeout(t *testing.T) {
+	timeout, err := bootstrapTimeout(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBootstrapTimeout, timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	timeout, err = bootstrapTimeout(ctx)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, timeout, time.Minute)
+	assert.Positive(t, timeout)
+
+	// a done context leaves no time to bootstrap
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = bootstrapTimeout(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = bootstrapTimeout(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
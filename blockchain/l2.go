@@ -0,0 +1,255 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/models"
+	"github.com/luxfi/sdk/network"
+)
+
+// Sequencer types of an L2
+const (
+	// SequencerCentralized orders transactions with a local sequencer process
+	SequencerCentralized = "centralized"
+	// SequencerBased leaves ordering to the settlement chain
+	SequencerBased = "based"
+)
+
+// Constructor signatures of the rollup contracts
+const (
+	inboxConstructor  = "(uint256)"
+	rollupConstructor = "(address)"
+	bridgeConstructor = "(address)"
+)
+
+var (
+	// ErrNoL2Config is returned when an L2 is deployed without an L2 config
+	ErrNoL2Config = errors.New("no L2 config")
+	// ErrNoSettlementChain is returned when an L2 has no settlement chain
+	ErrNoSettlementChain = errors.New("no settlement chain configured")
+	// ErrNoRollupContracts is returned when the bytecode of a rollup contract
+	// is missing
+	ErrNoRollupContracts = errors.New("no rollup contracts configured")
+	// ErrNoDeployerKey is returned when contracts are deployed without a key
+	ErrNoDeployerKey = errors.New("no deployer key configured")
+)
+
+// RollupContracts holds the hex encoded creation bytecode of the rollup
+// contracts, as output by solc --bin. Their constructors are
+// Inbox(uint256 chainId), Rollup(address inbox) and Bridge(address rollup).
+// The SDK ships no rollup contracts, so an L2 is deployed with the ones its
+// rollup framework builds.
+type RollupContracts struct {
+	Inbox  []byte
+	Rollup []byte
+	Bridge []byte
+}
+
+// SequencerConfig configures the local sequencer of a centralized L2
+type SequencerConfig struct {
+	// Binary is the path of the sequencer executable
+	Binary string
+	// Args are passed before the flags set by the builder
+	Args []string
+	// Env is added to the environment of the process
+	Env []string
+	// Port is the HTTP port of the sequencer, a free port if zero
+	Port uint16
+}
+
+// contractDeployer deploys a contract and returns its address
+type contractDeployer func(rpcURL, privateKey string, binBytes []byte, methodSpec string, params ...interface{}) (crypto.Address, error)
+
+// newL2Info returns the rollup description of an L2 created with the config
+func newL2Info(name string, chainID *big.Int, config *L2Config) *models.L2Config {
+	info := &models.L2Config{
+		Name:             name,
+		BaseChain:        config.SettlementChain,
+		RollupType:       config.RollupType,
+		DataAvailability: config.DALayer,
+		BasedRollup:      config.SequencerType == SequencerBased,
+	}
+	if chainID != nil && chainID.IsUint64() {
+		info.ChainID = chainID.Uint64()
+	}
+	return info
+}
+
+// deployL2 deploys the inbox, rollup and bridge contracts of an L2 to its
+// settlement chain and, for a centralized L2, starts its sequencer
+func (b *Builder) deployL2(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
+	b.logger.Info("deploying L2 blockchain", "chain", blockchain.Name)
+
+	config := blockchain.l2Config
+	if config == nil || blockchain.L2 == nil {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, ErrNoL2Config)
+	}
+	switch config.SequencerType {
+	case SequencerCentralized, SequencerBased:
+	default:
+		return fmt.Errorf("unsupported sequencer type: %q", config.SequencerType)
+	}
+	settlementURL, err := b.settlementRPCURL(config.SettlementChain)
+	if err != nil {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, err)
+	}
+	contracts := config.Contracts
+	if contracts == nil || len(contracts.Inbox) == 0 || len(contracts.Rollup) == 0 ||
+		(len(contracts.Bridge) == 0 && config.BridgeContract == "") {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, ErrNoRollupContracts)
+	}
+	if config.PrivateKey == "" {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, ErrNoDeployerKey)
+	}
+	if config.BridgeContract != "" && !common.IsHexAddress(config.BridgeContract) {
+		return fmt.Errorf("invalid bridge contract address: %q", config.BridgeContract)
+	}
+	l2 := blockchain.L2
+	if l2.ChainID == 0 {
+		return fmt.Errorf("failed to deploy %s: L2 chain ID is required", blockchain.Name)
+	}
+
+	inbox, err := b.deployContract(settlementURL, config.PrivateKey, contracts.Inbox, inboxConstructor, new(big.Int).SetUint64(l2.ChainID))
+	if err != nil {
+		return fmt.Errorf("failed to deploy inbox contract: %w", err)
+	}
+	l2.InboxContract = common.Address(inbox).Hex()
+	b.logger.Info("deployed inbox contract", "chain", blockchain.Name, "address", l2.InboxContract)
+
+	rollup, err := b.deployContract(settlementURL, config.PrivateKey, contracts.Rollup, rollupConstructor, inbox)
+	if err != nil {
+		return fmt.Errorf("failed to deploy rollup contract: %w", err)
+	}
+	l2.RollupContract = common.Address(rollup).Hex()
+	b.logger.Info("deployed rollup contract", "chain", blockchain.Name, "address", l2.RollupContract)
+
+	if config.BridgeContract != "" {
+		l2.BridgeContract = common.HexToAddress(config.BridgeContract).Hex()
+	} else {
+		bridge, err := b.deployContract(settlementURL, config.PrivateKey, contracts.Bridge, bridgeConstructor, rollup)
+		if err != nil {
+			return fmt.Errorf("failed to deploy bridge contract: %w", err)
+		}
+		l2.BridgeContract = common.Address(bridge).Hex()
+		b.logger.Info("deployed bridge contract", "chain", blockchain.Name, "address", l2.BridgeContract)
+	}
+
+	blockchain.RPCURL = config.RPCURL
+	if config.SequencerType == SequencerCentralized {
+		sequencer, err := b.startL2Sequencer(ctx, blockchain, config, settlementURL)
+		if err != nil {
+			return err
+		}
+		blockchain.RPCURL = sequencer.Endpoint
+	}
+	l2.DeployedAt = time.Now().Unix()
+	return nil
+}
+
+// startL2Sequencer starts the sequencer of a centralized L2 and waits for
+// it to report healthy. The sequencer of a previous deployment of the chain
+// is stopped first.
+func (b *Builder) startL2Sequencer(ctx context.Context, blockchain *Blockchain, config *L2Config, settlementURL string) (*Sequencer, error) {
+	if config.Sequencer == nil || config.Sequencer.Binary == "" {
+		return nil, fmt.Errorf("failed to deploy %s: %w", blockchain.Name, ErrNoSequencer)
+	}
+
+	b.mu.Lock()
+	previous := b.sequencers[blockchain.ID]
+	delete(b.sequencers, blockchain.ID)
+	b.mu.Unlock()
+	if previous != nil {
+		if err := previous.Stop(); err != nil {
+			return nil, fmt.Errorf("failed to stop previous sequencer: %w", err)
+		}
+	}
+
+	port := config.Sequencer.Port
+	if port == 0 {
+		var err error
+		if port, err = freePort(); err != nil {
+			return nil, err
+		}
+	}
+
+	l2 := blockchain.L2
+	args := append(slices.Clone(config.Sequencer.Args),
+		"--settlement-rpc="+settlementURL,
+		"--inbox="+l2.InboxContract,
+		"--rollup="+l2.RollupContract,
+		"--bridge="+l2.BridgeContract,
+		"--chain-id="+strconv.FormatUint(l2.ChainID, 10),
+		"--da-layer="+config.DALayer,
+		"--http-port="+strconv.Itoa(int(port)),
+	)
+	sequencer, err := startSequencer(config.Sequencer.Binary, args, config.Sequencer.Env, port)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := bootstrapTimeout(ctx)
+	if err != nil {
+		_ = sequencer.Stop()
+		return nil, fmt.Errorf("sequencer of %s is not healthy: %w", blockchain.Name, err)
+	}
+	if err := sequencer.waitHealthy(ctx, timeout); err != nil {
+		_ = sequencer.Stop()
+		return nil, fmt.Errorf("sequencer of %s is not healthy: %w", blockchain.Name, err)
+	}
+	b.logger.Info("started sequencer", "chain", blockchain.Name, "endpoint", sequencer.Endpoint)
+
+	b.mu.Lock()
+	b.sequencers[blockchain.ID] = sequencer
+	b.mu.Unlock()
+	go b.forgetSequencer(blockchain.ID, sequencer)
+	return sequencer, nil
+}
+
+// settlementRPCURL resolves the settlement chain of an L2: an RPC URL, or
+// the ID or name of a deployed blockchain of the builder
+func (b *Builder) settlementRPCURL(settlementChain string) (string, error) {
+	if settlementChain == "" {
+		return "", ErrNoSettlementChain
+	}
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(settlementChain, scheme) {
+			return settlementChain, nil
+		}
+	}
+	settlement, ok := b.lookup(settlementChain)
+	if !ok {
+		return "", fmt.Errorf("settlement chain %s not found", settlementChain)
+	}
+	if settlement.RPCURL == "" {
+		return "", fmt.Errorf("settlement chain %s has no RPC endpoint", settlementChain)
+	}
+	return settlement.RPCURL, nil
+}
+
+// lookup returns a snapshot of a blockchain by ID or name
+func (b *Builder) lookup(ref string) (*Blockchain, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if blockchain, ok := b.blockchains[ref]; ok {
+		return blockchain.clone(), true
+	}
+	for _, blockchain := range b.blockchains {
+		if blockchain.Name == ref {
+			return blockchain.clone(), true
+		}
+	}
+	return nil, false
+}
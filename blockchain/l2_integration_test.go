@@ -0,0 +1,93 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build integration
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuilder_DeployL2Devnet deploys a centralized L2 to a local EVM devnet.
+// The SDK ships no rollup contracts, so the test runs only when it is given
+// a settlement chain and the bytecode to deploy:
+//
+//	LUX_L2_SETTLEMENT_RPC_URL  RPC endpoint of the settlement chain
+//	LUX_L2_PRIVATE_KEY         hex encoded key funded on the settlement chain
+//	LUX_L2_CONTRACTS_DIR       directory holding the solc --bin outputs
+//	                           Inbox.bin, Rollup.bin and Bridge.bin
+//	LUX_L2_SEQUENCER           optional sequencer binary, a stub serving
+//	                           /health if empty
+func TestBuilder_DeployL2Devnet(t *testing.T) {
+	settlementURL := os.Getenv("LUX_L2_SETTLEMENT_RPC_URL")
+	privateKey := os.Getenv("LUX_L2_PRIVATE_KEY")
+	contractsDir := os.Getenv("LUX_L2_CONTRACTS_DIR")
+	if settlementURL == "" || privateKey == "" || contractsDir == "" {
+		t.Skip("LUX_L2_SETTLEMENT_RPC_URL, LUX_L2_PRIVATE_KEY and LUX_L2_CONTRACTS_DIR are required")
+	}
+	readBin := func(name string) []byte {
+		bin, err := os.ReadFile(filepath.Join(contractsDir, name+".bin"))
+		require.NoError(t, err)
+		return []byte(strings.TrimSpace(string(bin)))
+	}
+	sequencer := helperSequencer()
+	if binary := os.Getenv("LUX_L2_SEQUENCER"); binary != "" {
+		sequencer = &SequencerConfig{Binary: binary}
+	}
+
+	builder := NewBuilder(log.NewNoOpLogger())
+	defer builder.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	l2, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:    "devnet-rollup",
+		Type:    TypeL2,
+		VMType:  VMTypeEVM,
+		ChainID: big.NewInt(77777),
+		L2Config: &L2Config{
+			SequencerType:   SequencerCentralized,
+			DALayer:         "local",
+			RollupType:      "optimistic",
+			SettlementChain: settlementURL,
+			Contracts: &RollupContracts{
+				Inbox:  readBin("Inbox"),
+				Rollup: readBin("Rollup"),
+				Bridge: readBin("Bridge"),
+			},
+			PrivateKey: privateKey,
+			Sequencer:  sequencer,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, builder.Deploy(ctx, l2, &network.Network{ID: "devnet"}))
+	assert.Equal(t, StatusDeployed, l2.Status)
+
+	// the rollup contracts are deployed on the settlement chain
+	client, err := evm.GetClient(settlementURL)
+	require.NoError(t, err)
+	defer client.Close()
+	for _, address := range []string{l2.L2.InboxContract, l2.L2.RollupContract, l2.L2.BridgeContract} {
+		deployed, err := client.ContractAlreadyDeployed(address)
+		require.NoError(t, err)
+		assert.True(t, deployed, "no contract at %s", address)
+	}
+
+	sequencerHandle, err := builder.Sequencer(l2.ID)
+	require.NoError(t, err)
+	require.NoError(t, sequencerHandle.Health(ctx))
+	require.NoError(t, builder.StopSequencer(l2.ID))
+}
@@ -0,0 +1,368 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const settlementURL = "http://127.0.0.1:9650/ext/bc/C/rpc"
+
+// fakeDeployer records the contracts it is asked to deploy and returns
+// sequential addresses
+type fakeDeployer struct {
+	mu      sync.Mutex
+	deploys []fakeDeploy
+	failOn  int
+}
+
+type fakeDeploy struct {
+	rpcURL     string
+	privateKey string
+	bin        string
+	methodSpec string
+	params     []interface{}
+}
+
+func (d *fakeDeployer) deploy(rpcURL, privateKey string, binBytes []byte, methodSpec string, params ...interface{}) (crypto.Address, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deploys = append(d.deploys, fakeDeploy{rpcURL, privateKey, string(binBytes), methodSpec, params})
+	if len(d.deploys) == d.failOn {
+		return crypto.Address{}, errors.New("out of gas")
+	}
+	return crypto.Address{19: byte(len(d.deploys))}, nil
+}
+
+var testRollupContracts = &RollupContracts{
+	Inbox:  []byte("6001"),
+	Rollup: []byte("6002"),
+	Bridge: []byte("6003"),
+}
+
+// helperSequencer runs TestHelperSequencer as a sequencer process
+func helperSequencer(env ...string) *SequencerConfig {
+	return &SequencerConfig{
+		Binary: os.Args[0],
+		Args:   []string{"-test.run=^TestHelperSequencer$", "--"},
+		Env:    append([]string{"GO_WANT_HELPER_SEQUENCER=1"}, env...),
+	}
+}
+
+// TestHelperSequencer is not a real test. It serves the health endpoint of
+// a sequencer when run by helperSequencer.
+func TestHelperSequencer(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_SEQUENCER") != "1" {
+		t.Skip("helper process")
+	}
+	if os.Getenv("HELPER_SEQUENCER_FAIL") == "1" {
+		os.Exit(3)
+	}
+
+	flags := flag.NewFlagSet("sequencer", flag.ExitOnError)
+	port := flags.Int("http-port", 0, "")
+	for _, name := range []string{"settlement-rpc", "inbox", "rollup", "bridge", "chain-id", "da-layer"} {
+		flags.String(name, "", "")
+	}
+	if err := flags.Parse(flag.Args()); err != nil {
+		os.Exit(2)
+	}
+
+	http.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, "inbox=%s", flags.Lookup("inbox").Value)
+	})
+	go func() {
+		_ = http.ListenAndServe(fmt.Sprintf("127.0.0.1:%d", *port), nil)
+	}()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+	os.Exit(0)
+}
+
+func TestBuilder_DeployL2Centralized(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	deployer := &fakeDeployer{}
+	builder.deployContract = deployer.deploy
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	l2, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:    "rollup",
+		Type:    TypeL2,
+		VMType:  VMTypeEVM,
+		ChainID: big.NewInt(77777),
+		L2Config: &L2Config{
+			SequencerType:   SequencerCentralized,
+			DALayer:         "celestia",
+			RollupType:      "optimistic",
+			SettlementChain: settlementURL,
+			Contracts:       testRollupContracts,
+			PrivateKey:      "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027",
+			Sequencer:       helperSequencer(),
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, l2.L2)
+	assert.Equal(t, uint64(77777), l2.L2.ChainID)
+	assert.Equal(t, "celestia", l2.L2.DataAvailability)
+	assert.False(t, l2.L2.BasedRollup)
+
+	require.NoError(t, builder.Deploy(ctx, l2, &network.Network{ID: "local"}))
+	assert.Equal(t, StatusDeployed, l2.Status)
+
+	// each contract is constructed with the address of the previous one
+	inbox := crypto.Address{19: 1}
+	rollup := crypto.Address{19: 2}
+	require.Len(t, deployer.deploys, 3)
+	assert.Equal(t, fakeDeploy{settlementURL, l2.l2Config.PrivateKey, "6001", "(uint256)", []interface{}{big.NewInt(77777)}}, deployer.deploys[0])
+	assert.Equal(t, fakeDeploy{settlementURL, l2.l2Config.PrivateKey, "6002", "(address)", []interface{}{inbox}}, deployer.deploys[1])
+	assert.Equal(t, fakeDeploy{settlementURL, l2.l2Config.PrivateKey, "6003", "(address)", []interface{}{rollup}}, deployer.deploys[2])
+
+	stored, err := builder.GetBlockchain(l2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, common.Address(inbox).Hex(), stored.L2.InboxContract)
+	assert.Equal(t, common.Address(rollup).Hex(), stored.L2.RollupContract)
+	assert.Equal(t, common.Address{19: 3}.Hex(), stored.L2.BridgeContract)
+	assert.NotZero(t, stored.L2.DeployedAt)
+
+	sequencer, err := builder.Sequencer(l2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sequencer.Endpoint, stored.RPCURL)
+	require.NoError(t, sequencer.Health(ctx))
+
+	require.NoError(t, sequencer.Stop())
+	<-sequencer.Done()
+	assert.ErrorIs(t, sequencer.Health(ctx), ErrSequencerExited)
+
+	// the handle on an exited sequencer is dropped
+	require.Eventually(t, func() bool {
+		_, err := builder.Sequencer(l2.ID)
+		return errors.Is(err, ErrNoSequencer)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBuilder_StopSequencer(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(builder *Builder, blockchainID string) error
+	}{
+		{
+			name: "stop sequencer",
+			stop: (*Builder).StopSequencer,
+		},
+		{
+			name: "remove blockchain",
+			stop: (*Builder).RemoveBlockchain,
+		},
+		{
+			name: "close builder",
+			stop: func(builder *Builder, _ string) error {
+				return builder.Close()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(log.NewNoOpLogger())
+			builder.deployContract = (&fakeDeployer{}).deploy
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			l2, err := builder.CreateBlockchain(ctx, &CreateParams{
+				Name:    "rollup",
+				Type:    TypeL2,
+				VMType:  VMTypeEVM,
+				ChainID: big.NewInt(77777),
+				L2Config: &L2Config{
+					SequencerType:   SequencerCentralized,
+					SettlementChain: settlementURL,
+					Contracts:       testRollupContracts,
+					PrivateKey:      "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027",
+					Sequencer:       helperSequencer(),
+				},
+			})
+			require.NoError(t, err)
+			require.NoError(t, builder.Deploy(ctx, l2, &network.Network{ID: "local"}))
+			sequencer, err := builder.Sequencer(l2.ID)
+			require.NoError(t, err)
+
+			require.NoError(t, tt.stop(builder, l2.ID))
+			assert.ErrorIs(t, sequencer.Health(ctx), ErrSequencerExited)
+			_, err = builder.Sequencer(l2.ID)
+			assert.ErrorIs(t, err, ErrNoSequencer)
+		})
+	}
+
+	builder := NewBuilder(log.NewNoOpLogger())
+	assert.ErrorIs(t, builder.StopSequencer("missing"), ErrNoSequencer)
+	assert.ErrorContains(t, builder.RemoveBlockchain("missing"), "blockchain missing not found")
+	assert.NoError(t, builder.Close())
+}
+
+func TestBuilder_DeployL2Based(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	deployer := &fakeDeployer{}
+	builder.deployContract = deployer.deploy
+	ctx := context.Background()
+
+	// the settlement chain is resolved by name from the builder
+	settlement, err := builder.CreateBlockchain(ctx, &CreateParams{Name: "settlement", Type: TypeL1, VMType: VMTypeEVM})
+	require.NoError(t, err)
+	settlement.RPCURL = settlementURL
+	builder.store(settlement)
+
+	bridge := "0x00000000000000000000000000000000000000bb"
+	l2, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:    "based",
+		Type:    TypeL2,
+		VMType:  VMTypeEVM,
+		ChainID: big.NewInt(88888),
+		L2Config: &L2Config{
+			SequencerType:   SequencerBased,
+			SettlementChain: "settlement",
+			BridgeContract:  bridge,
+			Contracts:       &RollupContracts{Inbox: []byte("6001"), Rollup: []byte("6002")},
+			PrivateKey:      "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027",
+			RPCURL:          "http://127.0.0.1:8545",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, l2.L2.BasedRollup)
+
+	require.NoError(t, builder.Deploy(ctx, l2, &network.Network{ID: "local"}))
+
+	// the existing bridge is reused
+	require.Len(t, deployer.deploys, 2)
+	assert.Equal(t, settlementURL, deployer.deploys[0].rpcURL)
+	assert.Equal(t, common.HexToAddress(bridge).Hex(), l2.L2.BridgeContract)
+	assert.Equal(t, "http://127.0.0.1:8545", l2.RPCURL)
+
+	_, err = builder.Sequencer(l2.ID)
+	assert.ErrorIs(t, err, ErrNoSequencer)
+}
+
+func TestBuilder_DeployL2Errors(t *testing.T) {
+	const key = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+	tests := []struct {
+		name     string
+		config   *L2Config
+		chainID  *big.Int
+		failOn   int
+		err      error
+		errMsg   string
+		deployed int
+	}{
+		{
+			name: "no config",
+			err:  ErrNoL2Config,
+		},
+		{
+			name:   "unknown sequencer type",
+			config: &L2Config{SequencerType: "shared", SettlementChain: settlementURL},
+			errMsg: `unsupported sequencer type: "shared"`,
+		},
+		{
+			name:   "unknown settlement chain",
+			config: &L2Config{SequencerType: SequencerBased, SettlementChain: "nowhere"},
+			errMsg: "settlement chain nowhere not found",
+		},
+		{
+			name:   "no contracts",
+			config: &L2Config{SequencerType: SequencerBased, SettlementChain: settlementURL, PrivateKey: key},
+			err:    ErrNoRollupContracts,
+		},
+		{
+			name:   "no key",
+			config: &L2Config{SequencerType: SequencerBased, SettlementChain: settlementURL, Contracts: testRollupContracts},
+			err:    ErrNoDeployerKey,
+		},
+		{
+			name: "invalid bridge",
+			config: &L2Config{
+				SequencerType: SequencerBased, SettlementChain: settlementURL,
+				Contracts: testRollupContracts, PrivateKey: key, BridgeContract: "bridge",
+			},
+			errMsg: `invalid bridge contract address: "bridge"`,
+		},
+		{
+			name:   "no chain ID",
+			config: &L2Config{SequencerType: SequencerBased, SettlementChain: settlementURL, Contracts: testRollupContracts, PrivateKey: key},
+			errMsg: "L2 chain ID is required",
+		},
+		{
+			name:     "rollup deploy failure",
+			config:   &L2Config{SequencerType: SequencerBased, SettlementChain: settlementURL, Contracts: testRollupContracts, PrivateKey: key},
+			chainID:  big.NewInt(1),
+			failOn:   2,
+			errMsg:   "failed to deploy rollup contract: out of gas",
+			deployed: 2,
+		},
+		{
+			name:     "no sequencer",
+			config:   &L2Config{SequencerType: SequencerCentralized, SettlementChain: settlementURL, Contracts: testRollupContracts, PrivateKey: key},
+			chainID:  big.NewInt(1),
+			err:      ErrNoSequencer,
+			deployed: 3,
+		},
+		{
+			name: "sequencer exits",
+			config: &L2Config{
+				SequencerType: SequencerCentralized, SettlementChain: settlementURL,
+				Contracts: testRollupContracts, PrivateKey: key, Sequencer: helperSequencer("HELPER_SEQUENCER_FAIL=1"),
+			},
+			chainID:  big.NewInt(1),
+			err:      ErrSequencerExited,
+			deployed: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(log.NewNoOpLogger())
+			deployer := &fakeDeployer{failOn: tt.failOn}
+			builder.deployContract = deployer.deploy
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			l2, err := builder.CreateBlockchain(ctx, &CreateParams{
+				Name:     tt.name,
+				Type:     TypeL2,
+				VMType:   VMTypeEVM,
+				ChainID:  tt.chainID,
+				L2Config: tt.config,
+			})
+			require.NoError(t, err)
+
+			err = builder.Deploy(ctx, l2, &network.Network{ID: "local"})
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+			assert.Equal(t, StatusError, l2.Status)
+			assert.Len(t, deployer.deploys, tt.deployed)
+
+			_, err = builder.Sequencer(l2.ID)
+			assert.ErrorIs(t, err, ErrNoSequencer)
+		})
+	}
+}
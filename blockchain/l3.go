@@ -0,0 +1,289 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/network"
+)
+
+// AppType selects the contract bundle deployed for an L3 app
+type AppType string
+
+const (
+	// AppTypeToken deploys an ERC20 token
+	AppTypeToken AppType = "token"
+	// AppTypeDEX deploys a wrapped native token, an AMM factory and its router
+	AppTypeDEX AppType = "dex"
+	// AppTypeNFT deploys an ERC721 collection
+	AppTypeNFT AppType = "nft"
+)
+
+var (
+	// ErrNoL3Config is returned when an L3 is deployed without an L3 config
+	ErrNoL3Config = errors.New("no L3 config")
+	// ErrParentL2NotFound is returned when the parent L2 of an L3 is not
+	// a blockchain of the builder
+	ErrParentL2NotFound = errors.New("parent L2 not found")
+	// ErrParentL2NotDeployed is returned when the parent L2 of an L3 has not
+	// been deployed
+	ErrParentL2NotDeployed = errors.New("parent L2 not deployed")
+	// ErrInvalidAppConfig is returned when the app config of an L3 does not
+	// fit its app type
+	ErrInvalidAppConfig = errors.New("invalid app config")
+)
+
+// L3Endpoints are the endpoints of a deployed L3
+type L3Endpoints struct {
+	// ParentChain is the blockchain ID of the parent L2
+	ParentChain string
+	// ParentRPCURL is the RPC endpoint of the parent L2
+	ParentRPCURL string
+	// RPCURL is the RPC endpoint the contracts were deployed through
+	RPCURL string
+	// Contracts maps the contracts of the app bundle to their addresses
+	Contracts map[string]string
+}
+
+// appContract is a contract of an app bundle. Its args are built from the
+// app config and the contracts of the bundle deployed before it.
+type appContract struct {
+	name        string
+	constructor string
+	args        func(app *appParams, deployed map[string]crypto.Address) []interface{}
+}
+
+// appBundles are the contracts deployed for each app type, in order
+var appBundles = map[AppType][]appContract{
+	AppTypeToken: {
+		{
+			name:        "token",
+			constructor: "(string, address, uint256)",
+			args: func(app *appParams, _ map[string]crypto.Address) []interface{} {
+				return []interface{}{app.symbol, app.owner, app.supply}
+			},
+		},
+	},
+	AppTypeDEX: {
+		{
+			name:        "weth",
+			constructor: "()",
+			args: func(*appParams, map[string]crypto.Address) []interface{} {
+				return nil
+			},
+		},
+		{
+			name:        "factory",
+			constructor: "(address)",
+			args: func(app *appParams, _ map[string]crypto.Address) []interface{} {
+				return []interface{}{app.owner}
+			},
+		},
+		{
+			name:        "router",
+			constructor: "(address, address)",
+			args: func(_ *appParams, deployed map[string]crypto.Address) []interface{} {
+				return []interface{}{deployed["factory"], deployed["weth"]}
+			},
+		},
+	},
+	AppTypeNFT: {
+		{
+			name:        "nft",
+			constructor: "(string, string, address)",
+			args: func(app *appParams, _ map[string]crypto.Address) []interface{} {
+				return []interface{}{app.name, app.symbol, app.owner}
+			},
+		},
+	},
+}
+
+// defaultAppBytecode returns the bytecode of the bundle contracts the SDK
+// ships with
+var defaultAppBytecode = map[string]func() []byte{
+	"token": contract.TokenBin,
+}
+
+// appParams are the settings of an app bundle read from the app config
+type appParams struct {
+	owner  crypto.Address
+	name   string
+	symbol string
+	supply *big.Int
+	// weth is an existing wrapped native token used by a DEX
+	weth *crypto.Address
+}
+
+// parseAppConfig reads the settings the bundle of the app type needs from
+// the app config. Every app needs an owner; tokens need a symbol and a
+// supply, NFT collections a name and a symbol.
+func parseAppConfig(appType AppType, config map[string]interface{}) (*appParams, error) {
+	var errs []error
+	app := &appParams{}
+
+	address := func(key string) (crypto.Address, bool) {
+		value, ok := config[key].(string)
+		if !ok || !common.IsHexAddress(value) {
+			errs = append(errs, fmt.Errorf("%s must be an address", key))
+			return crypto.Address{}, false
+		}
+		return crypto.Address(common.HexToAddress(value)), true
+	}
+	text := func(key string) string {
+		value, ok := config[key].(string)
+		if !ok || value == "" {
+			errs = append(errs, fmt.Errorf("%s must be a non empty string", key))
+		}
+		return value
+	}
+
+	app.owner, _ = address("owner")
+	switch appType {
+	case AppTypeToken:
+		app.symbol = text("symbol")
+		supply, err := parseAmount(config["supply"])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("supply %w", err))
+		}
+		app.supply = supply
+	case AppTypeDEX:
+		if _, ok := config["weth"]; ok {
+			if weth, ok := address("weth"); ok {
+				app.weth = &weth
+			}
+		}
+	case AppTypeNFT:
+		app.name = text("name")
+		app.symbol = text("symbol")
+	default:
+		return nil, fmt.Errorf("unsupported app type: %q", appType)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("%w for %s: %w", ErrInvalidAppConfig, appType, err)
+	}
+	return app, nil
+}
+
+// parseAmount reads a positive integer given as a number or a decimal string
+func parseAmount(value interface{}) (*big.Int, error) {
+	var amount *big.Int
+	switch v := value.(type) {
+	case *big.Int:
+		amount = v
+	case int:
+		amount = big.NewInt(int64(v))
+	case int64:
+		amount = big.NewInt(v)
+	case uint64:
+		amount = new(big.Int).SetUint64(v)
+	case float64:
+		if v == float64(int64(v)) {
+			amount = big.NewInt(int64(v))
+		}
+	case json.Number:
+		amount, _ = new(big.Int).SetString(v.String(), 10)
+	case string:
+		amount, _ = new(big.Int).SetString(v, 10)
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errors.New("must be a positive integer")
+	}
+	return amount, nil
+}
+
+// deployL3 resolves the parent L2 of an L3 from the builder, deploys the
+// contract bundle of its app type and records the endpoints of the L3. No
+// separate chain is started: the contracts are deployed on the L2, through
+// the RPC endpoint of the L3 config or else the one of the parent L2.
+func (b *Builder) deployL3(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
+	b.logger.Info("deploying L3 blockchain", "chain", blockchain.Name)
+
+	config := blockchain.l3Config
+	if config == nil {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, ErrNoL3Config)
+	}
+	parent, ok := b.lookup(config.L2Chain)
+	if !ok || parent.Type != TypeL2 {
+		return fmt.Errorf("failed to deploy %s on %q: %w", blockchain.Name, config.L2Chain, ErrParentL2NotFound)
+	}
+	if (parent.Status != StatusDeployed && parent.Status != StatusRunning) || parent.RPCURL == "" {
+		return fmt.Errorf("failed to deploy %s on %s: %w", blockchain.Name, parent.Name, ErrParentL2NotDeployed)
+	}
+
+	appType := AppType(config.AppType)
+	app, err := parseAppConfig(appType, config.AppConfig)
+	if err != nil {
+		return err
+	}
+	bundle := appBundles[appType]
+	deployed := make(map[string]crypto.Address, len(bundle))
+	if app.weth != nil {
+		deployed["weth"] = *app.weth
+	}
+	bytecode := make(map[string][]byte, len(bundle))
+	for _, c := range bundle {
+		if _, ok := deployed[c.name]; ok {
+			continue
+		}
+		bin := config.Contracts[c.name]
+		if len(bin) == 0 {
+			if defaultBin, ok := defaultAppBytecode[c.name]; ok {
+				bin = defaultBin()
+			}
+		}
+		if len(bin) == 0 {
+			return fmt.Errorf("failed to deploy %s: no bytecode for the %s contract", blockchain.Name, c.name)
+		}
+		bytecode[c.name] = bin
+	}
+	if config.PrivateKey == "" {
+		return fmt.Errorf("failed to deploy %s: %w", blockchain.Name, ErrNoDeployerKey)
+	}
+
+	rpcURL := config.RPCURL
+	if rpcURL == "" {
+		rpcURL = parent.RPCURL
+	}
+	for _, c := range bundle {
+		if _, ok := deployed[c.name]; ok {
+			continue
+		}
+		address, err := b.deployContract(rpcURL, config.PrivateKey, bytecode[c.name], c.constructor, c.args(app, deployed)...)
+		if err != nil {
+			return fmt.Errorf("failed to deploy %s contract: %w", c.name, err)
+		}
+		deployed[c.name] = address
+		b.logger.Info("deployed app contract", "chain", blockchain.Name, "contract", c.name, "address", common.Address(address).Hex())
+	}
+
+	endpoints := &L3Endpoints{
+		ParentChain:  parent.ID,
+		ParentRPCURL: parent.RPCURL,
+		RPCURL:       rpcURL,
+		Contracts:    make(map[string]string, len(deployed)),
+	}
+	for name, address := range deployed {
+		endpoints.Contracts[name] = common.Address(address).Hex()
+	}
+	blockchain.L3 = endpoints
+	blockchain.RPCURL = rpcURL
+	return nil
+}
+
+// clone returns a deep copy of the endpoints
+func (e *L3Endpoints) clone() *L3Endpoints {
+	c := *e
+	c.Contracts = maps.Clone(e.Contracts)
+	return &c
+}
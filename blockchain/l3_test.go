@@ -0,0 +1,226 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/contract"
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	l2RPCURL    = "http://127.0.0.1:8545"
+	deployerKey = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+	owner       = "0x00000000000000000000000000000000000000aa"
+)
+
+// newParentL2 registers a deployed L2 with the builder
+func newParentL2(t *testing.T, builder *Builder, name string, status BlockchainStatus) *Blockchain {
+	t.Helper()
+	l2, err := builder.CreateBlockchain(context.Background(), &CreateParams{Name: name, Type: TypeL2, VMType: VMTypeEVM})
+	require.NoError(t, err)
+	l2.Status = status
+	l2.RPCURL = l2RPCURL
+	builder.store(l2)
+	return l2
+}
+
+func deployL3(t *testing.T, builder *Builder, config *L3Config) (*Blockchain, error) {
+	t.Helper()
+	ctx := context.Background()
+	l3, err := builder.CreateBlockchain(ctx, &CreateParams{
+		Name:     "app-" + config.AppType,
+		Type:     TypeL3,
+		VMType:   VMTypeEVM,
+		L3Config: config,
+	})
+	require.NoError(t, err)
+	return l3, builder.Deploy(ctx, l3, &network.Network{ID: "local"})
+}
+
+func TestBuilder_DeployL3Token(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	deployer := &fakeDeployer{}
+	builder.deployContract = deployer.deploy
+	parent := newParentL2(t, builder, "rollup", StatusDeployed)
+
+	l3, err := deployL3(t, builder, &L3Config{
+		L2Chain:    "rollup",
+		AppType:    string(AppTypeToken),
+		AppConfig:  map[string]interface{}{"owner": owner, "symbol": "APP", "supply": "1000000"},
+		PrivateKey: deployerKey,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusDeployed, l3.Status)
+
+	// the token defaults to the SDK's ERC20, deployed to the parent L2
+	require.Len(t, deployer.deploys, 1)
+	assert.Equal(t, fakeDeploy{
+		rpcURL:     l2RPCURL,
+		privateKey: deployerKey,
+		bin:        string(contract.TokenBin()),
+		methodSpec: "(string, address, uint256)",
+		params:     []interface{}{"APP", crypto.Address(common.HexToAddress(owner)), big.NewInt(1000000)},
+	}, deployer.deploys[0])
+
+	stored, err := builder.GetBlockchain(l3.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &L3Endpoints{
+		ParentChain:  parent.ID,
+		ParentRPCURL: l2RPCURL,
+		RPCURL:       l2RPCURL,
+		Contracts:    map[string]string{"token": common.Address{19: 1}.Hex()},
+	}, stored.L3)
+	assert.Equal(t, l2RPCURL, stored.RPCURL)
+}
+
+func TestBuilder_DeployL3DEX(t *testing.T) {
+	contracts := map[string][]byte{
+		"weth":    []byte("60weth"),
+		"factory": []byte("60factory"),
+		"router":  []byte("60router"),
+	}
+
+	t.Run("new weth", func(t *testing.T) {
+		builder := NewBuilder(log.NewNoOpLogger())
+		deployer := &fakeDeployer{}
+		builder.deployContract = deployer.deploy
+		parent := newParentL2(t, builder, "rollup", StatusRunning)
+
+		l3, err := deployL3(t, builder, &L3Config{
+			L2Chain:    parent.ID,
+			AppType:    string(AppTypeDEX),
+			AppConfig:  map[string]interface{}{"owner": owner},
+			Contracts:  contracts,
+			PrivateKey: deployerKey,
+			RPCURL:     "http://127.0.0.1:9545",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, deployer.deploys, 3)
+		weth, factory := crypto.Address{19: 1}, crypto.Address{19: 2}
+		assert.Equal(t, "()", deployer.deploys[0].methodSpec)
+		assert.Empty(t, deployer.deploys[0].params)
+		assert.Equal(t, []interface{}{crypto.Address(common.HexToAddress(owner))}, deployer.deploys[1].params)
+		assert.Equal(t, "(address, address)", deployer.deploys[2].methodSpec)
+		assert.Equal(t, []interface{}{factory, weth}, deployer.deploys[2].params)
+		for _, deploy := range deployer.deploys {
+			assert.Equal(t, "http://127.0.0.1:9545", deploy.rpcURL)
+		}
+
+		assert.Equal(t, "http://127.0.0.1:9545", l3.L3.RPCURL)
+		assert.Equal(t, l2RPCURL, l3.L3.ParentRPCURL)
+		assert.Len(t, l3.L3.Contracts, 3)
+	})
+
+	t.Run("existing weth", func(t *testing.T) {
+		builder := NewBuilder(log.NewNoOpLogger())
+		deployer := &fakeDeployer{}
+		builder.deployContract = deployer.deploy
+		newParentL2(t, builder, "rollup", StatusDeployed)
+
+		weth := "0x00000000000000000000000000000000000000ee"
+		l3, err := deployL3(t, builder, &L3Config{
+			L2Chain:    "rollup",
+			AppType:    string(AppTypeDEX),
+			AppConfig:  map[string]interface{}{"owner": owner, "weth": weth},
+			Contracts:  map[string][]byte{"factory": contracts["factory"], "router": contracts["router"]},
+			PrivateKey: deployerKey,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, deployer.deploys, 2)
+		assert.Equal(t, []interface{}{crypto.Address{19: 1}, crypto.Address(common.HexToAddress(weth))}, deployer.deploys[1].params)
+		assert.Equal(t, common.HexToAddress(weth).Hex(), l3.L3.Contracts["weth"])
+	})
+}
+
+func TestBuilder_DeployL3Errors(t *testing.T) {
+	nft := map[string]interface{}{"owner": owner, "name": "Apes", "symbol": "APE"}
+	tests := []struct {
+		name     string
+		config   *L3Config
+		failOn   int
+		err      error
+		errMsg   string
+		deployed int
+	}{
+		{
+			name:   "parent not found",
+			config: &L3Config{L2Chain: "missing", AppType: "nft"},
+			err:    ErrParentL2NotFound,
+		},
+		{
+			name:   "parent is not an L2",
+			config: &L3Config{L2Chain: "l1", AppType: "nft"},
+			err:    ErrParentL2NotFound,
+		},
+		{
+			name:   "parent not deployed",
+			config: &L3Config{L2Chain: "pending", AppType: "nft"},
+			err:    ErrParentL2NotDeployed,
+		},
+		{
+			name:   "unsupported app type",
+			config: &L3Config{L2Chain: "rollup", AppType: "gaming"},
+			errMsg: `unsupported app type: "gaming"`,
+		},
+		{
+			name:   "invalid app config",
+			config: &L3Config{L2Chain: "rollup", AppType: "token", AppConfig: map[string]interface{}{"symbol": "APP", "supply": -1}},
+			err:    ErrInvalidAppConfig,
+			errMsg: "owner must be an address\nsupply must be a positive integer",
+		},
+		{
+			name:   "no bytecode",
+			config: &L3Config{L2Chain: "rollup", AppType: "nft", AppConfig: nft, PrivateKey: deployerKey},
+			errMsg: "no bytecode for the nft contract",
+		},
+		{
+			name:   "no key",
+			config: &L3Config{L2Chain: "rollup", AppType: "nft", AppConfig: nft, Contracts: map[string][]byte{"nft": []byte("60nft")}},
+			err:    ErrNoDeployerKey,
+		},
+		{
+			name: "deploy failure",
+			config: &L3Config{
+				L2Chain: "rollup", AppType: "nft", AppConfig: nft,
+				Contracts: map[string][]byte{"nft": []byte("60nft")}, PrivateKey: deployerKey,
+			},
+			failOn:   1,
+			errMsg:   "failed to deploy nft contract: out of gas",
+			deployed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(log.NewNoOpLogger())
+			deployer := &fakeDeployer{failOn: tt.failOn}
+			builder.deployContract = deployer.deploy
+			newParentL2(t, builder, "rollup", StatusDeployed)
+			newParentL2(t, builder, "pending", StatusCreated)
+			_, err := builder.CreateBlockchain(context.Background(), &CreateParams{Name: "l1", Type: TypeL1, VMType: VMTypeEVM})
+			require.NoError(t, err)
+
+			l3, err := deployL3(t, builder, tt.config)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+			assert.Equal(t, StatusError, l3.Status)
+			assert.Nil(t, l3.L3)
+			assert.Len(t, deployer.deploys, tt.deployed)
+		})
+	}
+}
@@ -94,6 +94,41 @@ func (m *Metrics) UpdateResources(cpu float64, memory, disk uint64) {
 	m.DiskUsage = disk
 }
 
+// MetricsSnapshot is a copy of the values of a Metrics
+type MetricsSnapshot struct {
+	BlocksProduced   uint64
+	LastBlockTime    time.Time
+	AverageBlockTime time.Duration
+	TxProcessed      uint64
+	TxFailed         uint64
+	TPS              float64
+	PeersConnected   int
+	NetworkLatency   time.Duration
+	CPUUsage         float64
+	MemoryUsage      uint64
+	DiskUsage        uint64
+}
+
+// Snapshot returns a typed snapshot of current metrics
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return MetricsSnapshot{
+		BlocksProduced:   m.BlocksProduced,
+		LastBlockTime:    m.LastBlockTime,
+		AverageBlockTime: m.AverageBlockTime,
+		TxProcessed:      m.TxProcessed,
+		TxFailed:         m.TxFailed,
+		TPS:              m.TPS,
+		PeersConnected:   m.PeersConnected,
+		NetworkLatency:   m.NetworkLatency,
+		CPUUsage:         m.CPUUsage,
+		MemoryUsage:      m.MemoryUsage,
+		DiskUsage:        m.DiskUsage,
+	}
+}
+
 // GetSnapshot returns a snapshot of current metrics
 func (m *Metrics) GetSnapshot() map[string]interface{} {
 	m.mu.RLock()
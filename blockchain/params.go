@@ -0,0 +1,68 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/luxfi/sdk/network"
+)
+
+// CreateParamsFromChainConfigs converts the chains of a network topology,
+// as loaded by network.LoadTopology, into blockchain creation parameters
+func CreateParamsFromChainConfigs(configs []network.ChainConfig) ([]*CreateParams, error) {
+	params := make([]*CreateParams, 0, len(configs))
+	for _, cfg := range configs {
+		p, err := createParamsFromChainConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain %s: %w", cfg.Name, err)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func createParamsFromChainConfig(cfg network.ChainConfig) (*CreateParams, error) {
+	chainType := BlockchainType(cfg.Type)
+	switch chainType {
+	case "":
+		chainType = TypeL1
+	case TypeL1, TypeL2, TypeL3:
+	default:
+		return nil, fmt.Errorf("unsupported blockchain type: %s", cfg.Type)
+	}
+
+	vmType := VMType(cfg.VMType)
+	switch vmType {
+	case VMTypeEVM, VMTypeWASM, VMTypeCustom, VMTypeTokenVM, VMTypeMorpheusVM:
+	default:
+		return nil, fmt.Errorf("unsupported VM type: %s", cfg.VMType)
+	}
+
+	params := &CreateParams{
+		Name:        cfg.Name,
+		Type:        chainType,
+		VMType:      vmType,
+		Genesis:     slices.Clone(cfg.Genesis),
+		ChainConfig: slices.Clone(cfg.Config),
+	}
+
+	if cfg.ChainID != "" {
+		chainID, ok := new(big.Int).SetString(cfg.ChainID, 10)
+		if !ok || chainID.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid chain ID: %s", cfg.ChainID)
+		}
+		params.ChainID = chainID
+	}
+
+	for _, v := range cfg.Validators {
+		params.ValidatorSet = append(params.ValidatorSet, Validator{
+			NodeID: v.NodeID,
+			Weight: v.Weight,
+		})
+	}
+	return params, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateParamsFromChainConfigs(t *testing.T) {
+	params, err := CreateParamsFromChainConfigs([]network.ChainConfig{
+		{
+			Name:    "devchain",
+			VMType:  "evm",
+			ChainID: "96369",
+			Genesis: []byte(`{}`),
+			Config:  []byte(`{"log-level":"debug"}`),
+			Validators: []network.ChainValidator{
+				{NodeID: "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", Weight: 100},
+			},
+		},
+		{Name: "rollup", Type: "L2", VMType: "wasm"},
+	})
+	require.NoError(t, err)
+	require.Len(t, params, 2)
+
+	assert.Equal(t, TypeL1, params[0].Type)
+	assert.Equal(t, VMTypeEVM, params[0].VMType)
+	assert.Equal(t, big.NewInt(96369), params[0].ChainID)
+	assert.Equal(t, []byte(`{}`), params[0].Genesis)
+	assert.Equal(t, []byte(`{"log-level":"debug"}`), params[0].ChainConfig)
+	assert.Equal(t, []Validator{{NodeID: "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", Weight: 100}}, params[0].ValidatorSet)
+
+	assert.Equal(t, TypeL2, params[1].Type)
+	assert.Equal(t, VMTypeWASM, params[1].VMType)
+	assert.Nil(t, params[1].ChainID)
+
+	tests := []struct {
+		name   string
+		config network.ChainConfig
+		errMsg string
+	}{
+		{"unknown type", network.ChainConfig{Name: "c", Type: "L4", VMType: "evm"}, "unsupported blockchain type: L4"},
+		{"unknown vm", network.ChainConfig{Name: "c", VMType: "jvm"}, "unsupported VM type: jvm"},
+		{"invalid chain ID", network.ChainConfig{Name: "c", VMType: "evm", ChainID: "0x10"}, "invalid chain ID: 0x10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CreateParamsFromChainConfigs([]network.ChainConfig{tt.config})
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	// sequencerStopTimeout is how long a sequencer is given to exit after
+	// an interrupt before it is killed
+	sequencerStopTimeout = 5 * time.Second
+	// sequencerPollInterval is the interval between health checks while
+	// waiting for a sequencer to start
+	sequencerPollInterval = 100 * time.Millisecond
+)
+
+var (
+	// ErrNoSequencer is returned when a blockchain has no local sequencer
+	ErrNoSequencer = errors.New("no sequencer configured")
+	// ErrSequencerExited is returned by health checks of an exited sequencer
+	ErrSequencerExited = errors.New("sequencer exited")
+)
+
+// Sequencer is a handle on the local sequencer process of a centralized L2
+type Sequencer struct {
+	// Endpoint is the HTTP endpoint of the sequencer
+	Endpoint string
+
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// startSequencer starts a sequencer process serving HTTP on the port
+func startSequencer(binary string, args, env []string, port uint16) (*Sequencer, error) {
+	// the process outlives the deploy context, so it is not bound to it
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start sequencer: %w", err)
+	}
+
+	s := &Sequencer{
+		Endpoint: fmt.Sprintf("http://127.0.0.1:%d", port),
+		cmd:      cmd,
+		done:     make(chan struct{}),
+	}
+	go func() {
+		s.err = cmd.Wait()
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// Health checks the sequencer process is running and its health endpoint
+// responds
+func (s *Sequencer) Health(ctx context.Context) error {
+	select {
+	case <-s.done:
+		if s.err != nil {
+			return fmt.Errorf("%w: %w", ErrSequencerExited, s.err)
+		}
+		return ErrSequencerExited
+	default:
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Endpoint+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sequencer health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sequencer is unhealthy: %s", resp.Status)
+	}
+	return nil
+}
+
+// Done is closed when the sequencer process exits
+func (s *Sequencer) Done() <-chan struct{} {
+	return s.done
+}
+
+// Stop interrupts the sequencer and waits for it to exit, killing it if
+// it does not exit in time
+func (s *Sequencer) Stop() error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+
+	if err := s.cmd.Process.Signal(os.Interrupt); err != nil {
+		return s.kill()
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(sequencerStopTimeout):
+		return s.kill()
+	}
+}
+
+func (s *Sequencer) kill() error {
+	if err := s.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill sequencer: %w", err)
+	}
+	<-s.done
+	return nil
+}
+
+// waitHealthy polls the health of the sequencer until it is healthy,
+// exits or the timeout elapses
+func (s *Sequencer) waitHealthy(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(sequencerPollInterval)
+	defer ticker.Stop()
+	for {
+		err := s.Health(ctx)
+		if err == nil || errors.Is(err, ErrSequencerExited) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sequencer returns the local sequencer of a deployed centralized L2
+func (b *Builder) Sequencer(blockchainID string) (*Sequencer, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	sequencer, ok := b.sequencers[blockchainID]
+	if !ok {
+		return nil, fmt.Errorf("blockchain %s: %w", blockchainID, ErrNoSequencer)
+	}
+	return sequencer, nil
+}
+
+// StopSequencer stops the local sequencer of a deployed centralized L2
+func (b *Builder) StopSequencer(blockchainID string) error {
+	b.mu.Lock()
+	sequencer, ok := b.sequencers[blockchainID]
+	delete(b.sequencers, blockchainID)
+	b.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("blockchain %s: %w", blockchainID, ErrNoSequencer)
+	}
+	return sequencer.Stop()
+}
+
+// Close stops the local sequencers started by the builder
+func (b *Builder) Close() error {
+	b.mu.Lock()
+	sequencers := b.sequencers
+	b.sequencers = make(map[string]*Sequencer)
+	b.mu.Unlock()
+
+	var errs []error
+	for blockchainID, sequencer := range sequencers {
+		if err := sequencer.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop sequencer of %s: %w", blockchainID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// forgetSequencer drops the handle on a sequencer once its process exits,
+// unless it was replaced in the meantime
+func (b *Builder) forgetSequencer(blockchainID string, sequencer *Sequencer) {
+	<-sequencer.Done()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sequencers[blockchainID] == sequencer {
+		delete(b.sequencers, blockchainID)
+	}
+}
+
+// freePort returns a TCP port that is free on the loopback interface
+func freePort() (uint16, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer listener.Close()
+	return uint16(listener.Addr().(*net.TCPAddr).Port), nil
+}
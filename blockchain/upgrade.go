@@ -0,0 +1,352 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/chainconfig"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/network"
+)
+
+var (
+	// ErrNoUpgrade is returned when an upgrade is applied to a blockchain
+	// without a scheduled upgrade
+	ErrNoUpgrade = errors.New("no upgrade scheduled")
+	// ErrUpgradeInPast is returned when an upgrade activates before now
+	ErrUpgradeInPast = errors.New("upgrade scheduled in the past")
+	// ErrUpgradeOutOfOrder is returned when an upgrade activates before an
+	// upgrade listed ahead of it
+	ErrUpgradeOutOfOrder = errors.New("upgrade scheduled out of order")
+	// ErrLockedUpgradeChanged is returned when an upgrade file changes or
+	// drops upgrades of the lock file
+	ErrLockedUpgradeChanged = errors.New("locked upgrade changed")
+	// ErrNoNodeRestarter is returned when an upgrade is applied by a builder
+	// that cannot restart nodes
+	ErrNoNodeRestarter = errors.New("no node restarter configured")
+)
+
+// NodeRestarter restarts the nodes of a network with the upgrade bytes of
+// chains, keyed by blockchain ID. It is implemented by
+// network.NetworkManager.
+type NodeRestarter interface {
+	RestartNodeWithUpgrades(ctx context.Context, networkID, nodeID string, upgrades map[string][]byte) error
+}
+
+// UpgradeConfig is the upgrade.json of a subnet-EVM chain
+type UpgradeConfig struct {
+	PrecompileUpgrades []PrecompileUpgrade `json:"precompileUpgrades"`
+}
+
+// PrecompileUpgrade activates, reconfigures or deactivates a precompile at
+// a block timestamp
+type PrecompileUpgrade struct {
+	// Key is the config key of the precompile, such as feeManagerConfig
+	Key       string
+	Timestamp uint64
+	Disable   bool
+	// Config is the JSON config of the precompile
+	Config json.RawMessage
+}
+
+// MarshalJSON encodes the upgrade as an object keyed by the precompile
+func (u PrecompileUpgrade) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]json.RawMessage{u.Key: u.Config})
+}
+
+// UnmarshalJSON decodes an object holding the config of one precompile
+func (u *PrecompileUpgrade) UnmarshalJSON(data []byte) error {
+	var upgrade map[string]json.RawMessage
+	if err := json.Unmarshal(data, &upgrade); err != nil {
+		return err
+	}
+	if len(upgrade) != 1 {
+		return fmt.Errorf("precompile upgrade must configure one precompile, got %d", len(upgrade))
+	}
+	for key, config := range upgrade {
+		var header struct {
+			BlockTimestamp *uint64 `json:"blockTimestamp"`
+			Disable        bool    `json:"disable"`
+		}
+		if err := json.Unmarshal(config, &header); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if header.BlockTimestamp == nil {
+			return fmt.Errorf("invalid %s: missing blockTimestamp", key)
+		}
+		*u = PrecompileUpgrade{
+			Key:       key,
+			Timestamp: *header.BlockTimestamp,
+			Disable:   header.Disable,
+			Config:    config,
+		}
+	}
+	return nil
+}
+
+// equal reports whether both upgrades have the same config
+func (u PrecompileUpgrade) equal(other PrecompileUpgrade) bool {
+	var a, b bytes.Buffer
+	if u.Key != other.Key || json.Compact(&a, u.Config) != nil || json.Compact(&b, other.Config) != nil {
+		return false
+	}
+	return bytes.Equal(a.Bytes(), b.Bytes())
+}
+
+// ParseUpgrade decodes an upgrade.json
+func ParseUpgrade(data []byte) (*UpgradeConfig, error) {
+	upgrade := &UpgradeConfig{}
+	if err := json.Unmarshal(data, upgrade); err != nil {
+		return nil, fmt.Errorf("failed to decode upgrade: %w", err)
+	}
+	return upgrade, nil
+}
+
+// UpgradeBuilder schedules precompile activations and fee config changes
+// of a subnet-EVM chain after the upgrades of its lock file
+type UpgradeBuilder struct {
+	locked   []PrecompileUpgrade
+	upgrades []PrecompileUpgrade
+	now      time.Time
+	err      error
+}
+
+// NewUpgradeBuilder creates an upgrade builder validating against the
+// current time
+func NewUpgradeBuilder() *UpgradeBuilder {
+	return &UpgradeBuilder{now: time.Now()}
+}
+
+// WithLockFile sets the upgrades already applied to the chain, read from
+// its upgrade lock file. They are kept ahead of the scheduled upgrades.
+func (u *UpgradeBuilder) WithLockFile(data []byte) *UpgradeBuilder {
+	locked, err := ParseUpgrade(data)
+	if err != nil {
+		u.setErr(fmt.Errorf("invalid lock file: %w", err))
+		return u
+	}
+	u.locked = locked.PrecompileUpgrades
+	return u
+}
+
+// WithCurrentTime sets the time upgrades must be scheduled after
+func (u *UpgradeBuilder) WithCurrentTime(now time.Time) *UpgradeBuilder {
+	u.now = now
+	return u
+}
+
+// EnablePrecompile activates a precompile at the timestamp. Precompiles
+// guarded by an allow list are administered by the admins.
+func (u *UpgradeBuilder) EnablePrecompile(address common.Address, timestamp uint64, admins ...common.Address) *UpgradeBuilder {
+	if address != chainconfig.WarpAddress && len(admins) == 0 {
+		u.setErr(ErrNoAdmins)
+		return u
+	}
+	var config interface{}
+	allowList := chainconfig.NewAllowListConfig(timestamp, admins...)
+	switch address {
+	case chainconfig.WarpAddress:
+		config = chainconfig.NewWarpConfig(timestamp)
+	case chainconfig.NativeMinterAddress:
+		config = &chainconfig.NativeMinterConfig{AllowListConfig: *allowList}
+	case chainconfig.FeeManagerAddress:
+		config = &chainconfig.FeeManagerConfig{AllowListConfig: *allowList}
+	default:
+		config = allowList
+	}
+	return u.add(address, timestamp, false, config)
+}
+
+// DisablePrecompile deactivates a precompile at the timestamp
+func (u *UpgradeBuilder) DisablePrecompile(address common.Address, timestamp uint64) *UpgradeBuilder {
+	var config interface{} = &chainconfig.AllowListConfig{BlockTimestamp: &timestamp, Disable: true}
+	if address == chainconfig.WarpAddress {
+		config = &chainconfig.WarpConfig{BlockTimestamp: &timestamp, Disable: true}
+	}
+	return u.add(address, timestamp, true, config)
+}
+
+// UpdateFeeConfig activates the fee manager at the timestamp, replacing
+// the fee config of the chain
+func (u *UpgradeBuilder) UpdateFeeConfig(timestamp uint64, feeConfig commontype.FeeConfig, admins ...common.Address) *UpgradeBuilder {
+	if feeConfig.GasLimit == nil || feeConfig.GasLimit.Sign() <= 0 {
+		u.setErr(chainconfig.ErrInvalidGasLimit)
+		return u
+	}
+	if len(admins) == 0 {
+		u.setErr(ErrNoAdmins)
+		return u
+	}
+	config := &chainconfig.FeeManagerConfig{
+		AllowListConfig:  *chainconfig.NewAllowListConfig(timestamp, admins...),
+		InitialFeeConfig: &feeConfig,
+	}
+	return u.add(chainconfig.FeeManagerAddress, timestamp, false, config)
+}
+
+func (u *UpgradeBuilder) add(address common.Address, timestamp uint64, disable bool, config interface{}) *UpgradeBuilder {
+	key, ok := chainconfig.PrecompileConfigKey(address)
+	if !ok {
+		u.setErr(fmt.Errorf("%w: %s", chainconfig.ErrInvalidPrecompile, address.Hex()))
+		return u
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		u.setErr(fmt.Errorf("failed to encode %s: %w", key, err))
+		return u
+	}
+	u.upgrades = append(u.upgrades, PrecompileUpgrade{
+		Key:       key,
+		Timestamp: timestamp,
+		Disable:   disable,
+		Config:    data,
+	})
+	return u
+}
+
+func (u *UpgradeBuilder) setErr(err error) {
+	if u.err == nil {
+		u.err = err
+	}
+}
+
+// Build validates the scheduled upgrades and returns the upgrade.json
+// holding the locked upgrades followed by the scheduled ones
+func (u *UpgradeBuilder) Build() ([]byte, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	if len(u.upgrades) == 0 {
+		return nil, ErrNoUpgrade
+	}
+	upgrade := &UpgradeConfig{PrecompileUpgrades: slices.Concat(u.locked, u.upgrades)}
+	if err := validateUpgrade(upgrade, &UpgradeConfig{PrecompileUpgrades: u.locked}, u.now); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(upgrade, "", "  ")
+}
+
+// validateUpgrade checks an upgrade against the upgrades of the lock
+// file: the locked upgrades are kept unchanged, the others activate after
+// now, and every precompile is alternately enabled and disabled in the
+// order of activation
+func validateUpgrade(upgrade, locked *UpgradeConfig, now time.Time) error {
+	if len(upgrade.PrecompileUpgrades) < len(locked.PrecompileUpgrades) {
+		return fmt.Errorf("%w: %d locked upgrades, %d in the upgrade file",
+			ErrLockedUpgradeChanged, len(locked.PrecompileUpgrades), len(upgrade.PrecompileUpgrades))
+	}
+
+	var errs []error
+	var last uint64
+	// previous is the last upgrade of each precompile
+	previous := make(map[string]PrecompileUpgrade)
+	for i, precompile := range upgrade.PrecompileUpgrades {
+		path := fmt.Sprintf("$.precompileUpgrades[%d]", i)
+		if i < len(locked.PrecompileUpgrades) {
+			if !precompile.equal(locked.PrecompileUpgrades[i]) {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrLockedUpgradeChanged, fieldError(path, "differs from the lock file")))
+			}
+		} else if int64(precompile.Timestamp) <= now.Unix() {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrUpgradeInPast, fieldError(path+"."+precompile.Key+".blockTimestamp",
+				"%d is not after %d", precompile.Timestamp, now.Unix())))
+		}
+
+		if precompile.Timestamp < last {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrUpgradeOutOfOrder, fieldError(path+"."+precompile.Key+".blockTimestamp",
+				"%d is before the previous upgrade at %d", precompile.Timestamp, last)))
+		}
+		last = max(last, precompile.Timestamp)
+
+		if prev, ok := previous[precompile.Key]; ok {
+			// a precompile changes at most once per block
+			if precompile.Timestamp == prev.Timestamp {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrUpgradeOutOfOrder, fieldError(path+"."+precompile.Key+".blockTimestamp",
+					"%s is already upgraded at %d", precompile.Key, prev.Timestamp)))
+			}
+			if precompile.Disable == prev.Disable {
+				state := "enabled"
+				if precompile.Disable {
+					state = "disabled"
+				}
+				errs = append(errs, fieldError(path, "%s is already %s", precompile.Key, state))
+			}
+		}
+		previous[precompile.Key] = precompile
+	}
+	return errors.Join(errs...)
+}
+
+// SetNodeRestarter sets the restarter of the nodes upgrades are applied to
+func (b *Builder) SetNodeRestarter(restarter NodeRestarter) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.restarter = restarter
+}
+
+// ApplyUpgrade validates the scheduled upgrade of a deployed chain against
+// its lock and restarts every node of the network with it. The applied
+// upgrade becomes the new lock.
+func (b *Builder) ApplyUpgrade(ctx context.Context, blockchain *Blockchain, network *network.Network) error {
+	b.mu.RLock()
+	restarter := b.restarter
+	b.mu.RUnlock()
+	if restarter == nil {
+		return ErrNoNodeRestarter
+	}
+	if blockchain.BlockchainID == types.Empty {
+		return fmt.Errorf("blockchain %s is not deployed", blockchain.Name)
+	}
+	if len(blockchain.Upgrade) == 0 {
+		return fmt.Errorf("failed to upgrade %s: %w", blockchain.Name, ErrNoUpgrade)
+	}
+	if len(network.Nodes) == 0 {
+		return fmt.Errorf("failed to upgrade %s on network %s: %w", blockchain.Name, network.ID, ErrNoNodes)
+	}
+
+	upgrade, err := ParseUpgrade(blockchain.Upgrade)
+	if err != nil {
+		return err
+	}
+	locked := &UpgradeConfig{}
+	if len(blockchain.UpgradeLock) > 0 {
+		if locked, err = ParseUpgrade(blockchain.UpgradeLock); err != nil {
+			return fmt.Errorf("invalid lock file: %w", err)
+		}
+	}
+	if err := validateUpgrade(upgrade, locked, time.Now()); err != nil {
+		return err
+	}
+
+	upgrades := map[string][]byte{blockchain.BlockchainID.CB58(): blockchain.Upgrade}
+	for _, node := range network.Nodes {
+		if err := restarter.RestartNodeWithUpgrades(ctx, network.ID, node.ID, upgrades); err != nil {
+			return fmt.Errorf("failed to restart node %s: %w", node.ID, err)
+		}
+	}
+	b.logger.Info("applied upgrade", "chain", blockchain.Name, "network", network.ID, "upgrades", len(upgrade.PrecompileUpgrades))
+
+	blockchain.UpgradeLock = slices.Clone(blockchain.Upgrade)
+	b.store(blockchain)
+
+	if blockchain.VMType != VMTypeEVM || blockchain.RPCURL == "" {
+		return nil
+	}
+	timeout, err := bootstrapTimeout(ctx)
+	if err != nil {
+		return fmt.Errorf("chain %s did not bootstrap after the upgrade: %w", blockchain.BlockchainID.CB58(), err)
+	}
+	if err := b.waitBootstrapped(blockchain.RPCURL, timeout); err != nil {
+		return fmt.Errorf("chain %s did not bootstrap after the upgrade: %w", blockchain.BlockchainID.CB58(), err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/chainconfig"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeBuilder_Build(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	ts := uint64(now.Unix())
+	feeConfig := commontype.FeeConfig{GasLimit: big.NewInt(15_000_000), MinBaseFee: big.NewInt(25_000_000_000)}
+
+	data, err := NewUpgradeBuilder().
+		WithCurrentTime(now).
+		EnablePrecompile(chainconfig.TxAllowListAddress, ts+100, alice).
+		UpdateFeeConfig(ts+200, feeConfig, bob).
+		DisablePrecompile(chainconfig.TxAllowListAddress, ts+300).
+		Build()
+	require.NoError(t, err)
+
+	upgrade, err := ParseUpgrade(data)
+	require.NoError(t, err)
+	require.Len(t, upgrade.PrecompileUpgrades, 3)
+	assert.Equal(t, "txAllowListConfig", upgrade.PrecompileUpgrades[0].Key)
+	assert.Equal(t, ts+100, upgrade.PrecompileUpgrades[0].Timestamp)
+	assert.True(t, upgrade.PrecompileUpgrades[2].Disable)
+	assert.JSONEq(t, `{"blockTimestamp":1700000300,"disable":true}`, string(upgrade.PrecompileUpgrades[2].Config))
+
+	var feeManager chainconfig.FeeManagerConfig
+	require.Equal(t, "feeManagerConfig", upgrade.PrecompileUpgrades[1].Key)
+	require.NoError(t, json.Unmarshal(upgrade.PrecompileUpgrades[1].Config, &feeManager))
+	assert.Equal(t, []common.Address{bob}, feeManager.AdminAddresses)
+	assert.Equal(t, &feeConfig, feeManager.InitialFeeConfig)
+
+	// new upgrades are scheduled after the locked ones
+	data, err = NewUpgradeBuilder().
+		WithCurrentTime(now.Add(time.Hour)).
+		WithLockFile(data).
+		EnablePrecompile(chainconfig.TxAllowListAddress, ts+7200, alice).
+		Build()
+	require.NoError(t, err)
+	upgrade, err = ParseUpgrade(data)
+	require.NoError(t, err)
+	assert.Len(t, upgrade.PrecompileUpgrades, 4)
+}
+
+func TestUpgradeBuilder_Errors(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	ts := uint64(now.Unix())
+	locked, err := NewUpgradeBuilder().
+		WithCurrentTime(now).
+		EnablePrecompile(chainconfig.NativeMinterAddress, ts+300, alice).
+		Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		upgrade func(u *UpgradeBuilder) *UpgradeBuilder
+		err     error
+		errMsg  string
+	}{
+		{
+			name:    "no upgrade",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder { return u },
+			err:     ErrNoUpgrade,
+		},
+		{
+			name: "in the past",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.EnablePrecompile(chainconfig.WarpAddress, ts)
+			},
+			err:    ErrUpgradeInPast,
+			errMsg: "$.precompileUpgrades[0].warpConfig.blockTimestamp: 1700000000 is not after 1700000000",
+		},
+		{
+			name: "out of order",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.EnablePrecompile(chainconfig.TxAllowListAddress, ts+200, alice).
+					EnablePrecompile(chainconfig.ContractDeployerAllowListAddress, ts+100, alice)
+			},
+			err:    ErrUpgradeOutOfOrder,
+			errMsg: "$.precompileUpgrades[1].contractDeployerAllowListConfig.blockTimestamp: 1700000100 is before the previous upgrade at 1700000200",
+		},
+		{
+			name: "before the lock file",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.WithLockFile(locked).DisablePrecompile(chainconfig.NativeMinterAddress, ts+250)
+			},
+			err: ErrUpgradeOutOfOrder,
+		},
+		{
+			name: "already enabled",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.WithLockFile(locked).EnablePrecompile(chainconfig.NativeMinterAddress, ts+400, bob)
+			},
+			errMsg: "$.precompileUpgrades[1]: contractNativeMinterConfig is already enabled",
+		},
+		{
+			name: "same block",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.WithLockFile(locked).DisablePrecompile(chainconfig.NativeMinterAddress, ts+300)
+			},
+			err:    ErrUpgradeOutOfOrder,
+			errMsg: "contractNativeMinterConfig is already upgraded at 1700000300",
+		},
+		{
+			name: "no admins",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.EnablePrecompile(chainconfig.RewardManagerAddress, ts+100)
+			},
+			err: ErrNoAdmins,
+		},
+		{
+			name: "no gas limit",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.UpdateFeeConfig(ts+100, commontype.FeeConfig{}, alice)
+			},
+			err: chainconfig.ErrInvalidGasLimit,
+		},
+		{
+			name: "unknown precompile",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.EnablePrecompile(common.HexToAddress("0x0300000000000000000000000000000000000000"), ts+100, alice)
+			},
+			err: chainconfig.ErrInvalidPrecompile,
+		},
+		{
+			name: "invalid lock file",
+			upgrade: func(u *UpgradeBuilder) *UpgradeBuilder {
+				return u.WithLockFile([]byte(`{"precompileUpgrades":[{}]}`)).EnablePrecompile(chainconfig.WarpAddress, ts+100)
+			},
+			errMsg: "invalid lock file: failed to decode upgrade: precompile upgrade must configure one precompile, got 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.upgrade(NewUpgradeBuilder().WithCurrentTime(now)).Build()
+			assert.Nil(t, data)
+			require.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// fakeRestarter records the nodes it restarts and their upgrades
+type fakeRestarter struct {
+	mu        sync.Mutex
+	restarted []string
+	upgrades  []map[string][]byte
+	err       error
+}
+
+func (r *fakeRestarter) RestartNodeWithUpgrades(_ context.Context, networkID, nodeID string, upgrades map[string][]byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.restarted = append(r.restarted, networkID+"/"+nodeID)
+	r.upgrades = append(r.upgrades, upgrades)
+	return nil
+}
+
+func TestBuilder_ApplyUpgrade(t *testing.T) {
+	builder := NewBuilder(log.NewNoOpLogger())
+	restarter := &fakeRestarter{}
+	builder.SetNodeRestarter(restarter)
+	var waited []string
+	builder.waitBootstrapped = func(rpcURL string, _ time.Duration) error {
+		waited = append(waited, rpcURL)
+		return nil
+	}
+	ctx := context.Background()
+
+	bc, err := builder.CreateBlockchain(ctx, &CreateParams{Name: "upgraded", Type: TypeL1, VMType: VMTypeEVM, ChainID: big.NewInt(99)})
+	require.NoError(t, err)
+	bc.BlockchainID = types.ID{9}
+	bc.RPCURL = "http://127.0.0.1:9650/ext/bc/upgraded/rpc"
+
+	testNetwork := &network.Network{
+		ID:    "local",
+		Nodes: []*network.Node{{ID: "node1"}, {ID: "node2"}},
+	}
+	activation := uint64(time.Now().Add(time.Hour).Unix())
+	bc.Upgrade, err = NewUpgradeBuilder().EnablePrecompile(chainconfig.FeeManagerAddress, activation, alice).Build()
+	require.NoError(t, err)
+
+	require.NoError(t, builder.ApplyUpgrade(ctx, bc, testNetwork))
+	assert.Equal(t, []string{"local/node1", "local/node2"}, restarter.restarted)
+	upgrades := map[string][]byte{bc.BlockchainID.CB58(): bc.Upgrade}
+	assert.Equal(t, []map[string][]byte{upgrades, upgrades}, restarter.upgrades)
+	assert.Equal(t, []string{bc.RPCURL}, waited)
+
+	stored, err := builder.GetBlockchain(bc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, bc.Upgrade, stored.UpgradeLock)
+
+	// the applied upgrade is locked
+	bc.Upgrade, err = NewUpgradeBuilder().EnablePrecompile(chainconfig.TxAllowListAddress, activation+60, alice).Build()
+	require.NoError(t, err)
+	assert.ErrorIs(t, builder.ApplyUpgrade(ctx, bc, testNetwork), ErrLockedUpgradeChanged)
+
+	bc.Upgrade, err = NewUpgradeBuilder().WithLockFile(stored.UpgradeLock).
+		EnablePrecompile(chainconfig.TxAllowListAddress, activation+60, alice).Build()
+	require.NoError(t, err)
+	require.NoError(t, builder.ApplyUpgrade(ctx, bc, testNetwork))
+	assert.Len(t, restarter.restarted, 4)
+}
+
+func TestBuilder_ApplyUpgradeErrors(t *testing.T) {
+	ctx := context.Background()
+	activation := uint64(time.Now().Add(time.Hour).Unix())
+	upgrade, err := NewUpgradeBuilder().EnablePrecompile(chainconfig.WarpAddress, activation).Build()
+	require.NoError(t, err)
+	testNetwork := &network.Network{ID: "local", Nodes: []*network.Node{{ID: "node1"}}}
+
+	tests := []struct {
+		name       string
+		restarter  NodeRestarter
+		blockchain *Blockchain
+		network    *network.Network
+		err        error
+		errMsg     string
+	}{
+		{
+			name:       "no restarter",
+			blockchain: &Blockchain{Name: "chain", BlockchainID: types.ID{1}, Upgrade: upgrade},
+			network:    testNetwork,
+			err:        ErrNoNodeRestarter,
+		},
+		{
+			name:       "not deployed",
+			restarter:  &fakeRestarter{},
+			blockchain: &Blockchain{Name: "chain", Upgrade: upgrade},
+			network:    testNetwork,
+			errMsg:     "blockchain chain is not deployed",
+		},
+		{
+			name:       "no upgrade",
+			restarter:  &fakeRestarter{},
+			blockchain: &Blockchain{Name: "chain", BlockchainID: types.ID{1}},
+			network:    testNetwork,
+			err:        ErrNoUpgrade,
+		},
+		{
+			name:       "no nodes",
+			restarter:  &fakeRestarter{},
+			blockchain: &Blockchain{Name: "chain", BlockchainID: types.ID{1}, Upgrade: upgrade},
+			network:    &network.Network{ID: "empty"},
+			err:        ErrNoNodes,
+		},
+		{
+			name:       "restart failure",
+			restarter:  &fakeRestarter{err: errors.New("node crashed")},
+			blockchain: &Blockchain{Name: "chain", BlockchainID: types.ID{1}, Upgrade: upgrade},
+			network:    testNetwork,
+			errMsg:     "failed to restart node node1: node crashed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBuilder(log.NewNoOpLogger())
+			if tt.restarter != nil {
+				builder.SetNodeRestarter(tt.restarter)
+			}
+			err := builder.ApplyUpgrade(ctx, tt.blockchain, tt.network)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+			assert.Empty(t, tt.blockchain.UpgradeLock)
+		})
+	}
+}
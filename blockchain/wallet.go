@@ -0,0 +1,244 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/utils/constants"
+	"github.com/luxfi/node/vms/platformvm"
+	"github.com/luxfi/node/vms/platformvm/fx"
+	"github.com/luxfi/node/vms/platformvm/txs"
+	"github.com/luxfi/node/vms/secp256k1fx"
+	pwallet "github.com/luxfi/node/wallet/chain/p"
+	pbuilder "github.com/luxfi/node/wallet/chain/p/builder"
+	psigner "github.com/luxfi/node/wallet/chain/p/signer"
+	"github.com/luxfi/node/wallet/subnet/primary"
+	"github.com/luxfi/node/wallet/subnet/primary/common"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/luxfi/sdk/network"
+)
+
+// subnetValidatorStartDelay is how far in the future subnet validations
+// start, so the transaction is accepted before its start time
+const subnetValidatorStartDelay = 30 * time.Second
+
+var (
+	_ PChainWallet    = (*NodeWallet)(nil)
+	_ PChainTxBuilder = (*NodeWallet)(nil)
+)
+
+// walletFactory returns the P-Chain wallet of a keychain on the node
+// serving the API at uri
+type walletFactory func(ctx context.Context, uri string, keychain *secp256k1fx.Keychain) (PChainWallet, error)
+
+// newNodeWallet is the walletFactory of NodeWallets
+func newNodeWallet(ctx context.Context, uri string, keychain *secp256k1fx.Keychain) (PChainWallet, error) {
+	return NewNodeWallet(ctx, uri, keychain)
+}
+
+// SetKeychain sets the keychain paying for the L1s deployed without a
+// wallet of their own. Their transactions are issued by a NodeWallet on
+// the first node of the network.
+func (b *Builder) SetKeychain(keychain *secp256k1fx.Keychain) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keychain = keychain
+}
+
+// pChainWallet returns the wallet deploying a chain to a network, its own
+// wallet or the one of the builder keychain
+func (b *Builder) pChainWallet(ctx context.Context, blockchain *Blockchain, network *network.Network) (PChainWallet, error) {
+	if blockchain.wallet != nil {
+		return blockchain.wallet, nil
+	}
+	b.mu.RLock()
+	keychain := b.keychain
+	b.mu.RUnlock()
+	if keychain == nil {
+		return nil, ErrNoWallet
+	}
+	if len(network.Nodes) == 0 {
+		return nil, fmt.Errorf("failed to sync P-Chain wallet with network %s: %w", network.ID, ErrNoNodes)
+	}
+	return b.newWallet(ctx, network.Nodes[0].Endpoint, keychain)
+}
+
+// pChainClient reads the current validators of the P-Chain
+type pChainClient interface {
+	GetCurrentValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID) ([]platformvm.ClientPermissionlessValidator, error)
+}
+
+// nodePChainClient is the pChainClient of the node serving the API at uri
+type nodePChainClient struct {
+	uri string
+}
+
+// GetCurrentValidators returns the current validators of a subnet among nodeIDs
+func (c nodePChainClient) GetCurrentValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID) ([]platformvm.ClientPermissionlessValidator, error) {
+	return platformvm.NewClient(c.uri).GetCurrentValidators(ctx, subnetID, nodeIDs)
+}
+
+// NodeWallet is the PChainWallet of a keychain, issuing transactions
+// through the P-Chain wallet of a node. The keychain pays the transaction
+// fees and owns the subnets it creates.
+type NodeWallet struct {
+	uri      string
+	keychain *secp256k1fx.Keychain
+	owner    *secp256k1fx.OutputOwners
+	wallet   pwallet.Wallet
+	client   pChainClient
+}
+
+// NewNodeWallet syncs the P-Chain wallet of a keychain with the node
+// serving the API at uri
+func NewNodeWallet(ctx context.Context, uri string, keychain *secp256k1fx.Keychain) (*NodeWallet, error) {
+	wallet, err := primary.MakeWallet(ctx, uri, keychain, keychain, primary.WalletConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync P-Chain wallet with %s: %w", uri, err)
+	}
+	return &NodeWallet{
+		uri:      uri,
+		keychain: keychain,
+		owner: &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     keychain.Addresses().List(),
+		},
+		wallet: wallet.P(),
+		client: nodePChainClient{uri: uri},
+	}, nil
+}
+
+// IssueCreateSubnetTx creates a subnet owned by the keychain
+func (w *NodeWallet) IssueCreateSubnetTx(ctx context.Context) (types.ID, error) {
+	tx, err := w.wallet.IssueCreateSubnetTx(w.owner, common.WithContext(ctx))
+	if err != nil {
+		return types.ID{}, err
+	}
+	return types.ID(tx.ID()), nil
+}
+
+// IssueAddSubnetValidatorTx adds a primary network validator to the subnet
+// for the rest of its primary network validation
+func (w *NodeWallet) IssueAddSubnetValidatorTx(ctx context.Context, subnetID types.ID, validator Validator) (types.ID, error) {
+	nodeID, err := ids.NodeIDFromString(validator.NodeID)
+	if err != nil {
+		return types.ID{}, fmt.Errorf("invalid node ID %q: %w", validator.NodeID, err)
+	}
+	primaryValidators, err := w.client.GetCurrentValidators(ctx, constants.PrimaryNetworkID, []ids.NodeID{nodeID})
+	if err != nil {
+		return types.ID{}, fmt.Errorf("failed to get primary network validator %s: %w", nodeID, err)
+	}
+	if len(primaryValidators) == 0 {
+		return types.ID{}, fmt.Errorf("node %s is not a primary network validator", nodeID)
+	}
+
+	tx, err := w.wallet.IssueAddSubnetValidatorTx(&txs.SubnetValidator{
+		Validator: txs.Validator{
+			NodeID: nodeID,
+			Start:  uint64(time.Now().Add(subnetValidatorStartDelay).Unix()),
+			End:    primaryValidators[0].EndTime,
+			Wght:   validator.Weight,
+		},
+		Subnet: ids.ID(subnetID),
+	}, common.WithContext(ctx))
+	if err != nil {
+		return types.ID{}, err
+	}
+	return types.ID(tx.ID()), nil
+}
+
+// IssueCreateChainTx creates a chain running the VM in the subnet
+func (w *NodeWallet) IssueCreateChainTx(ctx context.Context, subnetID types.ID, genesis []byte, vmID types.ID, chainName string) (types.ID, error) {
+	tx, err := w.wallet.IssueCreateChainTx(ids.ID(subnetID), genesis, ids.ID(vmID), nil, chainName, common.WithContext(ctx))
+	if err != nil {
+		return types.ID{}, err
+	}
+	return types.ID(tx.ID()), nil
+}
+
+// BuildCreateSubnetTx signs the subnet transaction IssueCreateSubnetTx
+// would issue, without issuing it
+func (w *NodeWallet) BuildCreateSubnetTx(ctx context.Context) (types.ID, error) {
+	planner, err := w.planner(ctx)
+	if err != nil {
+		return types.ID{}, err
+	}
+	tx, err := planner.createSubnetTx(ctx, w.owner)
+	if err != nil {
+		return types.ID{}, err
+	}
+	return types.ID(tx.ID()), nil
+}
+
+// BuildCreateChainTx signs the chain transaction IssueCreateChainTx would
+// issue once the subnet transaction predicted by BuildCreateSubnetTx is
+// accepted, without issuing either
+func (w *NodeWallet) BuildCreateChainTx(ctx context.Context, subnetID types.ID, genesis []byte, vmID types.ID, chainName string) (types.ID, error) {
+	planner, err := w.planner(ctx)
+	if err != nil {
+		return types.ID{}, err
+	}
+	subnetTx, err := planner.createSubnetTx(ctx, w.owner)
+	if err != nil {
+		return types.ID{}, err
+	}
+	if types.ID(subnetTx.ID()) != subnetID {
+		return types.ID{}, fmt.Errorf("subnet %s is not the subnet the wallet would create", subnetID.CB58())
+	}
+	// the chain spends the outputs left by the subnet transaction
+	if err := planner.backend.AcceptTx(ctx, subnetTx); err != nil {
+		return types.ID{}, fmt.Errorf("failed to apply subnet tx: %w", err)
+	}
+
+	utx, err := planner.builder.NewCreateChainTx(ids.ID(subnetID), genesis, ids.ID(vmID), nil, chainName, common.WithContext(ctx))
+	if err != nil {
+		return types.ID{}, fmt.Errorf("failed to build chain tx: %w", err)
+	}
+	tx, err := psigner.SignUnsigned(ctx, planner.signer, utx)
+	if err != nil {
+		return types.ID{}, fmt.Errorf("failed to sign chain tx: %w", err)
+	}
+	return types.ID(tx.ID()), nil
+}
+
+// txPlanner builds and signs transactions against a private copy of the
+// wallet state, so nothing it builds is issued or spent
+type txPlanner struct {
+	backend pwallet.Backend
+	builder pbuilder.Builder
+	signer  psigner.Signer
+}
+
+// planner fetches the current P-Chain state of the keychain
+func (w *NodeWallet) planner(ctx context.Context) (*txPlanner, error) {
+	addrs := w.keychain.Addresses()
+	state, err := primary.FetchState(ctx, w.uri, addrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch P-Chain state from %s: %w", w.uri, err)
+	}
+	utxos := common.NewChainUTXOs(constants.PlatformChainID, state.UTXOs)
+	backend := pwallet.NewBackend(utxos, make(map[ids.ID]fx.Owner))
+	return &txPlanner{
+		backend: backend,
+		builder: pbuilder.New(addrs, state.PCTX, backend),
+		signer:  psigner.New(w.keychain, backend),
+	}, nil
+}
+
+// createSubnetTx builds and signs a subnet transaction
+func (p *txPlanner) createSubnetTx(ctx context.Context, owner *secp256k1fx.OutputOwners) (*txs.Tx, error) {
+	utx, err := p.builder.NewCreateSubnetTx(owner, common.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build subnet tx: %w", err)
+	}
+	tx, err := psigner.SignUnsigned(ctx, p.signer, utx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign subnet tx: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/utils/constants"
+	"github.com/luxfi/node/vms/platformvm"
+	"github.com/luxfi/node/vms/platformvm/txs"
+	"github.com/luxfi/node/vms/secp256k1fx"
+	pwallet "github.com/luxfi/node/wallet/chain/p"
+	"github.com/luxfi/node/wallet/subnet/primary/common"
+	"github.com/luxfi/sdk/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePChainClient serves the primary network validators of its end times
+type fakePChainClient struct {
+	endTimes  map[ids.NodeID]uint64
+	err       error
+	subnetIDs []ids.ID
+}
+
+func (c *fakePChainClient) GetCurrentValidators(_ context.Context, subnetID ids.ID, nodeIDs []ids.NodeID) ([]platformvm.ClientPermissionlessValidator, error) {
+	c.subnetIDs = append(c.subnetIDs, subnetID)
+	if c.err != nil {
+		return nil, c.err
+	}
+	var validators []platformvm.ClientPermissionlessValidator
+	for _, nodeID := range nodeIDs {
+		if endTime, ok := c.endTimes[nodeID]; ok {
+			validators = append(validators, platformvm.ClientPermissionlessValidator{
+				ClientStaker: platformvm.ClientStaker{EndTime: endTime},
+			})
+		}
+	}
+	return validators, nil
+}
+
+// fakeNodePWallet records the transactions issued through the P-Chain
+// wallet of a node. Other methods of the wallet are not used by NodeWallet.
+type fakeNodePWallet struct {
+	pwallet.Wallet
+	owners     []*secp256k1fx.OutputOwners
+	validators []*txs.SubnetValidator
+}
+
+func (w *fakeNodePWallet) IssueCreateSubnetTx(owner *secp256k1fx.OutputOwners, _ ...common.Option) (*txs.Tx, error) {
+	w.owners = append(w.owners, owner)
+	return &txs.Tx{}, nil
+}
+
+func (w *fakeNodePWallet) IssueAddSubnetValidatorTx(validator *txs.SubnetValidator, _ ...common.Option) (*txs.Tx, error) {
+	w.validators = append(w.validators, validator)
+	return &txs.Tx{}, nil
+}
+
+func TestNodeWallet_IssueCreateSubnetTx(t *testing.T) {
+	owner := &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{{1}}}
+	wallet := &fakeNodePWallet{}
+	w := &NodeWallet{owner: owner, wallet: wallet, client: &fakePChainClient{}}
+
+	_, err := w.IssueCreateSubnetTx(context.Background())
+	require.NoError(t, err)
+	// the keychain owns the subnet
+	require.Len(t, wallet.owners, 1)
+	assert.Same(t, owner, wallet.owners[0])
+}
+
+func TestNodeWallet_IssueAddSubnetValidatorTx(t *testing.T) {
+	validatorID := ids.GenerateTestNodeID()
+	subnetID := types.ID{7}
+	endTime := uint64(time.Now().Add(24 * time.Hour).Unix())
+	tests := []struct {
+		name   string
+		nodeID string
+		err    error
+		errMsg string
+	}{
+		{
+			name:   "primary network validator",
+			nodeID: validatorID.String(),
+		},
+		{
+			name:   "not a validator",
+			nodeID: ids.GenerateTestNodeID().String(),
+			errMsg: "is not a primary network validator",
+		},
+		{
+			name:   "client error",
+			nodeID: validatorID.String(),
+			err:    errors.New("connection refused"),
+			errMsg: "failed to get primary network validator",
+		},
+		{
+			name:   "invalid node ID",
+			nodeID: "node1",
+			errMsg: `invalid node ID "node1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakePChainClient{
+				endTimes: map[ids.NodeID]uint64{validatorID: endTime},
+				err:      tt.err,
+			}
+			wallet := &fakeNodePWallet{}
+			w := &NodeWallet{wallet: wallet, client: client}
+
+			start := time.Now()
+			_, err := w.IssueAddSubnetValidatorTx(context.Background(), subnetID, Validator{NodeID: tt.nodeID, Weight: 20})
+			if tt.errMsg != "" {
+				require.ErrorContains(t, err, tt.errMsg)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				}
+				assert.Empty(t, wallet.validators)
+				return
+			}
+			require.NoError(t, err)
+
+			// the validation is looked up in the primary network
+			assert.Equal(t, []ids.ID{constants.PrimaryNetworkID}, client.subnetIDs)
+			// and the subnet validation starts soon and ends with it
+			require.Len(t, wallet.validators, 1)
+			validator := wallet.validators[0]
+			assert.Equal(t, ids.ID(subnetID), validator.Subnet)
+			assert.Equal(t, validatorID, validator.NodeID)
+			assert.Equal(t, uint64(20), validator.Wght)
+			assert.Equal(t, endTime, validator.End)
+			assert.GreaterOrEqual(t, validator.Start, uint64(start.Add(subnetValidatorStartDelay).Unix()))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package chainconfig
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 
 	"github.com/luxfi/evm/core"
@@ -16,6 +17,8 @@ import (
 // GenesisBuilder helps construct EVM genesis configurations
 type GenesisBuilder struct {
 	genesis *core.Genesis
+	// chainConfig holds the subnet-EVM settings params.ChainConfig lacks
+	chainConfig *ChainConfigBuilder
 }
 
 // NewGenesisBuilder creates a new genesis builder with defaults
@@ -36,6 +39,14 @@ func (b *GenesisBuilder) WithChainConfig(config *params.ChainConfig) *GenesisBui
 	return b
 }
 
+// WithChainConfigBuilder sets the chain configuration built by the builder,
+// including its fee config, precompiles and network upgrades
+func (b *GenesisBuilder) WithChainConfigBuilder(builder *ChainConfigBuilder) *GenesisBuilder {
+	b.genesis.Config = builder.Build()
+	b.chainConfig = builder
+	return b
+}
+
 // WithGasLimit sets the gas limit
 func (b *GenesisBuilder) WithGasLimit(gasLimit uint64) *GenesisBuilder {
 	b.genesis.GasLimit = gasLimit
@@ -96,9 +107,47 @@ func (b *GenesisBuilder) Build() *core.Genesis {
 	return b.genesis
 }
 
-// ToJSON converts the genesis to JSON bytes
+// ToJSON converts the genesis to JSON bytes. The settings of a chain config
+// builder are added to the chain config.
 func (b *GenesisBuilder) ToJSON() ([]byte, error) {
-	return json.MarshalIndent(b.genesis, "", "  ")
+	if b.chainConfig == nil {
+		return json.MarshalIndent(b.genesis, "", "  ")
+	}
+
+	data, err := json.Marshal(b.genesis)
+	if err != nil {
+		return nil, err
+	}
+	var genesis map[string]json.RawMessage
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, err
+	}
+	var config map[string]any
+	if err := json.Unmarshal(genesis["config"], &config); err != nil {
+		return nil, err
+	}
+
+	if feeConfig := b.chainConfig.GetFeeConfig(); feeConfig != nil {
+		config["feeConfig"] = feeConfig
+	}
+	if b.chainConfig.GetAllowFeeRecipients() {
+		config["allowFeeRecipients"] = true
+	}
+	for address, precompile := range b.chainConfig.GetPrecompiles() {
+		key, ok := PrecompileConfigKey(common.HexToAddress(address))
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown precompile %s", ErrInvalidPrecompile, address)
+		}
+		config[key] = precompile
+	}
+	for name, timestamp := range b.chainConfig.GetNetworkUpgrades() {
+		config[name] = timestamp
+	}
+
+	if genesis["config"], err = json.Marshal(config); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(genesis, "", "  ")
 }
 
 // DefaultGenesis creates a default genesis configuration
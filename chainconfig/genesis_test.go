@@ -0,0 +1,61 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainconfig
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/sdk/fees"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesisBuilder_ToJSON(t *testing.T) {
+	admin := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	t.Run("with chain config builder", func(t *testing.T) {
+		configBuilder := NewChainConfigBuilder().
+			WithChainID(big.NewInt(12345)).
+			WithFeeConfig(fees.MediumThroughputConfig).
+			WithAllowFeeRecipients(true).
+			WithNetworkUpgrade("durangoTimestamp", big.NewInt(0)).
+			WithPrecompile(WarpAddress, NewWarpConfig(0)).
+			WithPrecompile(TxAllowListAddress, NewAllowListConfig(0, admin))
+
+		data, err := NewGenesisBuilder().
+			WithChainConfigBuilder(configBuilder).
+			WithAllocation(admin, big.NewInt(1000)).
+			ToJSON()
+		require.NoError(t, err)
+
+		var genesis struct {
+			Config map[string]json.RawMessage `json:"config"`
+		}
+		require.NoError(t, json.Unmarshal(data, &genesis))
+		require.JSONEq(t, `12345`, string(genesis.Config["chainId"]))
+		require.JSONEq(t, `0`, string(genesis.Config["durangoTimestamp"]))
+		require.JSONEq(t, `true`, string(genesis.Config["allowFeeRecipients"]))
+		require.JSONEq(t, `{"blockTimestamp":0,"quorumNumerator":67}`, string(genesis.Config["warpConfig"]))
+		require.JSONEq(t, `{"blockTimestamp":0,"adminAddresses":["`+admin.Hex()+`"]}`, string(genesis.Config["txAllowListConfig"]))
+
+		feeConfig, err := json.Marshal(fees.MediumThroughputConfig)
+		require.NoError(t, err)
+		require.JSONEq(t, string(feeConfig), string(genesis.Config["feeConfig"]))
+
+		parsed, err := ParseGenesis(data)
+		require.NoError(t, err)
+		require.NoError(t, ValidateGenesis(parsed))
+		require.Equal(t, big.NewInt(1000), parsed.Alloc[admin].Balance)
+	})
+
+	t.Run("unknown precompile", func(t *testing.T) {
+		configBuilder := NewChainConfigBuilder().
+			WithPrecompile(common.HexToAddress("0x0100000000000000000000000000000000000000"), map[string]interface{}{})
+
+		_, err := NewGenesisBuilder().WithChainConfigBuilder(configBuilder).ToJSON()
+		require.ErrorIs(t, err, ErrInvalidPrecompile)
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2022-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chainconfig
+
+import (
+	"math/big"
+
+	"github.com/luxfi/evm/commontype"
+	"github.com/luxfi/geth/common"
+)
+
+// Addresses of the stateful precompiles configurable at genesis
+var (
+	ContractDeployerAllowListAddress = common.HexToAddress("0x0200000000000000000000000000000000000000")
+	NativeMinterAddress              = common.HexToAddress("0x0200000000000000000000000000000000000001")
+	TxAllowListAddress               = common.HexToAddress("0x0200000000000000000000000000000000000002")
+	FeeManagerAddress                = common.HexToAddress("0x0200000000000000000000000000000000000003")
+	RewardManagerAddress             = common.HexToAddress("0x0200000000000000000000000000000000000004")
+	WarpAddress                      = common.HexToAddress("0x0200000000000000000000000000000000000005")
+)
+
+// DefaultQuorumNumerator is the share of stake, out of 100, that must sign
+// a warp message
+const DefaultQuorumNumerator = 67
+
+// precompileConfigKeys are the chain config keys of the precompiles
+var precompileConfigKeys = map[common.Address]string{
+	ContractDeployerAllowListAddress: "contractDeployerAllowListConfig",
+	NativeMinterAddress:              "contractNativeMinterConfig",
+	TxAllowListAddress:               "txAllowListConfig",
+	FeeManagerAddress:                "feeManagerConfig",
+	RewardManagerAddress:             "rewardManagerConfig",
+	WarpAddress:                      "warpConfig",
+}
+
+// PrecompileConfigKey returns the chain config key of a precompile
+func PrecompileConfigKey(address common.Address) (string, bool) {
+	key, ok := precompileConfigKeys[address]
+	return key, ok
+}
+
+// AllowListConfig configures a precompile guarded by an allow list: the
+// contract deployer and transaction allow lists, the native minter, the
+// fee manager and the reward manager
+type AllowListConfig struct {
+	BlockTimestamp   *uint64          `json:"blockTimestamp"`
+	AdminAddresses   []common.Address `json:"adminAddresses,omitempty"`
+	ManagerAddresses []common.Address `json:"managerAddresses,omitempty"`
+	EnabledAddresses []common.Address `json:"enabledAddresses,omitempty"`
+	// Disable deactivates the precompile at the timestamp of an upgrade
+	Disable bool `json:"disable,omitempty"`
+}
+
+// NativeMinterConfig configures the native minter precompile
+type NativeMinterConfig struct {
+	AllowListConfig
+	InitialMint map[common.Address]*big.Int `json:"initialMint,omitempty"`
+}
+
+// FeeManagerConfig configures the fee manager precompile. In an upgrade,
+// InitialFeeConfig replaces the fee config of the chain.
+type FeeManagerConfig struct {
+	AllowListConfig
+	InitialFeeConfig *commontype.FeeConfig `json:"initialFeeConfig,omitempty"`
+}
+
+// WarpConfig configures the warp messaging precompile
+type WarpConfig struct {
+	BlockTimestamp               *uint64 `json:"blockTimestamp"`
+	QuorumNumerator              uint64  `json:"quorumNumerator,omitempty"`
+	RequirePrimaryNetworkSigners bool    `json:"requirePrimaryNetworkSigners,omitempty"`
+	Disable                      bool    `json:"disable,omitempty"`
+}
+
+// NewAllowListConfig returns an allow list activated at the timestamp and
+// administered by the admins
+func NewAllowListConfig(timestamp uint64, admins ...common.Address) *AllowListConfig {
+	return &AllowListConfig{
+		BlockTimestamp: &timestamp,
+		AdminAddresses: admins,
+	}
+}
+
+// NewWarpConfig returns a warp config activated at the timestamp with the
+// default quorum
+func NewWarpConfig(timestamp uint64) *WarpConfig {
+	return &WarpConfig{
+		BlockTimestamp:  &timestamp,
+		QuorumNumerator: DefaultQuorumNumerator,
+	}
+}
@@ -5,6 +5,7 @@ package config
 
 import (
 	"math/big"
+	"time"
 )
 
 // Config represents the SDK configuration
@@ -12,6 +13,9 @@ type Config struct {
 	LogLevel string
 	DataDir  string
 	Network  *NetworkConfig
+	// KeyPath is the secp256k1 key paying for and owning the L1s deployed
+	// without a wallet of their own. Empty leaves them without a wallet.
+	KeyPath string
 }
 
 // NetworkConfig represents network configuration
@@ -28,6 +32,12 @@ type NetworkConfig struct {
 	DBType            string
 	GenesisFile       string
 	StakeAmount       uint64
+	// RegistryDir is where created networks are persisted. Empty disables persistence.
+	RegistryDir string
+	// SnapshotsDir is where network snapshots and their metadata are stored
+	SnapshotsDir string
+	// HealthCheckInterval is how often watched networks are polled. Zero uses the default.
+	HealthCheckInterval time.Duration
 }
 
 // ChainID returns a big.Int representation of the NetworkID
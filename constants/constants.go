@@ -75,6 +75,7 @@ const (
 	StakerCertFileName         = "staker.crt"
 	StakerKeyFileName          = "staker.key"
 	BLSKeyFileName             = "bls.key"
+	SignerKeyFileName          = "signer.key"
 	ValidatorUptimeDeductible  = 5 * time.Minute
 
 	// SSH constants
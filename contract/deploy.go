@@ -12,6 +12,11 @@ import (
 //go:embed contracts/bin/Token.bin
 var tokenBin []byte
 
+// TokenBin returns the bytecode of the ERC20 token deployed by DeployERC20
+func TokenBin() []byte {
+	return tokenBin
+}
+
 func DeployERC20(
 	rpcURL string,
 	privateKey string,
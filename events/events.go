@@ -0,0 +1,158 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package events provides the bus the SDK publishes network and blockchain
+// status transitions on.
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is the number of events buffered for each subscriber
+const DefaultBufferSize = 64
+
+// Kind is the kind of resource an event is about
+type Kind string
+
+const (
+	KindNetwork    Kind = "network"
+	KindBlockchain Kind = "blockchain"
+)
+
+// Event is a status transition of a network or blockchain
+type Event struct {
+	Kind Kind
+	// ID is the ID of the network or blockchain
+	ID   string
+	Name string
+	// Previous is the status before the transition, empty for new resources
+	Previous string
+	Status   string
+	Time     time.Time
+	// Missed is the number of older events dropped for the subscriber to
+	// make room for this one
+	Missed uint64
+}
+
+// Filter selects the events a subscriber receives. Empty fields match
+// every event.
+type Filter struct {
+	Kinds    []Kind
+	IDs      []string
+	Statuses []string
+}
+
+// Match reports whether the event passes the filter
+func (f Filter) Match(e Event) bool {
+	return (len(f.Kinds) == 0 || slices.Contains(f.Kinds, e.Kind)) &&
+		(len(f.IDs) == 0 || slices.Contains(f.IDs, e.ID)) &&
+		(len(f.Statuses) == 0 || slices.Contains(f.Statuses, e.Status))
+}
+
+// Bus fans events out to subscribers. Publishing never blocks: when a
+// subscriber falls behind, its oldest buffered events are dropped.
+// A nil Bus discards every event. It is safe for concurrent use.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[<-chan Event]*subscriber
+	closed      bool
+}
+
+type subscriber struct {
+	filter Filter
+
+	mu sync.Mutex
+	ch chan Event
+}
+
+// NewBus creates an event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[<-chan Event]*subscriber),
+	}
+}
+
+// Subscribe returns a channel receiving the events matching the filter.
+// The channel is closed by Unsubscribe or when the bus is closed.
+func (b *Bus) Subscribe(filter Filter) <-chan Event {
+	s := &subscriber{
+		filter: filter,
+		ch:     make(chan Event, DefaultBufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(s.ch)
+		return s.ch
+	}
+	b.subscribers[s.ch] = s
+	return s.ch
+}
+
+// Unsubscribe stops delivering events to the channel and closes it
+func (b *Bus) Unsubscribe(ch <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(s.ch)
+	}
+}
+
+// Publish delivers the event to every matching subscriber
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, s := range b.subscribers {
+		if s.filter.Match(e) {
+			s.send(e)
+		}
+	}
+}
+
+// Close closes every subscriber channel. Later events are discarded.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for ch, s := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(s.ch)
+	}
+}
+
+// send delivers the event without blocking, dropping the oldest buffered
+// events while the buffer is full
+func (s *subscriber) send(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		select {
+		case s.ch <- e:
+			return
+		default:
+		}
+		select {
+		case <-s.ch:
+			e.Missed++
+		default:
+		}
+	}
+}
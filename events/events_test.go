@@ -0,0 +1,113 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	event := Event{Kind: KindNetwork, ID: "net1", Status: "running"}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		match  bool
+	}{
+		{"empty", Filter{}, true},
+		{"kind", Filter{Kinds: []Kind{KindNetwork}}, true},
+		{"other kind", Filter{Kinds: []Kind{KindBlockchain}}, false},
+		{"id", Filter{IDs: []string{"net2", "net1"}}, true},
+		{"other id", Filter{IDs: []string{"net2"}}, false},
+		{"status", Filter{Kinds: []Kind{KindNetwork}, Statuses: []string{"running"}}, true},
+		{"other status", Filter{Kinds: []Kind{KindNetwork}, Statuses: []string{"stopped"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.filter.Match(event))
+		})
+	}
+}
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	all := bus.Subscribe(Filter{})
+	blockchains := bus.Subscribe(Filter{Kinds: []Kind{KindBlockchain}})
+
+	bus.Publish(Event{Kind: KindNetwork, ID: "net1", Status: "running"})
+	bus.Publish(Event{Kind: KindBlockchain, ID: "bc1", Status: "deployed"})
+
+	event := <-all
+	assert.Equal(t, "net1", event.ID)
+	assert.False(t, event.Time.IsZero())
+	assert.Equal(t, "bc1", (<-all).ID)
+	assert.Equal(t, "bc1", (<-blockchains).ID)
+
+	bus.Unsubscribe(blockchains)
+	_, ok := <-blockchains
+	assert.False(t, ok)
+	bus.Unsubscribe(blockchains)
+
+	bus.Close()
+	_, ok = <-all
+	assert.False(t, ok)
+	bus.Publish(Event{Kind: KindNetwork, ID: "net1"})
+
+	_, ok = <-bus.Subscribe(Filter{})
+	assert.False(t, ok)
+
+	// a nil bus discards events
+	var nilBus *Bus
+	nilBus.Publish(Event{Kind: KindNetwork})
+}
+
+func TestBus_SlowSubscriber(t *testing.T) {
+	bus := NewBus()
+	slow := bus.Subscribe(Filter{})
+
+	// publishing never blocks on a subscriber that does not read
+	for i := 0; i < DefaultBufferSize+10; i++ {
+		bus.Publish(Event{Kind: KindNetwork, ID: "net1", Status: string(rune('a' + i%26))})
+	}
+	require.Len(t, slow, DefaultBufferSize)
+
+	// the oldest events were dropped, the latest is kept
+	var last Event
+	var missed uint64
+	for i := 0; i < DefaultBufferSize; i++ {
+		last = <-slow
+		missed += last.Missed
+	}
+	assert.Equal(t, uint64(10), missed)
+	assert.Equal(t, string(rune('a'+(DefaultBufferSize+9)%26)), last.Status)
+}
+
+func TestBus_ConcurrentUse(t *testing.T) {
+	bus := NewBus()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bus.Publish(Event{Kind: KindNetwork})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			ch := bus.Subscribe(Filter{})
+			select {
+			case <-ch:
+			default:
+			}
+			bus.Unsubscribe(ch)
+		}()
+	}
+	wg.Wait()
+	bus.Close()
+}
@@ -20,9 +20,11 @@ import (
 	"github.com/luxfi/evm/plugin/evm/upgrade/legacy"
 	evmWarp "github.com/luxfi/evm/precompile/contracts/warp"
 	"github.com/luxfi/evm/predicate"
+	"github.com/luxfi/evm/rpc"
 	subnetEvmUtils "github.com/luxfi/evm/utils"
 	ethereum "github.com/luxfi/geth"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/common/hexutil"
 	"github.com/luxfi/geth/core/types"
 	"github.com/luxfi/geth/params"
 	"github.com/luxfi/node/vms/platformvm/warp"
@@ -185,13 +187,11 @@ func (client Client) GetContractBytecode(
 	contractAddressStr string,
 ) ([]byte, error) {
 	contractAddress := HexToAddress(contractAddressStr)
-	code, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	code, err := retryRPC(
+		"eth_getCode",
 		func(ctx context.Context) ([]byte, error) {
 			return client.EthClient.CodeAt(ctx, toCommon(contractAddress), nil)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf(
@@ -222,13 +222,11 @@ func (client Client) GetAddressBalance(
 	addressStr string,
 ) (*big.Int, error) {
 	address := HexToAddress(addressStr)
-	balance, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	balance, err := retryRPC(
+		"eth_getBalance",
 		func(ctx context.Context) (*big.Int, error) {
 			return client.EthClient.BalanceAt(ctx, toCommon(address), nil)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure obtaining balance for %s on %s: %w", addressStr, client.URL, err)
@@ -242,13 +240,11 @@ func (client Client) NonceAt(
 	addressStr string,
 ) (uint64, error) {
 	address := HexToAddress(addressStr)
-	nonce, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	nonce, err := retryRPC(
+		"eth_getTransactionCount",
 		func(ctx context.Context) (uint64, error) {
 			return client.EthClient.NonceAt(ctx, toCommon(address), nil)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure obtaining nonce for %s on %s: %w", addressStr, client.URL, err)
@@ -259,13 +255,11 @@ func (client Client) NonceAt(
 // returns the suggested gas tip
 // supports [repeatsOnFailure] failures
 func (client Client) SuggestGasTipCap() (*big.Int, error) {
-	gasTipCap, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	gasTipCap, err := retryRPC(
+		"eth_maxPriorityFeePerGas",
 		func(ctx context.Context) (*big.Int, error) {
 			return client.EthClient.SuggestGasTipCap(ctx)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure obtaining gas tip cap on %s: %w", client.URL, err)
@@ -276,13 +270,11 @@ func (client Client) SuggestGasTipCap() (*big.Int, error) {
 // returns the estimated base fee
 // supports [repeatsOnFailure] failures
 func (client Client) EstimateBaseFee() (*big.Int, error) {
-	baseFee, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	baseFee, err := retryRPC(
+		"eth_baseFee",
 		func(ctx context.Context) (*big.Int, error) {
 			return client.EthClient.EstimateBaseFee(ctx)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure estimating base fee on %s: %w", client.URL, err)
@@ -317,13 +309,11 @@ func (client Client) CalculateTxParams(
 func (client Client) EstimateGasLimit(
 	msg ethereum.CallMsg,
 ) (uint64, error) {
-	gasLimit, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	gasLimit, err := retryRPC(
+		"eth_estimateGas",
 		func(ctx context.Context) (uint64, error) {
 			return client.EthClient.EstimateGas(ctx, msg)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure estimating gas limit on %s: %w", client.URL, err)
@@ -334,13 +324,11 @@ func (client Client) EstimateGasLimit(
 // returns the chain ID
 // supports [repeatsOnFailure] failures
 func (client Client) GetChainID() (*big.Int, error) {
-	chainID, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	chainID, err := retryRPC(
+		"eth_chainId",
 		func(ctx context.Context) (*big.Int, error) {
 			return client.EthClient.ChainID(ctx)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure getting chain id from %s: %w", client.URL, err)
@@ -351,13 +339,11 @@ func (client Client) GetChainID() (*big.Int, error) {
 // returns the chain conf
 // supports [repeatsOnFailure] failures
 func (client Client) ChainConfig() (*evmParams.ChainConfigWithUpgradesJSON, error) {
-	conf, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	conf, err := retryRPC(
+		"eth_getChainConfig",
 		func(ctx context.Context) (*evmParams.ChainConfigWithUpgradesJSON, error) {
 			return client.EthClient.ChainConfig(ctx)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure getting chain config from %s: %w", client.URL, err)
@@ -370,13 +356,11 @@ func (client Client) ChainConfig() (*evmParams.ChainConfigWithUpgradesJSON, erro
 func (client Client) SendTransaction(
 	tx *types.Transaction,
 ) error {
-	_, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	_, err := retryRPC(
+		"eth_sendRawTransaction",
 		func(ctx context.Context) (any, error) {
 			return nil, client.EthClient.SendTransaction(ctx, tx)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure sending transaction %#v to %s: %w", tx, client.URL, err)
@@ -596,13 +580,11 @@ func (client Client) TransactWithWarpMessage(
 // gets block [n]
 // supports [repeatsOnFailure] failures
 func (client Client) BlockByNumber(n *big.Int) (*types.Block, error) {
-	block, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	block, err := retryRPC(
+		"eth_getBlockByNumber",
 		func(ctx context.Context) (*types.Block, error) {
 			return client.EthClient.BlockByNumber(ctx, n)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure retrieving block %d on %s: %w", n, client.URL, err)
@@ -613,13 +595,11 @@ func (client Client) BlockByNumber(n *big.Int) (*types.Block, error) {
 // get logs as given by [query]
 // supports [repeatsOnFailure] failures
 func (client Client) FilterLogs(query ethereum.FilterQuery) ([]types.Log, error) {
-	logs, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	logs, err := retryRPC(
+		"eth_getLogs",
 		func(ctx context.Context) ([]types.Log, error) {
 			return client.EthClient.FilterLogs(ctx, query)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure retrieving logs on %s: %w", client.URL, err)
@@ -630,13 +610,11 @@ func (client Client) FilterLogs(query ethereum.FilterQuery) ([]types.Log, error)
 // get tx receipt for [hash]
 // supports [repeatsOnFailure] failures
 func (client Client) TransactionReceipt(hash common.Hash) (*types.Receipt, error) {
-	receipt, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	receipt, err := retryRPC(
+		"eth_getTransactionReceipt",
 		func(ctx context.Context) (*types.Receipt, error) {
 			return client.EthClient.TransactionReceipt(ctx, hash)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure retrieving receipt for %s on %s: %w", hash, client.URL, err)
@@ -647,13 +625,11 @@ func (client Client) TransactionReceipt(hash common.Hash) (*types.Receipt, error
 // gets current height
 // supports [repeatsOnFailure] failures
 func (client Client) BlockNumber() (uint64, error) {
-	blockNumber, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	blockNumber, err := retryRPC(
+		"eth_blockNumber",
 		func(ctx context.Context) (uint64, error) {
 			return client.EthClient.BlockNumber(ctx)
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure retrieving height (block number) on %s: %w", client.URL, err)
@@ -661,6 +637,66 @@ func (client Client) BlockNumber() (uint64, error) {
 	return blockNumber, err
 }
 
+// gets the header of block [n]
+// supports [repeatsOnFailure] failures
+func (client Client) HeaderByNumber(n *big.Int) (*types.Header, error) {
+	header, err := retryRPC(
+		"eth_getBlockByNumber",
+		func(ctx context.Context) (*types.Header, error) {
+			return client.EthClient.HeaderByNumber(ctx, n)
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("failure retrieving header %d on %s: %w", n, client.URL, err)
+	}
+	return header, err
+}
+
+// gets the receipts of the txs of block [n]
+// supports [repeatsOnFailure] failures
+func (client Client) BlockReceipts(n uint64) ([]*types.Receipt, error) {
+	receipts, err := retryRPC(
+		"eth_getBlockReceipts",
+		func(ctx context.Context) ([]*types.Receipt, error) {
+			return client.EthClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(n)))
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("failure retrieving receipts of block %d on %s: %w", n, client.URL, err)
+	}
+	return receipts, err
+}
+
+// gets the number of peers of the node serving the rpc url
+// supports [repeatsOnFailure] failures
+func (client Client) PeerCount() (uint64, error) {
+	peers, err := retryRPC(
+		"net_peerCount",
+		func(ctx context.Context) (uint64, error) {
+			var peers hexutil.Uint64
+			err := client.EthClient.Client().CallContext(ctx, &peers, "net_peerCount")
+			return uint64(peers), err
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("failure retrieving peer count on %s: %w", client.URL, err)
+	}
+	return peers, err
+}
+
+// subscribes to the new heads of the chain. only available on websocket
+// connections, fails with rpc.ErrNotificationsUnsupported otherwise
+func (client Client) SubscribeNewHead(
+	ctx context.Context,
+	ch chan<- *types.Header,
+) (ethereum.Subscription, error) {
+	sub, err := client.EthClient.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		err = fmt.Errorf("failure subscribing to new heads on %s: %w", client.URL, err)
+	}
+	return sub, err
+}
+
 // waits until current height is bigger than the given previous height at [prevBlockNumber]
 // supports [repeatsOnFailure] failures on each step
 func (client Client) WaitForNewBlock(
@@ -13,6 +13,7 @@ import (
 
 	"github.com/luxfi/crypto"
 	subnetethclient "github.com/luxfi/evm/ethclient"
+	"github.com/luxfi/evm/rpc"
 	"github.com/luxfi/geth"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/types"
@@ -1236,6 +1237,139 @@ func TestBlockNumber(t *testing.T) {
 	}
 }
 
+func TestHeaderByNumber(t *testing.T) {
+	originalSleepBetweenRepeats := sleepBetweenRepeats
+	sleepBetweenRepeats = 1 * time.Millisecond
+	defer func() {
+		sleepBetweenRepeats = originalSleepBetweenRepeats
+	}()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := mockethclient.NewMockClient(ctrl)
+	client := Client{
+		EthClient: mockClient,
+		URL:       "http://localhost:8545",
+	}
+	header := &types.Header{Number: big.NewInt(7), Time: 1700000000}
+	tests := []struct {
+		name        string
+		setupMock   func()
+		expected    *types.Header
+		expectError bool
+	}{
+		{
+			name: "successful header retrieval",
+			setupMock: func() {
+				mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(7)).
+					Return(header, nil)
+			},
+			expected:    header,
+			expectError: false,
+		},
+		{
+			name: "error getting header",
+			setupMock: func() {
+				for i := 0; i < repeatsOnFailure; i++ {
+					mockClient.EXPECT().HeaderByNumber(gomock.Any(), big.NewInt(7)).
+						Return(nil, errors.New("failed to get header"))
+				}
+			},
+			expected:    nil,
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			result, err := client.HeaderByNumber(big.NewInt(7))
+			if tt.expectError {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "retrieving header")
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBlockReceipts(t *testing.T) {
+	originalSleepBetweenRepeats := sleepBetweenRepeats
+	sleepBetweenRepeats = 1 * time.Millisecond
+	defer func() {
+		sleepBetweenRepeats = originalSleepBetweenRepeats
+	}()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := mockethclient.NewMockClient(ctrl)
+	client := Client{
+		EthClient: mockClient,
+		URL:       "http://localhost:8545",
+	}
+	block := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(7))
+	receipts := []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful},
+		{Status: types.ReceiptStatusFailed},
+	}
+	tests := []struct {
+		name        string
+		setupMock   func()
+		expected    []*types.Receipt
+		expectError bool
+	}{
+		{
+			name: "successful receipts retrieval",
+			setupMock: func() {
+				mockClient.EXPECT().BlockReceipts(gomock.Any(), block).
+					Return(receipts, nil)
+			},
+			expected:    receipts,
+			expectError: false,
+		},
+		{
+			name: "error getting receipts",
+			setupMock: func() {
+				for i := 0; i < repeatsOnFailure; i++ {
+					mockClient.EXPECT().BlockReceipts(gomock.Any(), block).
+						Return(nil, errors.New("failed to get receipts"))
+				}
+			},
+			expected:    nil,
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			result, err := client.BlockReceipts(7)
+			if tt.expectError {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "retrieving receipts of block 7")
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSubscribeNewHead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := mockethclient.NewMockClient(ctrl)
+	client := Client{
+		EthClient: mockClient,
+		URL:       "http://localhost:8545",
+	}
+	heads := make(chan *types.Header)
+
+	mockClient.EXPECT().SubscribeNewHead(gomock.Any(), gomock.Any()).
+		Return(nil, rpc.ErrNotificationsUnsupported)
+	_, err := client.SubscribeNewHead(context.Background(), heads)
+	require.ErrorIs(t, err, rpc.ErrNotificationsUnsupported)
+	require.Contains(t, err.Error(), "subscribing to new heads on http://localhost:8545")
+}
+
 func TestGetPrivateKeyBalance(t *testing.T) {
 	originalSleepBetweenRepeats := sleepBetweenRepeats
 	sleepBetweenRepeats = 1 * time.Millisecond
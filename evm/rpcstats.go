@@ -0,0 +1,107 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/luxfi/sdk/utils"
+)
+
+// RPCLatencyBuckets are the upper bounds of the latency histogram of rpc calls
+var RPCLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// RPCCallStats are the calls evm clients made to an rpc method. each
+// attempt of a call retried on failure is counted
+type RPCCallStats struct {
+	Method   string
+	Calls    uint64
+	Failures uint64
+	// Latency is the total latency of the calls
+	Latency time.Duration
+	// Buckets counts the calls that took at most the matching bound of
+	// RPCLatencyBuckets. counts are cumulative
+	Buckets []uint64
+}
+
+var rpcStats = struct {
+	mu      sync.Mutex
+	methods map[string]*RPCCallStats
+}{
+	methods: make(map[string]*RPCCallStats),
+}
+
+// RPCStats returns the stats of the rpc calls made by evm clients, sorted by method
+func RPCStats() []RPCCallStats {
+	rpcStats.mu.Lock()
+	defer rpcStats.mu.Unlock()
+	stats := make([]RPCCallStats, 0, len(rpcStats.methods))
+	for _, s := range rpcStats.methods {
+		c := *s
+		c.Buckets = slices.Clone(s.Buckets)
+		stats = append(stats, c)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+// records a call to rpc [method] that took [elapsed]
+func observeRPC(method string, elapsed time.Duration, err error) {
+	rpcStats.mu.Lock()
+	defer rpcStats.mu.Unlock()
+	s, ok := rpcStats.methods[method]
+	if !ok {
+		s = &RPCCallStats{
+			Method:  method,
+			Buckets: make([]uint64, len(RPCLatencyBuckets)),
+		}
+		rpcStats.methods[method] = s
+	}
+	s.Calls++
+	if err != nil {
+		s.Failures++
+	}
+	s.Latency += elapsed
+	for i, bound := range RPCLatencyBuckets {
+		if elapsed <= bound {
+			s.Buckets[i]++
+		}
+	}
+}
+
+// calls rpc [method] using [fn], recording the stats of each attempt
+// supports [repeatsOnFailure] failures
+func retryRPC[T any](
+	method string,
+	fn func(context.Context) (T, error),
+) (T, error) {
+	return utils.RetryWithContextGen(
+		utils.GetAPILargeContext,
+		func(ctx context.Context) (T, error) {
+			start := time.Now()
+			result, err := fn(ctx)
+			observeRPC(method, time.Since(start), err)
+			return result, err
+		},
+		repeatsOnFailure,
+		sleepBetweenRepeats,
+	)
+}
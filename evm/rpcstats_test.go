@@ -0,0 +1,70 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func rpcStatsOf(method string) (RPCCallStats, bool) {
+	for _, stats := range RPCStats() {
+		if stats.Method == method {
+			return stats, true
+		}
+	}
+	return RPCCallStats{}, false
+}
+
+func TestRetryRPCStats(t *testing.T) {
+	originalSleepBetweenRepeats := sleepBetweenRepeats
+	sleepBetweenRepeats = 1 * time.Millisecond
+	defer func() {
+		sleepBetweenRepeats = originalSleepBetweenRepeats
+	}()
+
+	attempts := 0
+	result, err := retryRPC(
+		"test_flaky",
+		func(context.Context) (int, error) {
+			attempts++
+			if attempts < repeatsOnFailure {
+				return 0, errors.New("connection reset")
+			}
+			return 7, nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 7, result)
+
+	stats, ok := rpcStatsOf("test_flaky")
+	require.True(t, ok)
+	require.Equal(t, uint64(repeatsOnFailure), stats.Calls)
+	require.Equal(t, uint64(repeatsOnFailure-1), stats.Failures)
+	require.Len(t, stats.Buckets, len(RPCLatencyBuckets))
+	// the calls are immediate, so they fall in every bucket
+	for _, count := range stats.Buckets {
+		require.Equal(t, uint64(repeatsOnFailure), count)
+	}
+
+	_, err = retryRPC(
+		"test_down",
+		func(context.Context) (any, error) {
+			return nil, errors.New("connection refused")
+		},
+	)
+	require.Error(t, err)
+	stats, ok = rpcStatsOf("test_down")
+	require.True(t, ok)
+	require.Equal(t, uint64(repeatsOnFailure), stats.Calls)
+	require.Equal(t, uint64(repeatsOnFailure), stats.Failures)
+
+	// returned stats are copies
+	stats.Buckets[0] = 0
+	stats, _ = rpcStatsOf("test_down")
+	require.Equal(t, uint64(repeatsOnFailure), stats.Buckets[0])
+}
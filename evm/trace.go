@@ -77,8 +77,8 @@ func (client RawClient) Close() {
 func (client RawClient) DebugTraceTransaction(
 	txID string,
 ) (map[string]interface{}, error) {
-	trace, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	trace, err := retryRPC(
+		"debug_traceTransaction",
 		func(ctx context.Context) (map[string]interface{}, error) {
 			var trace map[string]interface{}
 			err := client.CallContext(
@@ -90,8 +90,6 @@ func (client RawClient) DebugTraceTransaction(
 			)
 			return trace, err
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure tracing tx %s on %s: %w", txID, client.URL, err)
@@ -104,8 +102,8 @@ func (client RawClient) DebugTraceTransaction(
 func (client RawClient) DebugTraceCall(
 	data map[string]string,
 ) (map[string]interface{}, error) {
-	trace, err := utils.RetryWithContextGen(
-		utils.GetAPILargeContext,
+	trace, err := retryRPC(
+		"debug_traceCall",
 		func(ctx context.Context) (map[string]interface{}, error) {
 			var trace map[string]interface{}
 			err := client.CallContext(
@@ -123,8 +121,6 @@ func (client RawClient) DebugTraceCall(
 			)
 			return trace, err
 		},
-		repeatsOnFailure,
-		sleepBetweenRepeats,
 	)
 	if err != nil {
 		err = fmt.Errorf("failure tracing call on %s: %w", client.URL, err)
@@ -64,6 +64,14 @@ func NewFeeConfigBuilder() *FeeConfigBuilder {
 	}
 }
 
+// NewFeeConfigBuilderForThroughput creates a new fee config builder starting
+// from the preset for the throughput level, see GetFeeConfigForThroughput
+func NewFeeConfigBuilderForThroughput(throughput string) *FeeConfigBuilder {
+	return &FeeConfigBuilder{
+		config: GetFeeConfigForThroughput(throughput),
+	}
+}
+
 // WithGasLimit sets the gas limit
 func (b *FeeConfigBuilder) WithGasLimit(gasLimit *big.Int) *FeeConfigBuilder {
 	b.config.GasLimit = gasLimit
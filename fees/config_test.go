@@ -111,6 +111,18 @@ func TestFeeConfigBuilder(t *testing.T) {
 		require.Equal(t, DefaultFeeConfig.MinBaseFee, config.MinBaseFee)
 		require.Equal(t, DefaultFeeConfig.BaseFeeChangeDenominator, config.BaseFeeChangeDenominator)
 	})
+
+	t.Run("preset builder", func(t *testing.T) {
+		customGasLimit := big.NewInt(20_000_000)
+
+		config := NewFeeConfigBuilderForThroughput("high").
+			WithGasLimit(customGasLimit).
+			Build()
+
+		require.Equal(t, customGasLimit, config.GasLimit)
+		require.Equal(t, HighThroughputConfig.TargetGas, config.TargetGas)
+		require.Equal(t, big.NewInt(15_000_000), HighThroughputConfig.GasLimit)
+	})
 }
 
 func TestPresetConfigurations(t *testing.T) {
@@ -6,6 +6,8 @@ package types
 import (
 	"crypto/rand"
 	"encoding/hex"
+
+	"github.com/luxfi/ids"
 )
 
 // ID represents a 32-byte identifier
@@ -77,3 +79,8 @@ func NodeIDFromString(s string) (NodeID, error) {
 	copy(id[:], decoded)
 	return id, nil
 }
+
+// CB58 returns the CB58 encoding of an ID, the format used by nodes
+func (id ID) CB58() string {
+	return ids.ID(id).String()
+}
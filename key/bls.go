@@ -0,0 +1,200 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/ids"
+	"github.com/luxfi/node/staking"
+	"github.com/luxfi/sdk/constants"
+	"github.com/luxfi/sdk/crypto"
+)
+
+var (
+	// ErrNotBLSKey is returned when a BLS operation is given a key of another type
+	ErrNotBLSKey = errors.New("not a BLS key")
+	// ErrSignerKeyExists is returned when a staking directory already has
+	// the signer key of another BLS key
+	ErrSignerKeyExists = errors.New("staking directory has another signer key")
+	// ErrIncompleteStakingCert is returned when a staking directory has only
+	// one of the staking certificate and key
+	ErrIncompleteStakingCert = errors.New("staking directory has only one of the staking certificate and key")
+)
+
+// NodeStakingBundle is the staking directory of a node, with the TLS
+// certificate and key identifying the node and the BLS key it signs with
+type NodeStakingBundle struct {
+	StakerCertPath string
+	StakerKeyPath  string
+	SignerKeyPath  string
+	// BLSPublicKey is the compressed public key of the signer key
+	BLSPublicKey []byte
+	// BLSProofOfPossession is the proof of possession of the signer key
+	BLSProofOfPossession []byte
+}
+
+// GenerateBLS generates a new BLS key
+func (m *Manager) GenerateBLS() (*Key, *bls.SecretKey, error) {
+	blsKey, err := bls.NewSecretKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate BLS key: %w", err)
+	}
+	key := newBLSKey(blsKey)
+	m.keys[key.ID] = key
+	return key, blsKey, nil
+}
+
+// ImportBLS imports an existing BLS secret key, in the format of the
+// signer.key file of a node
+func (m *Manager) ImportBLS(secretKey []byte) (*Key, error) {
+	blsKey, err := bls.SecretKeyFromBytes(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BLS secret key: %w", err)
+	}
+	key := newBLSKey(blsKey)
+	m.keys[key.ID] = key
+	return key, nil
+}
+
+// newBLSKey creates the key of a BLS secret key. Its ID and address are
+// taken from its uncompressed public key.
+func newBLSKey(blsKey *bls.SecretKey) *Key {
+	pubKeyBytes := bls.PublicKeyToUncompressedBytes(bls.PublicFromSecretKey(blsKey))
+
+	keyID := ids.ID{}
+	copy(keyID[:], pubKeyBytes)
+
+	return &Key{
+		ID:           keyID,
+		Type:         "bls",
+		BLSSecretKey: blsKey,
+		Address:      generateAddress(crypto.PublicKey(pubKeyBytes[:crypto.PublicKeyLen])),
+		Metadata: map[string]string{
+			"blsPublicKey": hex.EncodeToString(pubKeyBytes),
+		},
+	}
+}
+
+// BLSProofOfPossession returns the compressed public key of a BLS key and
+// its proof of possession, as registered with an L1 validator
+func (m *Manager) BLSProofOfPossession(keyID ids.ID) ([]byte, []byte, error) {
+	blsKey, err := m.blsSecretKey(keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubKeyBytes := bls.PublicKeyToCompressedBytes(bls.PublicFromSecretKey(blsKey))
+	pop := bls.SignProofOfPossession(blsKey, pubKeyBytes)
+	return pubKeyBytes, bls.SignatureToBytes(pop), nil
+}
+
+// ExportNodeStakingBundle writes a BLS key as the signer.key of the node
+// staking directory [stakingDir]. The staking certificate and key of the
+// node are generated unless the directory already has them. An existing
+// signer.key of another BLS key is never overwritten.
+func (m *Manager) ExportNodeStakingBundle(keyID ids.ID, stakingDir string) (*NodeStakingBundle, error) {
+	blsKey, err := m.blsSecretKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(stakingDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create staking directory: %w", err)
+	}
+
+	bundle := &NodeStakingBundle{
+		StakerCertPath: filepath.Join(stakingDir, constants.StakerCertFileName),
+		StakerKeyPath:  filepath.Join(stakingDir, constants.StakerKeyFileName),
+		SignerKeyPath:  filepath.Join(stakingDir, constants.SignerKeyFileName),
+	}
+	signerKey := bls.SecretKeyToBytes(blsKey)
+	existing, err := os.ReadFile(bundle.SignerKeyPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read signer key: %w", err)
+	case !bytes.Equal(existing, signerKey):
+		return nil, fmt.Errorf("%w: %s", ErrSignerKeyExists, bundle.SignerKeyPath)
+	}
+	if err := writeStakingCert(bundle.StakerCertPath, bundle.StakerKeyPath); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(bundle.SignerKeyPath, signerKey, constants.WriteReadUserOnlyPerms); err != nil {
+		return nil, fmt.Errorf("failed to write signer key: %w", err)
+	}
+
+	bundle.BLSPublicKey, bundle.BLSProofOfPossession, err = m.BLSProofOfPossession(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// blsSecretKey returns the secret key of a BLS key
+func (m *Manager) blsSecretKey(keyID ids.ID) (*bls.SecretKey, error) {
+	key, err := m.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Type != "bls" {
+		return nil, fmt.Errorf("%w: %s", ErrNotBLSKey, key.Type)
+	}
+	if key.BLSSecretKey == nil {
+		if _, encrypted := m.keystores[keyID]; encrypted && !m.unlocked {
+			return nil, ErrLocked
+		}
+		return nil, errors.New("key does not have a BLS secret key")
+	}
+	return key.BLSSecretKey, nil
+}
+
+// writeStakingCert generates the staking certificate and key of a node,
+// unless both already exist. It refuses to replace a lone certificate or
+// key, which would change the node ID.
+func writeStakingCert(certPath, keyPath string) error {
+	certExists, err := fileExists(certPath)
+	if err != nil {
+		return err
+	}
+	keyExists, err := fileExists(keyPath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case certExists && keyExists:
+		return nil
+	case certExists || keyExists:
+		return ErrIncompleteStakingCert
+	}
+
+	certBytes, keyBytes, err := staking.NewCertAndKeyBytes()
+	if err != nil {
+		return fmt.Errorf("failed to generate staking certificate: %w", err)
+	}
+	if err := os.WriteFile(certPath, certBytes, constants.WriteReadUserOnlyPerms); err != nil {
+		return fmt.Errorf("failed to write staking certificate: %w", err)
+	}
+	if err := os.WriteFile(keyPath, keyBytes, constants.WriteReadUserOnlyPerms); err != nil {
+		return fmt.Errorf("failed to write staking key: %w", err)
+	}
+	return nil
+}
+
+// fileExists reports whether a file exists
+func fileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+}
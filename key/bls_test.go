@@ -0,0 +1,127 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/sdk/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_BLSPersistence(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := newUnlockedManager(t, tmpDir)
+
+	key, blsKey, err := manager.GenerateBLS()
+	require.NoError(t, err)
+	require.Equal(t, blsKey, key.BLSSecretKey)
+	require.NoError(t, manager.Save(key.ID))
+	pubKey, pop, err := manager.BLSProofOfPossession(key.ID)
+	require.NoError(t, err)
+
+	reloaded, err := NewManager(tmpDir)
+	require.NoError(t, err)
+	loaded, err := reloaded.Get(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, key.Address, loaded.Address)
+	require.Equal(t, key.Metadata["blsPublicKey"], loaded.Metadata["blsPublicKey"])
+	require.Nil(t, loaded.BLSSecretKey)
+	_, _, err = reloaded.BLSProofOfPossession(key.ID)
+	require.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, reloaded.Unlock(testPassphrase))
+	require.Equal(t, bls.SecretKeyToBytes(blsKey), bls.SecretKeyToBytes(loaded.BLSSecretKey))
+	loadedPubKey, loadedPoP, err := reloaded.BLSProofOfPossession(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, loadedPubKey)
+	require.Equal(t, pop, loadedPoP)
+
+	exported, err := reloaded.ExportKey(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, exported, loaded.PrivKeyHex())
+
+	reloaded.Lock()
+	require.Nil(t, loaded.BLSSecretKey)
+}
+
+func TestManager_BLSProofOfPossession(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+
+	key, blsKey, err := manager.GenerateBLS()
+	require.NoError(t, err)
+	pubKeyBytes, popBytes, err := manager.BLSProofOfPossession(key.ID)
+	require.NoError(t, err)
+	require.Len(t, pubKeyBytes, bls.PublicKeyLen)
+	require.Len(t, popBytes, bls.SignatureLen)
+
+	pubKey := bls.PublicFromSecretKey(blsKey)
+	require.Equal(t, bls.PublicKeyToCompressedBytes(pubKey), pubKeyBytes)
+	require.Equal(t, bls.SignatureToBytes(bls.SignProofOfPossession(blsKey, pubKeyBytes)), popBytes)
+
+	// importing the secret key gives the same key
+	imported, err := manager.ImportBLS(bls.SecretKeyToBytes(blsKey))
+	require.NoError(t, err)
+	require.Equal(t, key.ID, imported.ID)
+
+	secpKey, err := manager.GenerateKey("secp256k1")
+	require.NoError(t, err)
+	_, _, err = manager.BLSProofOfPossession(secpKey.ID)
+	require.ErrorIs(t, err, ErrNotBLSKey)
+}
+
+func TestManager_ExportNodeStakingBundle(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+	key, blsKey, err := manager.GenerateBLS()
+	require.NoError(t, err)
+
+	stakingDir := filepath.Join(t.TempDir(), "staking")
+	bundle, err := manager.ExportNodeStakingBundle(key.ID, stakingDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(stakingDir, constants.SignerKeyFileName), bundle.SignerKeyPath)
+
+	signerKey, err := os.ReadFile(bundle.SignerKeyPath)
+	require.NoError(t, err)
+	require.Equal(t, bls.SecretKeyToBytes(blsKey), signerKey)
+	cert, err := os.ReadFile(bundle.StakerCertPath)
+	require.NoError(t, err)
+	require.NotEmpty(t, cert)
+	require.FileExists(t, bundle.StakerKeyPath)
+
+	pubKey, pop, err := manager.BLSProofOfPossession(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, bundle.BLSPublicKey)
+	require.Equal(t, pop, bundle.BLSProofOfPossession)
+
+	// exporting the same key again keeps the staking certificate
+	_, err = manager.ExportNodeStakingBundle(key.ID, stakingDir)
+	require.NoError(t, err)
+	reexportedCert, err := os.ReadFile(bundle.StakerCertPath)
+	require.NoError(t, err)
+	require.Equal(t, cert, reexportedCert)
+
+	// the signer key of another key is never overwritten
+	other, _, err := manager.GenerateBLS()
+	require.NoError(t, err)
+	_, err = manager.ExportNodeStakingBundle(other.ID, stakingDir)
+	require.ErrorIs(t, err, ErrSignerKeyExists)
+	signerKey, err = os.ReadFile(bundle.SignerKeyPath)
+	require.NoError(t, err)
+	require.Equal(t, bls.SecretKeyToBytes(blsKey), signerKey)
+
+	// a lone staking certificate is not replaced, which would change the node ID
+	require.NoError(t, os.Remove(bundle.StakerKeyPath))
+	require.NoError(t, os.Remove(bundle.SignerKeyPath))
+	_, err = manager.ExportNodeStakingBundle(key.ID, stakingDir)
+	require.ErrorIs(t, err, ErrIncompleteStakingCert)
+	reexportedCert, err = os.ReadFile(bundle.StakerCertPath)
+	require.NoError(t, err)
+	require.Equal(t, cert, reexportedCert)
+	require.NoFileExists(t, bundle.StakerKeyPath)
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/luxfi/sdk/crypto"
+)
+
+const (
+	// HardenedKeyStart is the index of the first hardened child key
+	HardenedKeyStart uint32 = 0x80000000
+	// LuxCoinType is the SLIP-44 coin type of Lux
+	LuxCoinType = 9000
+	// Secp256k1KeyLen is the length of a secp256k1 private key
+	Secp256k1KeyLen = 32
+
+	// seed keys of the master key derivation
+	ed25519SeedKey   = "ed25519 seed"
+	secp256k1SeedKey = "Bitcoin seed"
+)
+
+var (
+	// ErrInvalidPath is returned for malformed derivation paths
+	ErrInvalidPath = errors.New("invalid derivation path")
+	// ErrHardenedOnly is returned for ed25519 paths with non hardened indices
+	ErrHardenedOnly = errors.New("ed25519 derivation only supports hardened indices")
+	// ErrInvalidSeed is returned for seeds shorter than 128 bits or longer than 512 bits
+	ErrInvalidSeed = errors.New("seed must be 16 to 64 bytes")
+	// ErrInvalidChildKey is returned in the unlikely case a derived
+	// secp256k1 key is not a valid private key
+	ErrInvalidChildKey = errors.New("derived key is invalid")
+)
+
+// LedgerPath returns the BIP44 path of the secp256k1 key at [index], the
+// path the Lux Ledger app derives addresses at
+func LedgerPath(index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", LuxCoinType, index)
+}
+
+// Ed25519Path returns the SLIP-10 path of the ed25519 key at [index]. All
+// its indices are hardened, as ed25519 derivation requires.
+func Ed25519Path(index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0'/%d'", LuxCoinType, index)
+}
+
+// ParseDerivationPath parses a BIP32 derivation path such as
+// m/44'/9000'/0'/0/0. Hardened indices are suffixed by ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	segments := strings.Split(strings.TrimSpace(path), "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("%w %q: must start with m", ErrInvalidPath, path)
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := false
+		if trimmed, ok := strings.CutSuffix(segment, "'"); ok {
+			segment, hardened = trimmed, true
+		} else if trimmed, ok := strings.CutSuffix(strings.ToLower(segment), "h"); ok {
+			segment, hardened = trimmed, true
+		}
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("%w %q: invalid index %q", ErrInvalidPath, path, segment)
+		}
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// DeriveEd25519 derives the ed25519 key at [path] from a seed following
+// SLIP-10
+func DeriveEd25519(seed []byte, path string) (crypto.PrivateKey, error) {
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return crypto.EmptyPrivateKey, err
+	}
+	if err := validateSeed(seed); err != nil {
+		return crypto.EmptyPrivateKey, err
+	}
+
+	key, chainCode := hmacSplit([]byte(ed25519SeedKey), seed)
+	for _, index := range indices {
+		if index < HardenedKeyStart {
+			return crypto.EmptyPrivateKey, fmt.Errorf("%w: %q", ErrHardenedOnly, path)
+		}
+		key, chainCode = hmacSplit(chainCode, hardenedChildData(key, index))
+	}
+	return crypto.PrivateKey(ed25519.NewKeyFromSeed(key)), nil
+}
+
+// DeriveSecp256k1 derives the secp256k1 private key at [path] from a seed
+// following BIP32
+func DeriveSecp256k1(seed []byte, path string) ([]byte, error) {
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSeed(seed); err != nil {
+		return nil, err
+	}
+
+	keyBytes, chainCode := hmacSplit([]byte(secp256k1SeedKey), seed)
+	var key secp256k1.ModNScalar
+	if overflow := key.SetByteSlice(keyBytes); overflow || key.IsZero() {
+		return nil, ErrInvalidChildKey
+	}
+	for _, index := range indices {
+		var data []byte
+		if index >= HardenedKeyStart {
+			parent := key.Bytes()
+			data = hardenedChildData(parent[:], index)
+		} else {
+			pubKey := secp256k1.NewPrivateKey(&key).PubKey().SerializeCompressed()
+			data = binary.BigEndian.AppendUint32(pubKey, index)
+		}
+		var tweak []byte
+		tweak, chainCode = hmacSplit(chainCode, data)
+
+		var child secp256k1.ModNScalar
+		if overflow := child.SetByteSlice(tweak); overflow {
+			return nil, ErrInvalidChildKey
+		}
+		child.Add(&key)
+		if child.IsZero() {
+			return nil, ErrInvalidChildKey
+		}
+		key = child
+	}
+	keyBytes = make([]byte, Secp256k1KeyLen)
+	key.PutBytesUnchecked(keyBytes)
+	return keyBytes, nil
+}
+
+// DeriveKey derives the ed25519 key at [index] from a mnemonic, at the path
+// returned by Ed25519Path
+func DeriveKey(mnemonic []string, index uint32) (crypto.PrivateKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, "")
+	if err != nil {
+		return crypto.EmptyPrivateKey, err
+	}
+	return DeriveEd25519(seed, Ed25519Path(index))
+}
+
+// DeriveSecp256k1Key derives the secp256k1 key at [index] from a mnemonic,
+// at the path returned by LedgerPath. A Ledger initialized with the same
+// mnemonic has the same keys.
+func DeriveSecp256k1Key(mnemonic []string, index uint32) ([]byte, error) {
+	seed, err := MnemonicToSeed(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	return DeriveSecp256k1(seed, LedgerPath(index))
+}
+
+// validateSeed returns an error if the seed length is not allowed by BIP32
+func validateSeed(seed []byte) error {
+	if len(seed) < 16 || len(seed) > 64 {
+		return ErrInvalidSeed
+	}
+	return nil
+}
+
+// hmacSplit returns both halves of HMAC-SHA512(key, data)
+func hmacSplit(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+// hardenedChildData returns 0x00 || key || index, the input of a hardened
+// child key derivation
+func hardenedChildData(key []byte, index uint32) []byte {
+	data := make([]byte, 0, 1+len(key)+4)
+	data = append(data, 0x00)
+	data = append(data, key...)
+	return binary.BigEndian.AppendUint32(data, index)
+}
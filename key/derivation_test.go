@@ -0,0 +1,171 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectorSeed is the seed of test vector 1 of BIP32 and SLIP-10
+const vectorSeed = "000102030405060708090a0b0c0d0e0f"
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []uint32
+		wantErr bool
+	}{
+		{path: "m", want: []uint32{}},
+		{path: "m/44'/9000'/0'/0/7", want: []uint32{HardenedKeyStart + 44, HardenedKeyStart + 9000, HardenedKeyStart, 0, 7}},
+		{path: "m/0h/1H/2", want: []uint32{HardenedKeyStart, HardenedKeyStart + 1, 2}},
+		{path: "m/2147483647'", want: []uint32{HardenedKeyStart + 2147483647}},
+		{path: "", wantErr: true},
+		{path: "44'/9000'", wantErr: true},
+		{path: "m/", wantErr: true},
+		{path: "m/-1", wantErr: true},
+		{path: "m/a'", wantErr: true},
+		{path: "m/2147483648", wantErr: true},
+		{path: "m/0''", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			indices, err := ParseDerivationPath(tt.path)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidPath)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, indices)
+		})
+	}
+}
+
+func TestDeriveEd25519_Vectors(t *testing.T) {
+	seed, err := hex.DecodeString(vectorSeed)
+	require.NoError(t, err)
+
+	// SLIP-10 ed25519 test vector 1
+	tests := []struct {
+		path       string
+		privateKey string
+		publicKey  string
+	}{
+		{
+			path:       "m",
+			privateKey: "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			publicKey:  "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+		},
+		{
+			path:       "m/0'",
+			privateKey: "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			publicKey:  "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+		},
+		{
+			path:       "m/0'/1'",
+			privateKey: "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+			publicKey:  "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187",
+		},
+		{
+			path:       "m/0'/1'/2'",
+			privateKey: "92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9",
+			publicKey:  "ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1",
+		},
+		{
+			path:       "m/0'/1'/2'/2'",
+			privateKey: "30d1dc7e5fc04c31219ab25a27ae00b50f6fd66622f6e9c913253d6511d1e662",
+			publicKey:  "8abae2d66361c879b900d204ad2cc4984fa2aa344dd7ddc46007329ac76c429c",
+		},
+		{
+			path:       "m/0'/1'/2'/2'/1000000000'",
+			privateKey: "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+			publicKey:  "3c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			privateKey, err := DeriveEd25519(seed, tt.path)
+			require.NoError(t, err)
+			require.Equal(t, tt.privateKey, hex.EncodeToString(privateKey[:32]))
+			publicKey := privateKey.PublicKey()
+			require.Equal(t, tt.publicKey, hex.EncodeToString(publicKey[:]))
+		})
+	}
+
+	_, err = DeriveEd25519(seed, LedgerPath(0))
+	require.ErrorIs(t, err, ErrHardenedOnly)
+	_, err = DeriveEd25519(seed[:15], "m/0'")
+	require.ErrorIs(t, err, ErrInvalidSeed)
+}
+
+func TestDeriveSecp256k1_Vectors(t *testing.T) {
+	seed, err := hex.DecodeString(vectorSeed)
+	require.NoError(t, err)
+
+	// BIP32 test vector 1
+	tests := []struct {
+		path       string
+		privateKey string
+	}{
+		{path: "m", privateKey: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{path: "m/0'", privateKey: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{path: "m/0'/1", privateKey: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{path: "m/0'/1/2'", privateKey: "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{path: "m/0'/1/2'/2", privateKey: "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{path: "m/0'/1/2'/2/1000000000", privateKey: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			privateKey, err := DeriveSecp256k1(seed, tt.path)
+			require.NoError(t, err)
+			require.Len(t, privateKey, Secp256k1KeyLen)
+			require.Equal(t, tt.privateKey, hex.EncodeToString(privateKey))
+		})
+	}
+
+	_, err = DeriveSecp256k1(seed, "m/x")
+	require.ErrorIs(t, err, ErrInvalidPath)
+	_, err = DeriveSecp256k1(make([]byte, 65), "m/0")
+	require.ErrorIs(t, err, ErrInvalidSeed)
+}
+
+func TestDeriveSecp256k1Key_LedgerPath(t *testing.T) {
+	mnemonic := strings.Fields(bip39Vectors[0].mnemonic)
+	seed, err := MnemonicToSeed(mnemonic, "")
+	require.NoError(t, err)
+
+	require.Equal(t, "m/44'/9000'/0'/0/3", LedgerPath(3))
+	for index := uint32(0); index < 3; index++ {
+		privateKey, err := DeriveSecp256k1Key(mnemonic, index)
+		require.NoError(t, err)
+		want, err := DeriveSecp256k1(seed, LedgerPath(index))
+		require.NoError(t, err)
+		require.Equal(t, want, privateKey)
+	}
+
+	_, err = DeriveSecp256k1Key(mnemonic[:11], 0)
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+}
+
+func TestDeriveKey_Path(t *testing.T) {
+	mnemonic := strings.Fields(bip39Vectors[0].mnemonic)
+	seed, err := MnemonicToSeed(mnemonic, "")
+	require.NoError(t, err)
+
+	require.Equal(t, "m/44'/9000'/0'/0'/3'", Ed25519Path(3))
+	privateKey, err := DeriveKey(mnemonic, 3)
+	require.NoError(t, err)
+	want, err := DeriveEd25519(seed, Ed25519Path(3))
+	require.NoError(t, err)
+	require.Equal(t, want, privateKey)
+
+	_, err = DeriveKey([]string{"abandon"}, 0)
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+}
@@ -4,6 +4,7 @@
 package key
 
 import (
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -11,11 +12,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/luxfi/crypto/bls"
 	"github.com/luxfi/ids"
 	"github.com/luxfi/sdk/crypto"
+	"github.com/luxfi/sdk/internal/address"
 )
 
 // Key represents a cryptographic key with metadata
@@ -26,6 +28,14 @@ type Key struct {
 	PublicKey  crypto.PublicKey  `json:"publicKey"`
 	Address    ids.ShortID       `json:"address"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
+
+	// Secp256k1PrivateKey is the private key of secp256k1 keys
+	Secp256k1PrivateKey *secp256k1.PrivateKey `json:"-"`
+	// Secp256k1PublicKey is the compressed public key of secp256k1 keys
+	Secp256k1PublicKey []byte `json:"secp256k1PublicKey,omitempty"`
+
+	// BLSSecretKey is the secret key of BLS keys
+	BLSSecretKey *bls.SecretKey `json:"-"`
 }
 
 // generateAddress generates an address from a public key
@@ -84,52 +94,29 @@ func (kc *Keychain) Addresses() []ids.ShortID {
 	return addresses
 }
 
-// GenerateMnemonic generates a mnemonic phrase
-func GenerateMnemonic(bitSize int) ([]string, error) {
-	// Simple mock implementation for testing
-	// In production, use a proper BIP39 implementation
-	words := []string{
-		"abandon", "ability", "able", "about", "above", "absent",
-		"absorb", "abstract", "absurd", "abuse", "access", "accident",
-	}
-
-	if bitSize == 128 {
-		return words[:12], nil
-	} else if bitSize == 256 {
-		return append(words, words...)[:24], nil
-	}
-	return nil, fmt.Errorf("unsupported bit size: %d", bitSize)
-}
-
-// DeriveKey derives a key from a mnemonic at the given index
-func DeriveKey(mnemonic []string, index uint32) (crypto.PrivateKey, error) {
-	// Simple mock implementation for testing
-	// In production, use proper BIP32/BIP44 derivation
-
-	// Generate a deterministic key based on mnemonic and index
-	seed := fmt.Sprintf("%v-%d", mnemonic, index)
-
-	// Create a deterministic private key (for testing only!)
-	// Use a simple hash to ensure valid key
-	h := [64]byte{}
-	copy(h[:], []byte(seed))
-
-	// Ensure it's different for different indices
-	h[0] = byte(index)
-	h[1] = byte(index >> 8)
-	h[2] = byte(index >> 16)
-	h[3] = byte(index >> 24)
-
-	var privKey crypto.PrivateKey
-	copy(privKey[:], h[:crypto.PrivateKeyLen])
-
-	return privKey, nil
-}
+const (
+	keyFileExt = ".key"
+	// keystoreFileExt is the extension of the encrypted private key files
+	keystoreFileExt = ".keystore"
+	// plaintextFileExt is the extension of the legacy plaintext private key
+	// files, migrated to keystores on unlock
+	plaintextFileExt = ".priv"
+)
 
-// Manager handles key generation, storage, and retrieval
+// Manager handles key generation, storage, and retrieval. Private keys are
+// persisted in keystores encrypted with the passphrase the manager is
+// unlocked with.
 type Manager struct {
 	keyDir string
 	keys   map[ids.ID]*Key
+	// keystores are the encrypted private keys of the key directory
+	keystores map[ids.ID]*EncryptedKey
+	// plaintext are the keys loaded from plaintext files
+	plaintext map[ids.ID]struct{}
+	// passphrase encrypts the keystores while the manager is unlocked
+	passphrase []byte
+	unlocked   bool
+	params     KeystoreParams
 }
 
 // NewManager creates a new key manager
@@ -139,8 +126,11 @@ func NewManager(keyDir string) (*Manager, error) {
 	}
 
 	m := &Manager{
-		keyDir: keyDir,
-		keys:   make(map[ids.ID]*Key),
+		keyDir:    keyDir,
+		keys:      make(map[ids.ID]*Key),
+		keystores: make(map[ids.ID]*EncryptedKey),
+		plaintext: make(map[ids.ID]struct{}),
+		params:    StandardKeystoreParams,
 	}
 
 	// Load existing keys
@@ -171,48 +161,15 @@ func (m *Manager) GenerateEd25519() (*Key, error) {
 	return key, nil
 }
 
-// GenerateBLS generates a new BLS key
-func (m *Manager) GenerateBLS() (*Key, *bls.SecretKey, error) {
-	blsKey, err := bls.NewSecretKey()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate BLS key: %w", err)
-	}
-
-	// Create a wrapper key
-	pubKey := bls.PublicFromSecretKey(blsKey)
-	pubKeyBytes := bls.PublicKeyToUncompressedBytes(pubKey)
-
-	// Generate ID from public key
-	keyID := ids.ID{}
-	copy(keyID[:], pubKeyBytes)
-
-	key := &Key{
-		ID:       keyID,
-		Type:     "bls",
-		Metadata: make(map[string]string),
-	}
-
-	// Store BLS public key info in metadata
-	key.Metadata["blsPublicKey"] = hex.EncodeToString(pubKeyBytes)
-
-	m.keys[key.ID] = key
-	return key, blsKey, nil
-}
-
 // GenerateKey generates a new key of the specified type
 func (m *Manager) GenerateKey(keyType string) (*Key, error) {
 	switch keyType {
 	case "ed25519":
 		return m.GenerateEd25519()
+	case "secp256k1":
+		return m.GenerateSecp256k1()
 	case "bls":
-		key, blsKey, err := m.GenerateBLS()
-		if err != nil {
-			return nil, err
-		}
-		// Generate address from BLS public key
-		pubKey := bls.PublicFromSecretKey(blsKey)
-		pubKeyBytes := bls.PublicKeyToUncompressedBytes(pubKey)
-		key.Address = generateAddress(crypto.PublicKey(pubKeyBytes[:crypto.PublicKeyLen]))
+		key, _, err := m.GenerateBLS()
 		return key, err
 	default:
 		return nil, fmt.Errorf("unsupported key type: %s", keyType)
@@ -234,12 +191,23 @@ func (m *Manager) ImportPrivateKey(privateKey crypto.PrivateKey) (*Key, error) {
 	return key, nil
 }
 
-// ImportKey imports a key with the given private key and type
-func (m *Manager) ImportKey(privateKey crypto.PrivateKey, keyType string) (*Key, error) {
-	if keyType != "ed25519" {
+// ImportKey imports the raw private key of a key of the given type: an
+// ed25519 seed or expanded key, a secp256k1 key or a BLS secret key
+func (m *Manager) ImportKey(privateKey []byte, keyType string) (*Key, error) {
+	switch keyType {
+	case "ed25519":
+		key, err := softEd25519Key(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return m.ImportPrivateKey(key.PrivateKey)
+	case "secp256k1":
+		return m.ImportSecp256k1(privateKey)
+	case "bls":
+		return m.ImportBLS(privateKey)
+	default:
 		return nil, fmt.Errorf("unsupported key type for import: %s", keyType)
 	}
-	return m.ImportPrivateKey(privateKey)
 }
 
 // GetKey retrieves a key by ID
@@ -270,11 +238,15 @@ func (m *Manager) ExportKey(keyID ids.ID) (string, error) {
 		return "", err
 	}
 
-	if key.Type != "ed25519" || key.PrivateKey == crypto.EmptyPrivateKey {
+	if _, encrypted := m.keystores[keyID]; encrypted && !m.unlocked {
+		return "", ErrLocked
+	}
+	secret := keySecret(key)
+	if secret == nil {
 		return "", fmt.Errorf("key does not have exportable private key")
 	}
 
-	return hex.EncodeToString(key.PrivateKey[:]), nil
+	return hex.EncodeToString(secret), nil
 }
 
 // Get retrieves a key by ID
@@ -312,24 +284,30 @@ func (m *Manager) Delete(keyID ids.ID) error {
 	}
 
 	delete(m.keys, keyID)
+	delete(m.keystores, keyID)
+	delete(m.plaintext, keyID)
 
 	// Delete from disk
-	keyFile := filepath.Join(m.keyDir, fmt.Sprintf("%s.key", keyID))
-	if err := os.Remove(keyFile); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete key file: %w", err)
+	for _, ext := range []string{keyFileExt, keystoreFileExt, plaintextFileExt} {
+		if err := os.Remove(m.path(keyID, ext)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete key file: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// Save persists a key to disk
+// Save persists a key to disk. Its private key is encrypted with the
+// passphrase of the manager, which must be unlocked.
 func (m *Manager) Save(keyID ids.ID) error {
 	key, exists := m.keys[keyID]
 	if !exists {
 		return errors.New("key not found")
 	}
-
-	keyFile := filepath.Join(m.keyDir, fmt.Sprintf("%s.key", keyID))
+	secret := keySecret(key)
+	if secret != nil && !m.unlocked {
+		return ErrLocked
+	}
 
 	// Serialize key (excluding private key for security)
 	data, err := json.MarshalIndent(key, "", "  ")
@@ -337,19 +315,29 @@ func (m *Manager) Save(keyID ids.ID) error {
 		return fmt.Errorf("failed to marshal key: %w", err)
 	}
 
-	if err := os.WriteFile(keyFile, data, 0600); err != nil {
+	if err := os.WriteFile(m.path(keyID, keyFileExt), data, 0600); err != nil {
 		return fmt.Errorf("failed to write key file: %w", err)
 	}
 
-	// Save private key separately (encrypted in production)
-	privKeyFile := filepath.Join(m.keyDir, fmt.Sprintf("%s.priv", keyID))
-	if key.Type == "ed25519" && key.PrivateKey != crypto.EmptyPrivateKey {
-		privKeyData := key.PrivateKey[:]
-		if err := os.WriteFile(privKeyFile, privKeyData, 0600); err != nil {
-			return fmt.Errorf("failed to write private key file: %w", err)
-		}
+	if secret == nil {
+		return nil
+	}
+	encrypted, err := EncryptKey(secret, string(m.passphrase), m.params)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+	encrypted.ID = keyID.String()
+	encrypted.Address = key.Address.String()
+	if err := m.writeKeystore(keyID, encrypted); err != nil {
+		return err
 	}
+	m.keystores[keyID] = encrypted
 
+	// the keystore replaces the plaintext file of the key, if any
+	if err := os.Remove(m.path(keyID, plaintextFileExt)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete plaintext private key file: %w", err)
+	}
+	delete(m.plaintext, keyID)
 	return nil
 }
 
@@ -363,7 +351,144 @@ func (m *Manager) SaveAll() error {
 	return nil
 }
 
-// loadKeys loads all keys from disk
+// Unlock decrypts the private keys of the keystores with the passphrase,
+// which encrypts the keys saved until the manager is locked. Keys loaded
+// from plaintext files are encrypted and their plaintext files deleted.
+func (m *Manager) Unlock(passphrase string) error {
+	if passphrase == "" {
+		return ErrEmptyPassphrase
+	}
+	secrets, err := m.decryptKeystores(passphrase)
+	if err != nil {
+		return err
+	}
+	for keyID, secret := range secrets {
+		if err := setKeySecret(m.keys[keyID], secret); err != nil {
+			return fmt.Errorf("failed to unlock key %s: %w", keyID, err)
+		}
+	}
+	m.passphrase = []byte(passphrase)
+	m.unlocked = true
+
+	for keyID := range m.plaintext {
+		if err := m.Save(keyID); err != nil {
+			return fmt.Errorf("failed to migrate plaintext key %s: %w", keyID, err)
+		}
+	}
+	return nil
+}
+
+// Lock forgets the passphrase and the private keys persisted in keystores.
+// Keys that were never saved keep their private key.
+func (m *Manager) Lock() {
+	clear(m.passphrase)
+	m.passphrase = nil
+	m.unlocked = false
+	for keyID := range m.keystores {
+		if key, ok := m.keys[keyID]; ok {
+			clearKeySecret(key)
+		}
+	}
+}
+
+// Locked returns true if the manager is locked
+func (m *Manager) Locked() bool {
+	return !m.unlocked
+}
+
+// ChangePassphrase encrypts the keystores with a new passphrase. All the
+// keystores are encrypted before any is replaced, so a wrong passphrase
+// leaves them untouched.
+func (m *Manager) ChangePassphrase(oldPassphrase, newPassphrase string) error {
+	if newPassphrase == "" {
+		return ErrEmptyPassphrase
+	}
+	if m.unlocked && subtle.ConstantTimeCompare(m.passphrase, []byte(oldPassphrase)) != 1 {
+		return ErrWrongPassphrase
+	}
+	secrets, err := m.decryptKeystores(oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	rotated := make(map[ids.ID]*EncryptedKey, len(secrets))
+	for keyID, secret := range secrets {
+		encrypted, err := EncryptKey(secret, newPassphrase, m.params)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt key %s: %w", keyID, err)
+		}
+		encrypted.ID = m.keystores[keyID].ID
+		encrypted.Address = m.keystores[keyID].Address
+		rotated[keyID] = encrypted
+	}
+	for keyID, encrypted := range rotated {
+		if err := m.writeKeystore(keyID, encrypted); err != nil {
+			return err
+		}
+		m.keystores[keyID] = encrypted
+	}
+
+	if m.unlocked {
+		clear(m.passphrase)
+		m.passphrase = []byte(newPassphrase)
+	}
+	return nil
+}
+
+// SetKeystoreParams sets the kdf and cipher used to encrypt the keystores
+// saved from now on. Existing keystores keep their parameters until saved
+// again.
+func (m *Manager) SetKeystoreParams(params KeystoreParams) {
+	m.params = params
+}
+
+// decryptKeystores decrypts all the keystores with the passphrase
+func (m *Manager) decryptKeystores(passphrase string) (map[ids.ID][]byte, error) {
+	secrets := make(map[ids.ID][]byte, len(m.keystores))
+	for keyID, encrypted := range m.keystores {
+		secret, err := DecryptKey(encrypted, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt key %s: %w", keyID, err)
+		}
+		secrets[keyID] = secret
+	}
+	return secrets, nil
+}
+
+// writeKeystore writes the keystore of a key atomically
+func (m *Manager) writeKeystore(keyID ids.ID, encrypted *EncryptedKey) error {
+	data, err := json.MarshalIndent(encrypted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal keystore of key %s: %w", keyID, err)
+	}
+
+	tmpFile, err := os.CreateTemp(m.keyDir, keyID.String()+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write keystore of key %s: %w", keyID, err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write keystore of key %s: %w", keyID, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write keystore of key %s: %w", keyID, err)
+	}
+	if err := os.Rename(tmpPath, m.path(keyID, keystoreFileExt)); err != nil {
+		return fmt.Errorf("failed to write keystore of key %s: %w", keyID, err)
+	}
+	return nil
+}
+
+// path returns the path of the file of a key with the extension
+func (m *Manager) path(keyID ids.ID, ext string) string {
+	return filepath.Join(m.keyDir, keyID.String()+ext)
+}
+
+// loadKeys loads all keys from disk. Private keys in keystores are
+// decrypted on unlock.
 func (m *Manager) loadKeys() error {
 	entries, err := os.ReadDir(m.keyDir)
 	if err != nil {
@@ -374,7 +499,7 @@ func (m *Manager) loadKeys() error {
 	}
 
 	for _, entry := range entries {
-		if filepath.Ext(entry.Name()) != ".key" {
+		if filepath.Ext(entry.Name()) != keyFileExt {
 			continue
 		}
 
@@ -389,16 +514,25 @@ func (m *Manager) loadKeys() error {
 			return fmt.Errorf("failed to unmarshal key %s: %w", entry.Name(), err)
 		}
 
-		// Try to load private key
-		privKeyFile := filepath.Join(m.keyDir, fmt.Sprintf("%s.priv", key.ID))
-		if privKeyData, err := os.ReadFile(privKeyFile); err == nil && key.Type == "ed25519" {
-			// Reconstruct private key (simplified - in production this would be encrypted)
-			if len(privKeyData) == crypto.PrivateKeyLen {
-				key.PrivateKey = crypto.PrivateKey(privKeyData)
-			} else {
-				// Skip this key, it has invalid private key data
-				continue
+		keystoreData, err := os.ReadFile(m.path(key.ID, keystoreFileExt))
+		switch {
+		case err == nil:
+			var encrypted EncryptedKey
+			if err := json.Unmarshal(keystoreData, &encrypted); err != nil {
+				return fmt.Errorf("failed to unmarshal keystore of key %s: %w", key.ID, err)
+			}
+			m.keystores[key.ID] = &encrypted
+		case os.IsNotExist(err):
+			// Try to load a plaintext private key saved before keystores
+			if privKeyData, err := os.ReadFile(m.path(key.ID, plaintextFileExt)); err == nil && key.Type == "ed25519" {
+				if err := setKeySecret(&key, privKeyData); err != nil {
+					// Skip this key, it has invalid private key data
+					continue
+				}
+				m.plaintext[key.ID] = struct{}{}
 			}
+		default:
+			return fmt.Errorf("failed to read keystore of key %s: %w", key.ID, err)
 		}
 
 		m.keys[key.ID] = &key
@@ -407,6 +541,64 @@ func (m *Manager) loadKeys() error {
 	return nil
 }
 
+// keySecret returns the private key of a key to persist, or nil if it
+// has none
+func keySecret(key *Key) []byte {
+	switch key.Type {
+	case "ed25519":
+		if key.PrivateKey != crypto.EmptyPrivateKey {
+			return key.PrivateKey[:]
+		}
+	case "secp256k1":
+		if key.Secp256k1PrivateKey != nil {
+			return key.Secp256k1PrivateKey.Serialize()
+		}
+	case "bls":
+		if key.BLSSecretKey != nil {
+			return bls.SecretKeyToBytes(key.BLSSecretKey)
+		}
+	}
+	return nil
+}
+
+// setKeySecret sets the private key of a key from its persisted form
+func setKeySecret(key *Key, secret []byte) error {
+	switch key.Type {
+	case "ed25519":
+		if len(secret) != crypto.PrivateKeyLen {
+			return crypto.ErrInvalidPrivateKey
+		}
+		key.PrivateKey = crypto.PrivateKey(secret)
+		return nil
+	case "secp256k1":
+		privateKey, err := parseSecp256k1(secret)
+		if err != nil {
+			return err
+		}
+		key.Secp256k1PrivateKey = privateKey
+		return nil
+	case "bls":
+		blsKey, err := bls.SecretKeyFromBytes(secret)
+		if err != nil {
+			return err
+		}
+		key.BLSSecretKey = blsKey
+		return nil
+	default:
+		return fmt.Errorf("unsupported key type for private key: %s", key.Type)
+	}
+}
+
+// clearKeySecret forgets the private key of a key
+func clearKeySecret(key *Key) {
+	key.PrivateKey = crypto.EmptyPrivateKey
+	if key.Secp256k1PrivateKey != nil {
+		key.Secp256k1PrivateKey.Zero()
+		key.Secp256k1PrivateKey = nil
+	}
+	key.BLSSecretKey = nil
+}
+
 // ExportKey exports a key in various formats
 func ExportKey(key *Key, format string, writer io.Writer) error {
 	switch format {
@@ -415,97 +607,53 @@ func ExportKey(key *Key, format string, writer io.Writer) error {
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(key)
 	case "hex":
-		if key.Type != "ed25519" || key.PrivateKey == crypto.EmptyPrivateKey {
+		secret := keySecret(key)
+		if secret == nil {
 			return errors.New("no private key to export")
 		}
-		_, err := writer.Write([]byte(hex.EncodeToString(key.PrivateKey[:])))
+		_, err := writer.Write([]byte(hex.EncodeToString(secret)))
 		return err
 	default:
 		return fmt.Errorf("unsupported export format: %s", format)
 	}
 }
 
-// LoadSoft loads a soft key from a file path
-func LoadSoft(networkID uint32, keyPath string) (*Key, error) {
-	keyBytes, err := os.ReadFile(keyPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read key file: %w", err)
-	}
-
-	// Try to parse as hex-encoded private key (64 chars)
-	keyStr := strings.TrimSpace(string(keyBytes))
-
-	// Check if it's a prefixed key (like "PrivateKey-...")
-	const privKeyPrefix = "PrivateKey-"
-	if strings.HasPrefix(keyStr, privKeyPrefix) {
-		keyStr = strings.TrimPrefix(keyStr, privKeyPrefix)
-		// TODO: decode CB58 format if needed
-	}
-
-	// Try hex decoding
-	privateKeyBytes, err := hex.DecodeString(keyStr)
-	if err == nil && len(privateKeyBytes) == 32 {
-		// This is a valid 32-byte private key
-		var privateKey crypto.PrivateKey
-		copy(privateKey[:], privateKeyBytes)
-
-		// For compatibility, pad to 64 bytes if needed (seed + public key format)
-		if len(privateKey) == 32 {
-			// Generate public key from private key seed
-			publicKey := privateKey.PublicKey()
-			fullKey := append(privateKeyBytes, publicKey[:]...)
-			copy(privateKey[:], fullKey[:crypto.PrivateKeyLen])
-		}
-
-		return &Key{
-			Type:       "ed25519",
-			PrivateKey: privateKey,
-			PublicKey:  privateKey.PublicKey(),
-			Address:    generateAddress(privateKey.PublicKey()),
-		}, nil
+// C returns the EIP-55 checksummed C-Chain address of secp256k1 keys.
+// Other key types have no C-Chain address, and return an empty string.
+func (k *Key) C() string {
+	if k.Type != "secp256k1" {
+		return ""
 	}
-
-	// Try JSON format
-	var keyData struct {
-		PrivateKey string `json:"privateKey"`
-		Type       string `json:"type"`
+	addr, err := ethAddress(k.Secp256k1PublicKey)
+	if err != nil {
+		return ""
 	}
-	if err := json.Unmarshal(keyBytes, &keyData); err == nil {
-		privateKeyBytes, err := hex.DecodeString(keyData.PrivateKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode private key from JSON: %w", err)
-		}
-
-		var privateKey crypto.PrivateKey
-		if len(privateKeyBytes) == 32 {
-			// Seed only, need to expand
-			copy(privateKey[:32], privateKeyBytes)
-			publicKey := privateKey.PublicKey()
-			copy(privateKey[32:], publicKey[:])
-		} else if len(privateKeyBytes) == crypto.PrivateKeyLen {
-			copy(privateKey[:], privateKeyBytes)
-		} else {
-			return nil, fmt.Errorf("invalid private key length: %d", len(privateKeyBytes))
-		}
+	return addr
+}
 
-		return &Key{
-			Type:       keyData.Type,
-			PrivateKey: privateKey,
-			PublicKey:  privateKey.PublicKey(),
-			Address:    generateAddress(privateKey.PublicKey()),
-		}, nil
-	}
+// P returns the P-Chain address of the key for the network [hrp]
+func (k *Key) P(hrp string) (string, error) {
+	return k.chainAddress("P", hrp)
+}
 
-	return nil, fmt.Errorf("unable to parse key file")
+// X returns the X-Chain address of the key for the network [hrp]
+func (k *Key) X(hrp string) (string, error) {
+	return k.chainAddress("X", hrp)
 }
 
-// C returns the Ethereum-style address (0x prefixed hex string)
-func (k *Key) C() string {
-	// Convert the address to hex format
-	return fmt.Sprintf("0x%x", k.Address[:])
+// chainAddress returns the bech32 address of the key on [chain]
+func (k *Key) chainAddress(chain, hrp string) (string, error) {
+	addr, err := address.FormatBech32(hrp, k.Address[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to format %s-Chain address: %w", chain, err)
+	}
+	return chain + "-" + addr, nil
 }
 
 // PrivKeyHex returns the private key as a hex string
 func (k *Key) PrivKeyHex() string {
+	if k.Type == "secp256k1" || k.Type == "bls" {
+		return hex.EncodeToString(keySecret(k))
+	}
 	return hex.EncodeToString(k.PrivateKey[:32]) // Only return the seed part
 }
@@ -4,6 +4,8 @@
 package key
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -29,6 +31,11 @@ func TestManager_GenerateKey(t *testing.T) {
 			keyType: "ed25519",
 			wantErr: false,
 		},
+		{
+			name:    "generate secp256k1 key",
+			keyType: "secp256k1",
+			wantErr: false,
+		},
 		{
 			name:    "generate bls key",
 			keyType: "bls",
@@ -59,10 +66,22 @@ func TestManager_GenerateKey(t *testing.T) {
 	}
 }
 
+// newUnlockedManager creates a manager unlocked with [testPassphrase],
+// using light keystore parameters
+func newUnlockedManager(t *testing.T, keyDir string) *Manager {
+	t.Helper()
+	manager, err := NewManager(keyDir)
+	require.NoError(t, err)
+	manager.SetKeystoreParams(LightKeystoreParams)
+	require.NoError(t, manager.Unlock(testPassphrase))
+	return manager
+}
+
+const testPassphrase = "correct horse battery staple"
+
 func TestManager_SaveAndLoadKey(t *testing.T) {
 	tmpDir := t.TempDir()
-	manager, err := NewManager(tmpDir)
-	require.NoError(t, err)
+	manager := newUnlockedManager(t, tmpDir)
 
 	// Generate a key
 	key, err := manager.GenerateKey("ed25519")
@@ -77,6 +96,13 @@ func TestManager_SaveAndLoadKey(t *testing.T) {
 	_, err = os.Stat(keyFile)
 	require.NoError(t, err)
 
+	// The private key is only saved encrypted
+	_, err = os.Stat(filepath.Join(tmpDir, key.ID.String()+".priv"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	keystore, err := os.ReadFile(filepath.Join(tmpDir, key.ID.String()+".keystore"))
+	require.NoError(t, err)
+	require.NotContains(t, string(keystore), hex.EncodeToString(key.PrivateKey[:32]))
+
 	// Create new manager to test loading
 	manager2, err := NewManager(tmpDir)
 	require.NoError(t, err)
@@ -87,6 +113,119 @@ func TestManager_SaveAndLoadKey(t *testing.T) {
 	require.Equal(t, key.ID, loadedKey.ID)
 	require.Equal(t, key.Type, loadedKey.Type)
 	require.Equal(t, key.Address, loadedKey.Address)
+
+	// The private key is decrypted on unlock
+	require.True(t, manager2.Locked())
+	require.Zero(t, loadedKey.PrivateKey)
+	require.NoError(t, manager2.Unlock(testPassphrase))
+	require.Equal(t, key.PrivateKey, loadedKey.PrivateKey)
+}
+
+func TestManager_LockUnlock(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := newUnlockedManager(t, tmpDir)
+	key, err := manager.GenerateKey("ed25519")
+	require.NoError(t, err)
+	privateKey := key.PrivateKey
+	require.NoError(t, manager.Save(key.ID))
+
+	manager.Lock()
+	require.True(t, manager.Locked())
+	require.Zero(t, key.PrivateKey)
+	_, err = manager.ExportKey(key.ID)
+	require.ErrorIs(t, err, ErrLocked)
+
+	// keys with a private key can not be saved while locked
+	unsaved, err := manager.GenerateKey("ed25519")
+	require.NoError(t, err)
+	require.ErrorIs(t, manager.Save(unsaved.ID), ErrLocked)
+
+	require.ErrorIs(t, manager.Unlock("wrong passphrase"), ErrWrongPassphrase)
+	require.ErrorIs(t, manager.Unlock(""), ErrEmptyPassphrase)
+	require.True(t, manager.Locked())
+
+	require.NoError(t, manager.Unlock(testPassphrase))
+	require.False(t, manager.Locked())
+	require.Equal(t, privateKey, key.PrivateKey)
+	exported, err := manager.ExportKey(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(privateKey[:]), exported)
+}
+
+func TestManager_ChangePassphrase(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := newUnlockedManager(t, tmpDir)
+	key, err := manager.GenerateKey("ed25519")
+	require.NoError(t, err)
+	require.NoError(t, manager.Save(key.ID))
+
+	const newPassphrase = "new passphrase"
+	require.ErrorIs(t, manager.ChangePassphrase("wrong passphrase", newPassphrase), ErrWrongPassphrase)
+	require.ErrorIs(t, manager.ChangePassphrase(testPassphrase, ""), ErrEmptyPassphrase)
+	require.NoError(t, manager.ChangePassphrase(testPassphrase, newPassphrase))
+
+	// keys saved after the change use the new passphrase
+	other, err := manager.GenerateKey("ed25519")
+	require.NoError(t, err)
+	require.NoError(t, manager.Save(other.ID))
+
+	reloaded, err := NewManager(tmpDir)
+	require.NoError(t, err)
+	reloaded.SetKeystoreParams(LightKeystoreParams)
+	require.ErrorIs(t, reloaded.Unlock(testPassphrase), ErrWrongPassphrase)
+	require.NoError(t, reloaded.Unlock(newPassphrase))
+	for _, k := range []*Key{key, other} {
+		loaded, err := reloaded.Get(k.ID)
+		require.NoError(t, err)
+		require.Equal(t, k.PrivateKey, loaded.PrivateKey)
+	}
+
+	// a locked manager verifies the old passphrase against the keystores
+	reloaded.Lock()
+	require.ErrorIs(t, reloaded.ChangePassphrase(testPassphrase, "third"), ErrWrongPassphrase)
+	require.NoError(t, reloaded.ChangePassphrase(newPassphrase, "third"))
+	require.True(t, reloaded.Locked())
+	require.NoError(t, reloaded.Unlock("third"))
+}
+
+func TestManager_MigratePlaintextKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// a key saved as plaintext, before keystores
+	privateKey, err := crypto.GeneratePrivateKey()
+	require.NoError(t, err)
+	key := &Key{
+		ID:         ids.GenerateTestID(),
+		Type:       "ed25519",
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.PublicKey(),
+		Address:    generateAddress(privateKey.PublicKey()),
+	}
+	data, err := json.Marshal(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, key.ID.String()+".key"), data, 0o600))
+	plaintextFile := filepath.Join(tmpDir, key.ID.String()+".priv")
+	require.NoError(t, os.WriteFile(plaintextFile, privateKey[:], 0o600))
+
+	manager, err := NewManager(tmpDir)
+	require.NoError(t, err)
+	manager.SetKeystoreParams(LightKeystoreParams)
+	require.NoError(t, manager.Unlock(testPassphrase))
+	_, err = os.Stat(plaintextFile)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	reloaded, err := NewManager(tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, reloaded.Unlock(testPassphrase))
+	loaded, err := reloaded.Get(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, privateKey, loaded.PrivateKey)
+
+	// deleting the key removes its keystore
+	require.NoError(t, reloaded.Delete(key.ID))
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
 }
 
 func TestManager_ImportKey(t *testing.T) {
@@ -99,10 +238,20 @@ func TestManager_ImportKey(t *testing.T) {
 	require.NoError(t, err)
 
 	// Import the key
-	key, err := manager.ImportKey(priv, "ed25519")
+	key, err := manager.ImportKey(priv[:], "ed25519")
 	require.NoError(t, err)
 	require.NotNil(t, key)
 	require.Equal(t, priv, key.PrivateKey)
+
+	secpBytes, err := hex.DecodeString(ewoqKey)
+	require.NoError(t, err)
+	key, err = manager.ImportKey(secpBytes, "secp256k1")
+	require.NoError(t, err)
+	require.Equal(t, "secp256k1", key.Type)
+	require.Equal(t, ewoqKey, key.PrivKeyHex())
+
+	_, err = manager.ImportKey(secpBytes, "sr25519")
+	require.ErrorContains(t, err, "unsupported key type")
 }
 
 func TestManager_ExportKey(t *testing.T) {
@@ -0,0 +1,333 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KeystoreVersion is the version of the keystore format, which follows
+	// the Ethereum v3 keystore
+	KeystoreVersion = 3
+
+	// KDFScrypt derives keystore keys with scrypt
+	KDFScrypt = "scrypt"
+	// KDFArgon2id derives keystore keys with argon2id
+	KDFArgon2id = "argon2id"
+	// KDFPBKDF2 derives keystore keys with PBKDF2-HMAC-SHA256. It is only
+	// supported to decrypt Ethereum keystores.
+	KDFPBKDF2 = "pbkdf2"
+
+	// CipherAESGCM encrypts keystores with AES-256-GCM
+	CipherAESGCM = "aes-256-gcm"
+	// CipherXChaCha20Poly1305 encrypts keystores with XChaCha20-Poly1305
+	CipherXChaCha20Poly1305 = "xchacha20-poly1305"
+	// CipherAES128CTR is the cipher of Ethereum keystores, authenticated by
+	// a keccak256 mac. It is only supported for decryption.
+	CipherAES128CTR = "aes-128-ctr"
+
+	keystoreKeyLen  = 32
+	keystoreSaltLen = 32
+
+	// The kdf parameters of a keystore are bounded, so a crafted keystore
+	// can not make decryption use unbounded memory or time. The bounds are
+	// well above the parameters of the standard keystores.
+	maxScryptN = 1 << 20
+	// maxScryptRP bounds the product of the scrypt r and p parameters
+	maxScryptRP = 64
+	// maxScryptMemory bounds the 128*N*r bytes scrypt allocates
+	maxScryptMemory = 1 << 30
+	// maxArgon2Memory is 1 GiB, in KiB. The argon2id thread count is a
+	// uint8, so it can not exceed 255.
+	maxArgon2Memory = 1 << 20
+	maxArgon2Time   = 16
+	maxPBKDF2Rounds = 10_000_000
+)
+
+var (
+	// ErrWrongPassphrase is returned when a keystore can not be decrypted
+	// with the passphrase
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrUnsupportedKeystore is returned for keystores with an unknown
+	// version, kdf or cipher
+	ErrUnsupportedKeystore = errors.New("unsupported keystore")
+	// ErrInvalidKeystore is returned for malformed keystores
+	ErrInvalidKeystore = errors.New("invalid keystore")
+	// ErrLocked is returned when a private key is needed from a locked manager
+	ErrLocked = errors.New("key manager is locked")
+	// ErrEmptyPassphrase is returned when unlocking with an empty passphrase
+	ErrEmptyPassphrase = errors.New("passphrase must not be empty")
+)
+
+// KeystoreParams selects the kdf and cipher used to encrypt keystores, and
+// the cost of the kdf
+type KeystoreParams struct {
+	KDF    string
+	Cipher string
+
+	// scrypt cost parameters
+	ScryptN int
+	ScryptR int
+	ScryptP int
+
+	// argon2id cost parameters, memory is in KiB
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+var (
+	// StandardKeystoreParams are the parameters of the Ethereum standard
+	// scrypt keystore, with AES-256-GCM
+	StandardKeystoreParams = KeystoreParams{
+		KDF:     KDFScrypt,
+		Cipher:  CipherAESGCM,
+		ScryptN: 1 << 18,
+		ScryptR: 8,
+		ScryptP: 1,
+	}
+	// Argon2idKeystoreParams use argon2id with the parameters recommended
+	// by RFC 9106 for memory constrained environments, with
+	// XChaCha20-Poly1305
+	Argon2idKeystoreParams = KeystoreParams{
+		KDF:           KDFArgon2id,
+		Cipher:        CipherXChaCha20Poly1305,
+		Argon2Time:    3,
+		Argon2Memory:  64 * 1024,
+		Argon2Threads: 4,
+	}
+	// LightKeystoreParams trade security for speed. They are meant for tests
+	// and throwaway keys.
+	LightKeystoreParams = KeystoreParams{
+		KDF:     KDFScrypt,
+		Cipher:  CipherAESGCM,
+		ScryptN: 1 << 12,
+		ScryptR: 8,
+		ScryptP: 6,
+	}
+)
+
+// EncryptedKey is a private key encrypted with a passphrase, serialized
+// like an Ethereum v3 keystore
+type EncryptedKey struct {
+	Version int            `json:"version"`
+	ID      string         `json:"id"`
+	Address string         `json:"address,omitempty"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+}
+
+// KeystoreCrypto holds the encrypted private key and the parameters to
+// decrypt it
+type KeystoreCrypto struct {
+	Cipher       string       `json:"cipher"`
+	CipherText   string       `json:"ciphertext"`
+	CipherParams CipherParams `json:"cipherparams"`
+	KDF          string       `json:"kdf"`
+	KDFParams    KDFParams    `json:"kdfparams"`
+	// MAC authenticates the ciphertext of Ethereum keystores
+	MAC string `json:"mac,omitempty"`
+}
+
+// CipherParams are the parameters of the keystore cipher
+type CipherParams struct {
+	Nonce string `json:"nonce,omitempty"`
+	// IV is the counter of Ethereum keystores
+	IV string `json:"iv,omitempty"`
+}
+
+// KDFParams are the parameters of the keystore kdf. Only the parameters of
+// the kdf in use are set.
+type KDFParams struct {
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+
+	// pbkdf2
+	C   int    `json:"c,omitempty"`
+	PRF string `json:"prf,omitempty"`
+}
+
+// EncryptKey encrypts a private key with a key derived from the passphrase
+func EncryptKey(secret []byte, passphrase string, params KeystoreParams) (*EncryptedKey, error) {
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	kdfParams := KDFParams{DKLen: keystoreKeyLen, Salt: hex.EncodeToString(salt)}
+	switch params.KDF {
+	case KDFScrypt:
+		kdfParams.N, kdfParams.R, kdfParams.P = params.ScryptN, params.ScryptR, params.ScryptP
+	case KDFArgon2id:
+		kdfParams.Time, kdfParams.Memory, kdfParams.Threads = params.Argon2Time, params.Argon2Memory, params.Argon2Threads
+	}
+	derivedKey, err := deriveKeystoreKey(params.KDF, kdfParams, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newKeystoreAEAD(params.Cipher, derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return &EncryptedKey{
+		Version: KeystoreVersion,
+		Crypto: KeystoreCrypto{
+			Cipher:       params.Cipher,
+			CipherText:   hex.EncodeToString(aead.Seal(nil, nonce, secret, nil)),
+			CipherParams: CipherParams{Nonce: hex.EncodeToString(nonce)},
+			KDF:          params.KDF,
+			KDFParams:    kdfParams,
+		},
+	}, nil
+}
+
+// DecryptKey decrypts a private key encrypted by EncryptKey, or by an
+// Ethereum v3 keystore
+func DecryptKey(encrypted *EncryptedKey, passphrase string) ([]byte, error) {
+	if encrypted.Version != KeystoreVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedKeystore, encrypted.Version)
+	}
+	c := encrypted.Crypto
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ciphertext: %w", ErrInvalidKeystore, err)
+	}
+
+	derivedKey, err := deriveKeystoreKey(c.KDF, c.KDFParams, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if c.Cipher == CipherAES128CTR {
+		return decryptEthereumKeystore(c, derivedKey, cipherText)
+	}
+
+	nonce, err := hex.DecodeString(c.CipherParams.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid nonce: %w", ErrInvalidKeystore, err)
+	}
+	aead, err := newKeystoreAEAD(c.Cipher, derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: nonce must be %d bytes", ErrInvalidKeystore, aead.NonceSize())
+	}
+	secret, err := aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		// authentication fails for a wrong passphrase and for a tampered
+		// keystore alike
+		return nil, ErrWrongPassphrase
+	}
+	return secret, nil
+}
+
+// decryptEthereumKeystore verifies the mac of an Ethereum keystore and
+// decrypts its ciphertext
+func decryptEthereumKeystore(c KeystoreCrypto, derivedKey, cipherText []byte) ([]byte, error) {
+	iv, err := hex.DecodeString(c.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: invalid iv", ErrInvalidKeystore)
+	}
+	mac, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid mac: %w", ErrInvalidKeystore, err)
+	}
+	wantMAC := keccak256(append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+	if subtle.ConstantTimeCompare(mac, wantMAC) != 1 {
+		return nil, ErrWrongPassphrase
+	}
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, cipherText)
+	return secret, nil
+}
+
+// deriveKeystoreKey derives the encryption key of a keystore from the
+// passphrase
+func deriveKeystoreKey(kdf string, params KDFParams, passphrase string) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("%w: invalid salt", ErrInvalidKeystore)
+	}
+	if params.DKLen != keystoreKeyLen {
+		return nil, fmt.Errorf("%w: derived key must be %d bytes", ErrInvalidKeystore, keystoreKeyLen)
+	}
+
+	switch kdf {
+	case KDFScrypt:
+		if params.N > maxScryptN || params.R <= 0 || params.P <= 0 ||
+			params.R > maxScryptRP || params.P > maxScryptRP || params.R*params.P > maxScryptRP ||
+			128*int64(params.N)*int64(params.R) > maxScryptMemory {
+			return nil, fmt.Errorf("%w: scrypt parameters exceed n=%d, r*p=%d or %d bytes of memory", ErrInvalidKeystore, maxScryptN, maxScryptRP, maxScryptMemory)
+		}
+		key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidKeystore, err)
+		}
+		return key, nil
+	case KDFArgon2id:
+		if params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+			return nil, fmt.Errorf("%w: invalid argon2id parameters", ErrInvalidKeystore)
+		}
+		if params.Time > maxArgon2Time || params.Memory > maxArgon2Memory {
+			return nil, fmt.Errorf("%w: argon2id parameters exceed time=%d or memory=%d KiB", ErrInvalidKeystore, maxArgon2Time, maxArgon2Memory)
+		}
+		return argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, uint32(params.DKLen)), nil
+	case KDFPBKDF2:
+		if params.PRF != "hmac-sha256" || params.C <= 0 || params.C > maxPBKDF2Rounds {
+			return nil, fmt.Errorf("%w: invalid pbkdf2 parameters", ErrInvalidKeystore)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w: kdf %q", ErrUnsupportedKeystore, kdf)
+	}
+}
+
+// newKeystoreAEAD returns the cipher of a keystore
+func newKeystoreAEAD(name string, key []byte) (cipher.AEAD, error) {
+	switch name {
+	case CipherAESGCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case CipherXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, fmt.Errorf("%w: cipher %q", ErrUnsupportedKeystore, name)
+	}
+}
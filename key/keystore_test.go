@@ -0,0 +1,167 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecryptKey(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	lightArgon2id := Argon2idKeystoreParams
+	lightArgon2id.Argon2Memory = 1024
+	lightArgon2id.Argon2Time = 1
+
+	tests := []struct {
+		name   string
+		params KeystoreParams
+	}{
+		{name: "scrypt aes-256-gcm", params: LightKeystoreParams},
+		{name: "argon2id xchacha20-poly1305", params: lightArgon2id},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := EncryptKey(secret, testPassphrase, tt.params)
+			require.NoError(t, err)
+			require.Equal(t, KeystoreVersion, encrypted.Version)
+			require.Equal(t, tt.params.KDF, encrypted.Crypto.KDF)
+			require.Equal(t, tt.params.Cipher, encrypted.Crypto.Cipher)
+
+			// keystores round trip through their json form
+			data, err := json.Marshal(encrypted)
+			require.NoError(t, err)
+			var decoded EncryptedKey
+			require.NoError(t, json.Unmarshal(data, &decoded))
+
+			decrypted, err := DecryptKey(&decoded, testPassphrase)
+			require.NoError(t, err)
+			require.Equal(t, secret, decrypted)
+
+			_, err = DecryptKey(&decoded, "wrong passphrase")
+			require.ErrorIs(t, err, ErrWrongPassphrase)
+
+			// tampering is detected
+			cipherText, err := hex.DecodeString(decoded.Crypto.CipherText)
+			require.NoError(t, err)
+			cipherText[0] ^= 1
+			decoded.Crypto.CipherText = hex.EncodeToString(cipherText)
+			_, err = DecryptKey(&decoded, testPassphrase)
+			require.ErrorIs(t, err, ErrWrongPassphrase)
+		})
+	}
+
+	// the same secret encrypts differently every time
+	a, err := EncryptKey(secret, testPassphrase, LightKeystoreParams)
+	require.NoError(t, err)
+	b, err := EncryptKey(secret, testPassphrase, LightKeystoreParams)
+	require.NoError(t, err)
+	require.NotEqual(t, a.Crypto.CipherText, b.Crypto.CipherText)
+	require.NotEqual(t, a.Crypto.KDFParams.Salt, b.Crypto.KDFParams.Salt)
+}
+
+func TestDecryptKey_Errors(t *testing.T) {
+	encrypt := func() *EncryptedKey {
+		encrypted, err := EncryptKey([]byte("secret"), testPassphrase, LightKeystoreParams)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*EncryptedKey)
+		wantErr error
+	}{
+		{
+			name:    "version",
+			modify:  func(e *EncryptedKey) { e.Version = 4 },
+			wantErr: ErrUnsupportedKeystore,
+		},
+		{
+			name:    "kdf",
+			modify:  func(e *EncryptedKey) { e.Crypto.KDF = "bcrypt" },
+			wantErr: ErrUnsupportedKeystore,
+		},
+		{
+			name:    "cipher",
+			modify:  func(e *EncryptedKey) { e.Crypto.Cipher = "aes-128-ecb" },
+			wantErr: ErrUnsupportedKeystore,
+		},
+		{
+			name:    "salt",
+			modify:  func(e *EncryptedKey) { e.Crypto.KDFParams.Salt = "zz" },
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name:    "scrypt parameters",
+			modify:  func(e *EncryptedKey) { e.Crypto.KDFParams.N = 3 },
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name:    "scrypt n above the cap",
+			modify:  func(e *EncryptedKey) { e.Crypto.KDFParams.N = maxScryptN << 1 },
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name:    "scrypt r*p above the cap",
+			modify:  func(e *EncryptedKey) { e.Crypto.KDFParams.R, e.Crypto.KDFParams.P = 16, 8 },
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name:    "scrypt r*p overflow",
+			modify:  func(e *EncryptedKey) { e.Crypto.KDFParams.R, e.Crypto.KDFParams.P = math.MaxInt, math.MaxInt },
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name: "scrypt memory above the cap",
+			modify: func(e *EncryptedKey) {
+				e.Crypto.KDFParams.N, e.Crypto.KDFParams.R, e.Crypto.KDFParams.P = maxScryptN, 16, 1
+			},
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name: "argon2id memory above the cap",
+			modify: func(e *EncryptedKey) {
+				e.Crypto.KDF = KDFArgon2id
+				e.Crypto.KDFParams = KDFParams{DKLen: 32, Salt: e.Crypto.KDFParams.Salt, Time: 1, Memory: maxArgon2Memory + 1, Threads: 255}
+			},
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name: "argon2id time above the cap",
+			modify: func(e *EncryptedKey) {
+				e.Crypto.KDF = KDFArgon2id
+				e.Crypto.KDFParams = KDFParams{DKLen: 32, Salt: e.Crypto.KDFParams.Salt, Time: maxArgon2Time + 1, Memory: 64, Threads: 1}
+			},
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name: "pbkdf2 rounds above the cap",
+			modify: func(e *EncryptedKey) {
+				e.Crypto.KDF = KDFPBKDF2
+				e.Crypto.KDFParams = KDFParams{DKLen: 32, Salt: e.Crypto.KDFParams.Salt, C: maxPBKDF2Rounds + 1, PRF: "hmac-sha256"}
+			},
+			wantErr: ErrInvalidKeystore,
+		},
+		{
+			name:    "nonce",
+			modify:  func(e *EncryptedKey) { e.Crypto.CipherParams.Nonce = "00" },
+			wantErr: ErrInvalidKeystore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted := encrypt()
+			tt.modify(encrypted)
+			_, err := DecryptKey(encrypted, testPassphrase)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// mnemonicWordBits is the number of entropy and checksum bits a word encodes
+	mnemonicWordBits = 11
+	// seedIterations is the number of PBKDF2 iterations of the BIP39 seed
+	seedIterations = 2048
+	// SeedLen is the length of a BIP39 seed
+	SeedLen = 64
+)
+
+var (
+	// ErrInvalidEntropy is returned for entropy sizes BIP39 does not support
+	ErrInvalidEntropy = errors.New("entropy must be 128 to 256 bits, a multiple of 32")
+	// ErrInvalidMnemonic is returned for mnemonics with unknown words or a wrong length
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+	// ErrMnemonicChecksum is returned for mnemonics whose checksum does not match
+	ErrMnemonicChecksum = errors.New("invalid mnemonic checksum")
+)
+
+//go:embed wordlist_english.txt
+var englishWords string
+
+// wordList is the BIP39 english word list, and wordIndex maps its words to
+// their index
+var (
+	wordList  = strings.Fields(englishWords)
+	wordIndex = func() map[string]int {
+		index := make(map[string]int, len(wordList))
+		for i, word := range wordList {
+			index[word] = i
+		}
+		return index
+	}()
+)
+
+// GenerateMnemonic generates a BIP39 mnemonic from [bitSize] bits of entropy.
+// 128 bits give 12 words and 256 bits give 24 words.
+func GenerateMnemonic(bitSize int) ([]string, error) {
+	if err := validateEntropySize(bitSize); err != nil {
+		return nil, fmt.Errorf("unsupported bit size %d: %w", bitSize, err)
+	}
+	entropy := make([]byte, bitSize/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes entropy as a BIP39 mnemonic
+func EntropyToMnemonic(entropy []byte) ([]string, error) {
+	if err := validateEntropySize(len(entropy) * 8); err != nil {
+		return nil, err
+	}
+	// the checksum is the first bits of the sha256 of the entropy, one bit
+	// for every 32 bits of entropy
+	checksum := sha256.Sum256(entropy)
+	bits := append(append([]byte{}, entropy...), checksum[0])
+	words := make([]string, (len(entropy)*8+len(entropy)/4)/mnemonicWordBits)
+	for i := range words {
+		words[i] = wordList[readBits(bits, i*mnemonicWordBits, mnemonicWordBits)]
+	}
+	return words, nil
+}
+
+// MnemonicToEntropy decodes a BIP39 mnemonic, verifying its checksum
+func MnemonicToEntropy(mnemonic []string) ([]byte, error) {
+	totalBits := len(mnemonic) * mnemonicWordBits
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+	if len(mnemonic)%3 != 0 || validateEntropySize(entropyBits) != nil {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonic, len(mnemonic))
+	}
+
+	// one extra byte holds the checksum
+	bits := make([]byte, entropyBits/8+1)
+	for i, word := range mnemonic {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		writeBits(bits, i*mnemonicWordBits, mnemonicWordBits, index)
+	}
+
+	entropy := bits[:entropyBits/8]
+	checksum := sha256.Sum256(entropy)
+	mask := byte(0xff) << (8 - checksumBits)
+	if bits[len(bits)-1]&mask != checksum[0]&mask {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic returns an error if the mnemonic is not a valid BIP39 mnemonic
+func ValidateMnemonic(mnemonic []string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// ParseMnemonic splits a mnemonic phrase into its words and validates it
+func ParseMnemonic(phrase string) ([]string, error) {
+	mnemonic := strings.Fields(norm.NFKD.String(phrase))
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return mnemonic, nil
+}
+
+// MnemonicToSeed validates the mnemonic and returns its BIP39 seed, salted
+// with the optional passphrase
+func MnemonicToSeed(mnemonic []string, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	password := norm.NFKD.String(strings.Join(mnemonic, " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(password), []byte(salt), seedIterations, SeedLen, sha512.New), nil
+}
+
+// validateEntropySize returns an error if BIP39 does not support [bitSize]
+// bits of entropy
+func validateEntropySize(bitSize int) error {
+	if bitSize < 128 || bitSize > 256 || bitSize%32 != 0 {
+		return ErrInvalidEntropy
+	}
+	return nil
+}
+
+// readBits reads [n] bits of [b] starting at bit [offset], most significant
+// bit first
+func readBits(b []byte, offset, n int) int {
+	value := 0
+	for i := offset; i < offset+n; i++ {
+		value = value<<1 | int(b[i/8]>>(7-i%8)&1)
+	}
+	return value
+}
+
+// writeBits writes the [n] low bits of [value] to [b] starting at bit
+// [offset], most significant bit first
+func writeBits(b []byte, offset, n, value int) {
+	for i := 0; i < n; i++ {
+		if value>>(n-1-i)&1 == 1 {
+			bit := offset + i
+			b[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+}
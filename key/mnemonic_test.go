@@ -0,0 +1,155 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// bip39Vectors are test vectors of the BIP39 reference implementation,
+// with passphrase TREZOR
+var bip39Vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		entropy:  "00000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  "80808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		seed:     "d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		seed:     "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		entropy:  "000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent",
+		seed:     "035895f2f481b1b0f01fcf8c289c794660b289981a78f8106447707fdd9666ca06da5a9a565181599b79f53b844d8a71dd9f439c52a3d7b3e8a79c906ac845fa",
+	},
+	{
+		entropy:  "808080808080808080808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		seed:     "107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	for _, v := range bip39Vectors {
+		t.Run(v.entropy, func(t *testing.T) {
+			entropy, err := hex.DecodeString(v.entropy)
+			require.NoError(t, err)
+
+			mnemonic, err := EntropyToMnemonic(entropy)
+			require.NoError(t, err)
+			require.Equal(t, v.mnemonic, strings.Join(mnemonic, " "))
+
+			decoded, err := MnemonicToEntropy(mnemonic)
+			require.NoError(t, err)
+			require.Equal(t, entropy, decoded)
+
+			seed, err := MnemonicToSeed(mnemonic, "TREZOR")
+			require.NoError(t, err)
+			require.Equal(t, v.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestGenerateMnemonic_Valid(t *testing.T) {
+	for _, bitSize := range []int{128, 160, 192, 224, 256} {
+		mnemonic, err := GenerateMnemonic(bitSize)
+		require.NoError(t, err)
+		require.Len(t, mnemonic, bitSize*33/32/11)
+		require.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	for _, bitSize := range []int{0, 96, 129, 288} {
+		_, err := GenerateMnemonic(bitSize)
+		require.ErrorIs(t, err, ErrInvalidEntropy)
+	}
+
+	a, err := GenerateMnemonic(128)
+	require.NoError(t, err)
+	b, err := GenerateMnemonic(128)
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		name    string
+		phrase  string
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			phrase: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+		{
+			name:    "wrong checksum",
+			phrase:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			wantErr: ErrMnemonicChecksum,
+		},
+		{
+			name:    "unknown word",
+			phrase:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon lux",
+			wantErr: ErrInvalidMnemonic,
+		},
+		{
+			name:    "wrong length",
+			phrase:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			wantErr: ErrInvalidMnemonic,
+		},
+		{
+			name:    "empty",
+			phrase:  "",
+			wantErr: ErrInvalidMnemonic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mnemonic, err := ParseMnemonic(tt.phrase)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, mnemonic, 12)
+		})
+	}
+}
+
+func TestMnemonicToSeed_Passphrase(t *testing.T) {
+	mnemonic := strings.Fields(bip39Vectors[0].mnemonic)
+	withPassphrase, err := MnemonicToSeed(mnemonic, "TREZOR")
+	require.NoError(t, err)
+	withoutPassphrase, err := MnemonicToSeed(mnemonic, "")
+	require.NoError(t, err)
+	require.Len(t, withoutPassphrase, SeedLen)
+	require.NotEqual(t, withPassphrase, withoutPassphrase)
+
+	_, err = MnemonicToSeed(mnemonic[:11], "")
+	require.ErrorIs(t, err, ErrInvalidMnemonic)
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/luxfi/ids"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // lux addresses are ripemd160 hashes
+	"golang.org/x/crypto/sha3"
+)
+
+// GenerateSecp256k1 generates a new secp256k1 key
+func (m *Manager) GenerateSecp256k1() (*Key, error) {
+	privateKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secp256k1 key: %w", err)
+	}
+	key := newSecp256k1Key(privateKey)
+	m.keys[key.ID] = key
+	return key, nil
+}
+
+// ImportSecp256k1 imports an existing 32 byte secp256k1 private key
+func (m *Manager) ImportSecp256k1(privateKey []byte) (*Key, error) {
+	secpKey, err := parseSecp256k1(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key := newSecp256k1Key(secpKey)
+	m.keys[key.ID] = key
+	return key, nil
+}
+
+// newSecp256k1Key creates the key of a secp256k1 private key. Its ID is
+// the sha256 of its public key, and its address the P/X-chain address.
+func newSecp256k1Key(privateKey *secp256k1.PrivateKey) *Key {
+	pubKey := privateKey.PubKey().SerializeCompressed()
+	return &Key{
+		ID:                  ids.ID(sha256.Sum256(pubKey)),
+		Type:                "secp256k1",
+		Secp256k1PrivateKey: privateKey,
+		Secp256k1PublicKey:  pubKey,
+		Address:             secp256k1Address(pubKey),
+		Metadata:            make(map[string]string),
+	}
+}
+
+// parseSecp256k1 parses a 32 byte secp256k1 private key
+func parseSecp256k1(privateKey []byte) (*secp256k1.PrivateKey, error) {
+	if len(privateKey) != Secp256k1KeyLen {
+		return nil, fmt.Errorf("invalid secp256k1 private key length: %d", len(privateKey))
+	}
+	var scalar secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(privateKey); overflow || scalar.IsZero() {
+		return nil, fmt.Errorf("invalid secp256k1 private key: out of range")
+	}
+	return secp256k1.NewPrivateKey(&scalar), nil
+}
+
+// secp256k1Address returns the P/X-chain address of a compressed secp256k1
+// public key, the ripemd160 of its sha256
+func secp256k1Address(pubKey []byte) ids.ShortID {
+	sha := sha256.Sum256(pubKey)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	var addr ids.ShortID
+	copy(addr[:], hasher.Sum(nil))
+	return addr
+}
+
+// ethAddress returns the EIP-55 checksummed C-Chain address of a
+// compressed secp256k1 public key, the last 20 bytes of the keccak256 of
+// its uncompressed form
+func ethAddress(pubKey []byte) (string, error) {
+	parsed, err := secp256k1.ParsePubKey(pubKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	hash := keccak256(parsed.SerializeUncompressed()[1:])
+	addr := hex.EncodeToString(hash[12:])
+
+	checksum := keccak256([]byte(addr))
+	var b strings.Builder
+	b.WriteString("0x")
+	for i, c := range addr {
+		nibble := checksum[i/2] >> 4
+		if i%2 == 1 {
+			nibble = checksum[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			c -= 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+	return b.String(), nil
+}
+
+func keccak256(data []byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ewoqKey is the well known prefunded key of local networks
+const ewoqKey = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+
+func TestSecp256k1Addresses(t *testing.T) {
+	tests := []struct {
+		name       string
+		privateKey string
+		c          string
+		x          string
+	}{
+		{
+			name:       "ewoq",
+			privateKey: ewoqKey,
+			c:          "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC",
+			x:          "X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u",
+		},
+		{
+			name:       "one",
+			privateKey: "0000000000000000000000000000000000000000000000000000000000000001",
+			c:          "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		},
+		{
+			name:       "two",
+			privateKey: "0000000000000000000000000000000000000000000000000000000000000002",
+			c:          "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF",
+		},
+	}
+
+	manager, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := hex.DecodeString(tt.privateKey)
+			require.NoError(t, err)
+			key, err := manager.ImportSecp256k1(privateKey)
+			require.NoError(t, err)
+			require.Equal(t, "secp256k1", key.Type)
+			require.Equal(t, tt.c, key.C())
+			require.Equal(t, tt.privateKey, key.PrivKeyHex())
+			if tt.x == "" {
+				return
+			}
+			x, err := key.X("local")
+			require.NoError(t, err)
+			require.Equal(t, tt.x, x)
+			p, err := key.P("local")
+			require.NoError(t, err)
+			require.Equal(t, "P-"+strings.TrimPrefix(tt.x, "X-"), p)
+		})
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+	} {
+		privateKey, err := hex.DecodeString(invalid)
+		require.NoError(t, err)
+		_, err = manager.ImportSecp256k1(privateKey)
+		require.Error(t, err)
+	}
+}
+
+func TestManager_Secp256k1Persistence(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := newUnlockedManager(t, tmpDir)
+
+	key, err := manager.GenerateKey("secp256k1")
+	require.NoError(t, err)
+	require.Len(t, key.Secp256k1PublicKey, 33)
+	require.True(t, strings.HasPrefix(key.C(), "0x"))
+	exported, err := manager.ExportKey(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, key.PrivKeyHex(), exported)
+	require.NoError(t, manager.Save(key.ID))
+
+	// importing the same private key gives the same key
+	privateKey, err := hex.DecodeString(exported)
+	require.NoError(t, err)
+	imported, err := manager.ImportSecp256k1(privateKey)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, imported.ID)
+
+	reloaded, err := NewManager(tmpDir)
+	require.NoError(t, err)
+	loaded, err := reloaded.Get(key.ID)
+	require.NoError(t, err)
+	require.Equal(t, key.Address, loaded.Address)
+	require.Equal(t, key.C(), loaded.C())
+	require.Empty(t, loaded.PrivKeyHex())
+
+	require.NoError(t, reloaded.Unlock(testPassphrase))
+	require.Equal(t, exported, loaded.PrivKeyHex())
+
+	reloaded.Lock()
+	require.Nil(t, loaded.Secp256k1PrivateKey)
+	require.Equal(t, key.C(), loaded.C())
+}
+
+func TestDeriveSecp256k1Key_Import(t *testing.T) {
+	manager, err := NewManager(t.TempDir())
+	require.NoError(t, err)
+
+	mnemonic := strings.Fields(bip39Vectors[0].mnemonic)
+	privateKey, err := DeriveSecp256k1Key(mnemonic, 0)
+	require.NoError(t, err)
+	key, err := manager.ImportSecp256k1(privateKey)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(privateKey), key.PrivKeyHex())
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luxfi/ids"
+	"github.com/luxfi/sdk/crypto"
+)
+
+// KeyFormat is a format soft keys are loaded from
+type KeyFormat string
+
+const (
+	// FormatCB58 is a CB58 encoded secp256k1 key prefixed by PrivateKey-,
+	// as exported by the node
+	FormatCB58 KeyFormat = "cb58"
+	// FormatHex is a hex encoded key, 32 bytes for secp256k1 keys and 64
+	// bytes for expanded ed25519 keys, optionally prefixed by 0x
+	FormatHex KeyFormat = "hex"
+	// FormatSeed is a raw 32 byte ed25519 seed
+	FormatSeed KeyFormat = "seed"
+	// FormatExpanded is a raw 64 byte ed25519 key, seed followed by public key
+	FormatExpanded KeyFormat = "expanded"
+	// FormatJSON is a json object with the hex private key and its type
+	FormatJSON KeyFormat = "json"
+	// FormatKeystore is an encrypted Ethereum v3 style keystore
+	FormatKeystore KeyFormat = "keystore"
+	// FormatMnemonic is a BIP39 mnemonic, loaded as the secp256k1 key at
+	// the first Ledger path
+	FormatMnemonic KeyFormat = "mnemonic"
+)
+
+// formatMetadata is the metadata entry of the format a soft key was loaded from
+const formatMetadata = "format"
+
+// cb58KeyPrefix prefixes CB58 encoded private keys
+const cb58KeyPrefix = "PrivateKey-"
+
+var (
+	// ErrUnknownKeyFormat is returned when the format of a soft key is not
+	// recognized
+	ErrUnknownKeyFormat = errors.New("unknown key format")
+	// ErrPassphraseRequired is returned when loading a keystore without
+	// a passphrase
+	ErrPassphraseRequired = errors.New("keystore requires a passphrase")
+)
+
+// LoadSoft loads a soft key from a file path. See LoadSoftFromBytes for
+// the supported formats.
+func LoadSoft(networkID uint32, keyPath string) (*Key, error) {
+	return LoadSoftWithPassphrase(networkID, keyPath, "")
+}
+
+// LoadSoftWithPassphrase loads a soft key from a file path, decrypting
+// keystores with the passphrase
+func LoadSoftWithPassphrase(networkID uint32, keyPath string, passphrase string) (*Key, error) {
+	keyBytes, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return LoadSoftFromBytes(networkID, keyBytes, passphrase)
+}
+
+// LoadSoftFromBytes loads a soft key, detecting its format. Its format is
+// recorded in the "format" metadata of the key, and its type in Type.
+// Supported formats are:
+//   - CB58 with the PrivateKey- prefix, a secp256k1 key
+//   - hex, with or without 0x: 32 bytes for a secp256k1 key, 64 bytes for
+//     an expanded ed25519 key
+//   - raw bytes: a 32 byte ed25519 seed or a 64 byte expanded ed25519 key
+//   - json with the hex privateKey and its type
+//   - v3 keystore json, decrypted with the passphrase: a 32 byte secret is
+//     a secp256k1 key and a 64 byte secret an expanded ed25519 key
+//   - BIP39 mnemonic, the secp256k1 key at LedgerPath(0)
+func LoadSoftFromBytes(_ uint32, keyBytes []byte, passphrase string) (*Key, error) {
+	key, format, err := parseSoftKey(keyBytes, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if key.Metadata == nil {
+		key.Metadata = make(map[string]string)
+	}
+	key.Metadata[formatMetadata] = string(format)
+	return key, nil
+}
+
+// parseSoftKey detects the format of a soft key and parses it
+func parseSoftKey(keyBytes []byte, passphrase string) (*Key, KeyFormat, error) {
+	if !isText(keyBytes) {
+		switch len(keyBytes) {
+		case crypto.PrivateKeySeedLen:
+			key, err := softEd25519Key(keyBytes)
+			return key, FormatSeed, err
+		case crypto.PrivateKeyLen:
+			key, err := softEd25519Key(keyBytes)
+			return key, FormatExpanded, err
+		}
+		return nil, "", ErrUnknownKeyFormat
+	}
+	keyStr := strings.TrimSpace(string(keyBytes))
+
+	switch {
+	case strings.HasPrefix(keyStr, cb58KeyPrefix):
+		privateKey, err := ids.FromString(strings.TrimPrefix(keyStr, cb58KeyPrefix))
+		if err != nil {
+			return nil, FormatCB58, fmt.Errorf("failed to decode CB58 private key: %w", err)
+		}
+		key, err := softSecp256k1Key(privateKey[:])
+		return key, FormatCB58, err
+	case strings.HasPrefix(keyStr, "{"):
+		return parseJSONKey([]byte(keyStr), passphrase)
+	}
+
+	if privateKeyBytes, err := hex.DecodeString(strings.TrimPrefix(keyStr, "0x")); err == nil && keyStr != "" {
+		switch len(privateKeyBytes) {
+		case Secp256k1KeyLen:
+			key, err := softSecp256k1Key(privateKeyBytes)
+			return key, FormatHex, err
+		case crypto.PrivateKeyLen:
+			key, err := softEd25519Key(privateKeyBytes)
+			return key, FormatHex, err
+		default:
+			return nil, FormatHex, fmt.Errorf("invalid private key length: %d", len(privateKeyBytes))
+		}
+	}
+
+	if words := strings.Fields(keyStr); len(words) >= 12 {
+		mnemonic, err := ParseMnemonic(keyStr)
+		if err != nil {
+			return nil, FormatMnemonic, err
+		}
+		privateKey, err := DeriveSecp256k1Key(mnemonic, 0)
+		if err != nil {
+			return nil, FormatMnemonic, err
+		}
+		key, err := softSecp256k1Key(privateKey)
+		return key, FormatMnemonic, err
+	}
+	return nil, "", ErrUnknownKeyFormat
+}
+
+// isText returns true if the bytes are printable ascii. Raw keys are
+// binary, text formats are not.
+func isText(b []byte) bool {
+	for _, c := range b {
+		if (c < ' ' || c > '~') && c != '\n' && c != '\r' && c != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseJSONKey parses a keystore or a json key
+func parseJSONKey(keyBytes []byte, passphrase string) (*Key, KeyFormat, error) {
+	var encrypted EncryptedKey
+	if err := json.Unmarshal(keyBytes, &encrypted); err != nil {
+		return nil, FormatJSON, fmt.Errorf("failed to decode key json: %w", err)
+	}
+	if encrypted.Crypto.Cipher != "" {
+		if passphrase == "" {
+			return nil, FormatKeystore, ErrPassphraseRequired
+		}
+		secret, err := DecryptKey(&encrypted, passphrase)
+		if err != nil {
+			return nil, FormatKeystore, err
+		}
+		if len(secret) == Secp256k1KeyLen {
+			key, err := softSecp256k1Key(secret)
+			return key, FormatKeystore, err
+		}
+		key, err := softEd25519Key(secret)
+		return key, FormatKeystore, err
+	}
+
+	var keyData struct {
+		PrivateKey string `json:"privateKey"`
+		Type       string `json:"type"`
+	}
+	if err := json.Unmarshal(keyBytes, &keyData); err != nil || keyData.PrivateKey == "" {
+		return nil, FormatJSON, ErrUnknownKeyFormat
+	}
+	privateKeyBytes, err := hex.DecodeString(strings.TrimPrefix(keyData.PrivateKey, "0x"))
+	if err != nil {
+		return nil, FormatJSON, fmt.Errorf("failed to decode private key from JSON: %w", err)
+	}
+	if keyData.Type == "secp256k1" {
+		key, err := softSecp256k1Key(privateKeyBytes)
+		return key, FormatJSON, err
+	}
+	key, err := softEd25519Key(privateKeyBytes)
+	return key, FormatJSON, err
+}
+
+// softSecp256k1Key creates a soft key from a 32 byte secp256k1 private key
+func softSecp256k1Key(privateKey []byte) (*Key, error) {
+	secpKey, err := parseSecp256k1(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return newSecp256k1Key(secpKey), nil
+}
+
+// softEd25519Key creates a soft key from a 32 byte ed25519 seed or a 64
+// byte expanded ed25519 key
+func softEd25519Key(privateKeyBytes []byte) (*Key, error) {
+	var privateKey crypto.PrivateKey
+	switch len(privateKeyBytes) {
+	case crypto.PrivateKeySeedLen:
+		privateKey = crypto.PrivateKey(ed25519.NewKeyFromSeed(privateKeyBytes))
+	case crypto.PrivateKeyLen:
+		// the public key half must match the seed
+		expanded := ed25519.NewKeyFromSeed(privateKeyBytes[:crypto.PrivateKeySeedLen])
+		if !bytes.Equal(expanded, privateKeyBytes) {
+			return nil, crypto.ErrInvalidPrivateKey
+		}
+		privateKey = crypto.PrivateKey(expanded)
+	default:
+		return nil, fmt.Errorf("invalid private key length: %d", len(privateKeyBytes))
+	}
+
+	return &Key{
+		Type:       "ed25519",
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.PublicKey(),
+		Address:    generateAddress(privateKey.PublicKey()),
+		Metadata:   make(map[string]string),
+	}, nil
+}
@@ -0,0 +1,233 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ewoqCB58 is ewoqKey as exported by the node
+const ewoqCB58 = "PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN"
+
+// ethereumKeystores are the test vectors of the Web3 Secret Storage
+// definition, encrypting ethereumKeystoreKey with testpassword
+var ethereumKeystores = map[string]string{
+	"pbkdf2": `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {"c": 262144, "dklen": 32, "prf": "hmac-sha256", "salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`,
+	"scrypt": `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {"dklen": 32, "n": 262144, "p": 8, "r": 1, "salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`,
+}
+
+const ethereumKeystoreKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+func TestLoadSoftFromBytes(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	expanded := ed25519.NewKeyFromSeed(seed)
+	ed25519Hex := hex.EncodeToString(expanded)
+	// raw keys are binary
+	seed[0] = 0xff
+	rawExpanded := ed25519.NewKeyFromSeed(seed)
+
+	ownKeystore, err := EncryptKey(expanded, testPassphrase, LightKeystoreParams)
+	require.NoError(t, err)
+	ownKeystoreJSON, err := json.Marshal(ownKeystore)
+	require.NoError(t, err)
+
+	mnemonic := bip39Vectors[0].mnemonic
+	mnemonicKey, err := DeriveSecp256k1Key(strings.Fields(mnemonic), 0)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		keyBytes   []byte
+		passphrase string
+		format     KeyFormat
+		keyType    string
+		privateKey string
+	}{
+		{
+			name:       "cb58",
+			keyBytes:   []byte(ewoqCB58 + "\n"),
+			format:     FormatCB58,
+			keyType:    "secp256k1",
+			privateKey: ewoqKey,
+		},
+		{
+			name:       "hex secp256k1",
+			keyBytes:   []byte(ewoqKey),
+			format:     FormatHex,
+			keyType:    "secp256k1",
+			privateKey: ewoqKey,
+		},
+		{
+			name:       "0x hex secp256k1",
+			keyBytes:   []byte("0x" + ewoqKey + "\n"),
+			format:     FormatHex,
+			keyType:    "secp256k1",
+			privateKey: ewoqKey,
+		},
+		{
+			name:       "hex expanded ed25519",
+			keyBytes:   []byte(ed25519Hex),
+			format:     FormatHex,
+			keyType:    "ed25519",
+			privateKey: ed25519Hex[:64],
+		},
+		{
+			name:       "raw seed",
+			keyBytes:   seed,
+			format:     FormatSeed,
+			keyType:    "ed25519",
+			privateKey: hex.EncodeToString(seed),
+		},
+		{
+			name:       "raw expanded",
+			keyBytes:   rawExpanded,
+			format:     FormatExpanded,
+			keyType:    "ed25519",
+			privateKey: hex.EncodeToString(seed),
+		},
+		{
+			name:       "json secp256k1",
+			keyBytes:   []byte(`{"privateKey": "` + ewoqKey + `", "type": "secp256k1"}`),
+			format:     FormatJSON,
+			keyType:    "secp256k1",
+			privateKey: ewoqKey,
+		},
+		{
+			name:       "json ed25519",
+			keyBytes:   []byte(`{"privateKey": "` + ed25519Hex[:64] + `", "type": "ed25519"}`),
+			format:     FormatJSON,
+			keyType:    "ed25519",
+			privateKey: ed25519Hex[:64],
+		},
+		{
+			name:       "ethereum pbkdf2 keystore",
+			keyBytes:   []byte(ethereumKeystores["pbkdf2"]),
+			passphrase: "testpassword",
+			format:     FormatKeystore,
+			keyType:    "secp256k1",
+			privateKey: ethereumKeystoreKey,
+		},
+		{
+			name:       "ethereum scrypt keystore",
+			keyBytes:   []byte(ethereumKeystores["scrypt"]),
+			passphrase: "testpassword",
+			format:     FormatKeystore,
+			keyType:    "secp256k1",
+			privateKey: ethereumKeystoreKey,
+		},
+		{
+			name:       "ed25519 keystore",
+			keyBytes:   ownKeystoreJSON,
+			passphrase: testPassphrase,
+			format:     FormatKeystore,
+			keyType:    "ed25519",
+			privateKey: ed25519Hex[:64],
+		},
+		{
+			name:       "mnemonic",
+			keyBytes:   []byte(mnemonic + "\n"),
+			format:     FormatMnemonic,
+			keyType:    "secp256k1",
+			privateKey: hex.EncodeToString(mnemonicKey),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadSoftFromBytes(0, tt.keyBytes, tt.passphrase)
+			require.NoError(t, err)
+			require.Equal(t, tt.keyType, key.Type)
+			require.Equal(t, string(tt.format), key.Metadata["format"])
+			require.Equal(t, tt.privateKey, key.PrivKeyHex())
+		})
+	}
+}
+
+func TestLoadSoftFromBytes_Errors(t *testing.T) {
+	tamperedExpanded := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	tamperedExpanded[63] ^= 1
+
+	tests := []struct {
+		name       string
+		keyBytes   string
+		passphrase string
+		wantErr    error
+	}{
+		{name: "cb58 checksum", keyBytes: strings.TrimSuffix(ewoqCB58, "N") + "M"},
+		{name: "hex length", keyBytes: "abcd"},
+		{name: "hex expanded mismatch", keyBytes: hex.EncodeToString(tamperedExpanded)},
+		{name: "secp256k1 out of range", keyBytes: strings.Repeat("ff", 32)},
+		{name: "keystore without passphrase", keyBytes: ethereumKeystores["pbkdf2"], wantErr: ErrPassphraseRequired},
+		{name: "keystore wrong passphrase", keyBytes: ethereumKeystores["pbkdf2"], passphrase: "wrong", wantErr: ErrWrongPassphrase},
+		{name: "json", keyBytes: `{"address": "0x"}`, wantErr: ErrUnknownKeyFormat},
+		{name: "mnemonic checksum", keyBytes: strings.Repeat("abandon ", 12), wantErr: ErrMnemonicChecksum},
+		{name: "text", keyBytes: "not a key", wantErr: ErrUnknownKeyFormat},
+		{name: "empty", keyBytes: "", wantErr: ErrUnknownKeyFormat},
+		{name: "binary", keyBytes: "\x00\x01\x02", wantErr: ErrUnknownKeyFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSoftFromBytes(0, []byte(tt.keyBytes), tt.passphrase)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSoft(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "ewoq.pk")
+	require.NoError(t, os.WriteFile(keyPath, []byte(ewoqCB58), 0o600))
+
+	key, err := LoadSoft(0, keyPath)
+	require.NoError(t, err)
+	require.Equal(t, "secp256k1", key.Type)
+	require.Equal(t, "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", key.C())
+
+	keystorePath := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, os.WriteFile(keystorePath, []byte(ethereumKeystores["pbkdf2"]), 0o600))
+	_, err = LoadSoft(0, keystorePath)
+	require.ErrorIs(t, err, ErrPassphraseRequired)
+	key, err = LoadSoftWithPassphrase(0, keystorePath, "testpassword")
+	require.NoError(t, err)
+	require.Equal(t, ethereumKeystoreKey, key.PrivKeyHex())
+
+	_, err = LoadSoft(0, filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
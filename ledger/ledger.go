@@ -5,6 +5,7 @@ package ledger
 import (
 	"fmt"
 
+	"github.com/luxfi/sdk/key"
 	"github.com/luxfi/sdk/network"
 	"github.com/luxfi/sdk/utils"
 
@@ -54,7 +55,7 @@ func (dev *LedgerDevice) Version() (v *version.Semantic, err error) {
 
 // Address returns the address at the given index
 func (dev *LedgerDevice) Address(hrp string, index uint32) (ids.ShortID, error) {
-	path := fmt.Sprintf("m/44'/9000'/0'/0/%d", index)
+	path := key.LedgerPath(index)
 	resp, err := dev.device.GetPubKey(path, false, hrp, "P")
 	if err != nil {
 		return ids.ShortEmpty, err
@@ -93,7 +94,7 @@ func (dev *LedgerDevice) FindAddresses(addresses []string, maxIndex uint32) (map
 	indices := map[string]uint32{}
 	for index := uint32(0); index < maxIndex; index++ {
 		// Get the address from ledger at this index
-		path := fmt.Sprintf("m/44'/9000'/0'/0/%d", index)
+		path := key.LedgerPath(index)
 		resp, err := dev.device.GetPubKey(path, false, "lux", "P")
 		if err != nil {
 			return nil, err
@@ -132,7 +133,7 @@ func (dev *LedgerDevice) FindFunds(
 	}
 	for index := uint32(0); index < maxIndex; index++ {
 		// Get the address from ledger at this index
-		path := fmt.Sprintf("m/44'/9000'/0'/0/%d", index)
+		path := key.LedgerPath(index)
 		resp, err := dev.device.GetPubKey(path, false, "lux", "P")
 		if err != nil {
 			return []uint32{}, err
@@ -168,7 +169,7 @@ func (dev *LedgerDevice) FindFunds(
 func (dev *LedgerDevice) GetAddresses(indices []uint32, hrp string, chainID string) ([]string, error) {
 	addresses := make([]string, len(indices))
 	for i, index := range indices {
-		path := fmt.Sprintf("m/44'/9000'/0'/0/%d", index)
+		path := key.LedgerPath(index)
 		resp, err := dev.device.GetPubKey(path, false, hrp, chainID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get address at index %d: %w", index, err)
@@ -195,7 +196,7 @@ func (dev *LedgerDevice) SignHash(hash []byte, indices []uint32) ([][]byte, erro
 func (dev *LedgerDevice) Sign(hash []byte, indices []uint32) ([][]byte, error) {
 	signatures := make([][]byte, len(indices))
 	for i, index := range indices {
-		path := fmt.Sprintf("m/44'/9000'/0'/0/%d", index)
+		path := key.LedgerPath(index)
 		// For Lux ledger, we need signing paths and change paths
 		signingPaths := []string{path}
 		changePaths := []string{} // No change paths for simple signature
@@ -0,0 +1,223 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package metrics exports the metrics of the SDK in the OpenMetrics text
+// format, for Prometheus compatible scrapers.
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/luxfi/sdk/blockchain"
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/network"
+)
+
+// namespace prefixes the names of the exported metrics
+const namespace = "lux"
+
+// blockchainStatuses are the states of the blockchain status metric
+var blockchainStatuses = []blockchain.BlockchainStatus{
+	blockchain.StatusCreated,
+	blockchain.StatusDeploying,
+	blockchain.StatusDeployed,
+	blockchain.StatusRunning,
+	blockchain.StatusStopped,
+	blockchain.StatusError,
+}
+
+// networkStatuses are the states of the network status metric
+var networkStatuses = []network.NetworkStatus{
+	network.NetworkStatusCreating,
+	network.NetworkStatusRunning,
+	network.NetworkStatusStopped,
+	network.NetworkStatusError,
+}
+
+// nodeStatuses are the states of the node status metric
+var nodeStatuses = []network.NodeStatus{
+	network.NodeStatusBootstrapping,
+	network.NodeStatusHealthy,
+	network.NodeStatusUnhealthy,
+	network.NodeStatusStopped,
+	network.NodeStatusPaused,
+}
+
+// BlockchainSource lists blockchains and the metrics collected for them,
+// see blockchain.Builder
+type BlockchainSource interface {
+	ListBlockchains() []*blockchain.Blockchain
+	Metrics(blockchainID string) (*blockchain.Metrics, error)
+}
+
+// NetworkSource lists networks and the status of their nodes, see
+// network.NetworkManager
+type NetworkSource interface {
+	ListNetworks() []*network.Network
+}
+
+// Exporter is an http.Handler serving the metrics of blockchains, the
+// status of networks and nodes, and the stats of the rpc calls made by
+// evm clients in the OpenMetrics text format
+type Exporter struct {
+	blockchains BlockchainSource
+	networks    NetworkSource
+	rpcStats    func() []evm.RPCCallStats
+}
+
+// NewExporter creates an exporter of the metrics of the sources. Either
+// source may be nil.
+func NewExporter(blockchains BlockchainSource, networks NetworkSource) *Exporter {
+	return &Exporter{
+		blockchains: blockchains,
+		networks:    networks,
+		rpcStats:    evm.RPCStats,
+	}
+}
+
+// ServeHTTP writes the current metrics
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	if err := writeFamilies(&buf, e.families()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// families returns the current metric families
+func (e *Exporter) families() []*family {
+	var families []*family
+	if e.blockchains != nil {
+		families = append(families, blockchainFamilies(e.blockchains)...)
+	}
+	if e.networks != nil {
+		families = append(families, networkFamilies(e.networks.ListNetworks())...)
+	}
+	return append(families, rpcFamilies(e.rpcStats())...)
+}
+
+// blockchainFamilies returns the status and collected metrics of blockchains
+func blockchainFamilies(source BlockchainSource) []*family {
+	status := &family{name: namespace + "_blockchain_status", typ: typeGauge, help: "Status of the blockchain, 1 for the current status."}
+	blocks := &family{name: namespace + "_blockchain_blocks", typ: typeCounter, help: "Blocks produced since metrics collection started."}
+	txs := &family{name: namespace + "_blockchain_transactions", typ: typeCounter, help: "Transactions processed since metrics collection started, by result."}
+	tps := &family{name: namespace + "_blockchain_tps", typ: typeGauge, help: "Transactions per second over recent blocks."}
+	blockTime := &family{name: namespace + "_blockchain_block_time_seconds", typ: typeGauge, unit: "seconds", help: "Moving average of the time between blocks."}
+	lastBlock := &family{name: namespace + "_blockchain_last_block_timestamp_seconds", typ: typeGauge, unit: "seconds", help: "Timestamp of the last block."}
+	peers := &family{name: namespace + "_blockchain_peers", typ: typeGauge, help: "Peers of the node serving the blockchain RPC endpoint."}
+	latency := &family{name: namespace + "_blockchain_network_latency_seconds", typ: typeGauge, unit: "seconds", help: "Latency of the blockchain RPC endpoint."}
+	cpu := &family{name: namespace + "_blockchain_cpu_usage", typ: typeGauge, help: "CPU usage of the blockchain."}
+	memory := &family{name: namespace + "_blockchain_memory_usage_bytes", typ: typeGauge, unit: "bytes", help: "Memory usage of the blockchain."}
+	disk := &family{name: namespace + "_blockchain_disk_usage_bytes", typ: typeGauge, unit: "bytes", help: "Disk usage of the blockchain."}
+
+	chains := source.ListBlockchains()
+	sort.Slice(chains, func(i, j int) bool {
+		if chains[i].Name != chains[j].Name {
+			return chains[i].Name < chains[j].Name
+		}
+		return chains[i].ID < chains[j].ID
+	})
+	for _, chain := range chains {
+		labels := []label{
+			{"blockchain", chain.ID},
+			{"name", chain.Name},
+			{"type", string(chain.Type)},
+			{"vm", string(chain.VMType)},
+		}
+		for _, s := range blockchainStatuses {
+			status.add("", boolValue(chain.Status == s), append(labels, label{"status", string(s)})...)
+		}
+
+		m, err := source.Metrics(chain.ID)
+		if err != nil {
+			continue
+		}
+		snapshot := m.Snapshot()
+		blocks.add("_total", float64(snapshot.BlocksProduced), labels...)
+		txs.add("_total", float64(snapshot.TxProcessed), append(labels, label{"result", "success"})...)
+		txs.add("_total", float64(snapshot.TxFailed), append(labels, label{"result", "failure"})...)
+		tps.add("", snapshot.TPS, labels...)
+		blockTime.add("", snapshot.AverageBlockTime.Seconds(), labels...)
+		if !snapshot.LastBlockTime.IsZero() {
+			lastBlock.add("", float64(snapshot.LastBlockTime.UnixMilli())/1000, labels...)
+		}
+		peers.add("", float64(snapshot.PeersConnected), labels...)
+		latency.add("", snapshot.NetworkLatency.Seconds(), labels...)
+		cpu.add("", snapshot.CPUUsage, labels...)
+		memory.add("", float64(snapshot.MemoryUsage), labels...)
+		disk.add("", float64(snapshot.DiskUsage), labels...)
+	}
+	return []*family{status, blocks, txs, tps, blockTime, lastBlock, peers, latency, cpu, memory, disk}
+}
+
+// networkFamilies returns the status of networks and their nodes
+func networkFamilies(networks []*network.Network) []*family {
+	status := &family{name: namespace + "_network_status", typ: typeGauge, help: "Status of the network, 1 for the current status."}
+	nodes := &family{name: namespace + "_network_nodes", typ: typeGauge, help: "Nodes of the network."}
+	nodeStatus := &family{name: namespace + "_node_status", typ: typeGauge, help: "Status of the node, 1 for the current status."}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].ID < networks[j].ID
+	})
+	for _, n := range networks {
+		labels := []label{
+			{"network", n.ID},
+			{"name", n.Name},
+			{"type", string(n.Type)},
+		}
+		for _, s := range networkStatuses {
+			status.add("", boolValue(n.Status == s), append(labels, label{"status", string(s)})...)
+		}
+		nodes.add("", float64(len(n.Nodes)), labels...)
+
+		for _, node := range n.Nodes {
+			nodeLabels := []label{
+				{"network", n.ID},
+				{"node", node.ID},
+				{"node_id", node.NodeID},
+				{"type", string(node.Type)},
+			}
+			for _, s := range nodeStatuses {
+				nodeStatus.add("", boolValue(node.Status == s), append(nodeLabels, label{"status", string(s)})...)
+			}
+		}
+	}
+	return []*family{status, nodes, nodeStatus}
+}
+
+// rpcFamilies returns the counts and latencies of the rpc calls of evm clients
+func rpcFamilies(stats []evm.RPCCallStats) []*family {
+	requests := &family{name: namespace + "_evm_rpc_requests", typ: typeCounter, help: "RPC requests made by EVM clients, including retries."}
+	failures := &family{name: namespace + "_evm_rpc_failures", typ: typeCounter, help: "RPC requests made by EVM clients that failed."}
+	duration := &family{name: namespace + "_evm_rpc_request_duration_seconds", typ: typeHistogram, unit: "seconds", help: "Latency of the RPC requests made by EVM clients."}
+
+	for _, s := range stats {
+		labels := []label{{"method", s.Method}}
+		requests.add("_total", float64(s.Calls), labels...)
+		failures.add("_total", float64(s.Failures), labels...)
+		for i, bound := range evm.RPCLatencyBuckets {
+			var count uint64
+			if i < len(s.Buckets) {
+				count = s.Buckets[i]
+			}
+			duration.add("_bucket", float64(count), append(labels, label{"le", strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)})...)
+		}
+		duration.add("_bucket", float64(s.Calls), append(labels, label{"le", "+Inf"})...)
+		duration.add("_count", float64(s.Calls), labels...)
+		duration.add("_sum", s.Latency.Seconds(), labels...)
+	}
+	return []*family{requests, failures, duration}
+}
+
+// boolValue returns 1 for true and 0 for false
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
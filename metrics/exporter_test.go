@@ -0,0 +1,225 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxfi/sdk/blockchain"
+	"github.com/luxfi/sdk/evm"
+	"github.com/luxfi/sdk/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlockchains serves blockchains and their metrics to the exporter
+type fakeBlockchains struct {
+	blockchains []*blockchain.Blockchain
+	metrics     map[string]*blockchain.Metrics
+}
+
+func (f *fakeBlockchains) ListBlockchains() []*blockchain.Blockchain {
+	return f.blockchains
+}
+
+func (f *fakeBlockchains) Metrics(blockchainID string) (*blockchain.Metrics, error) {
+	m, ok := f.metrics[blockchainID]
+	if !ok {
+		return nil, blockchain.ErrNoMetrics
+	}
+	return m, nil
+}
+
+// fakeNetworks serves networks to the exporter
+type fakeNetworks []*network.Network
+
+func (f fakeNetworks) ListNetworks() []*network.Network {
+	return f
+}
+
+// scrape serves a request with the exporter
+func scrape(t *testing.T, exporter *Exporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	return rec.Body.String()
+}
+
+func TestExporter_Blockchains(t *testing.T) {
+	m := blockchain.NewMetrics()
+	m.RecordBlock(time.Unix(1700000000, 0))
+	m.RecordBlock(time.Unix(1700000002, 0))
+	m.RecordTransaction(true)
+	m.RecordTransaction(true)
+	m.RecordTransaction(false)
+	m.UpdateTPS(1.5)
+	m.UpdateNetwork(4, 25*time.Millisecond)
+	m.UpdateResources(0.25, 1024, 2048)
+
+	exporter := NewExporter(&fakeBlockchains{
+		blockchains: []*blockchain.Blockchain{
+			{ID: "b", Name: "pending", Type: blockchain.TypeL2, VMType: blockchain.VMTypeEVM, Status: blockchain.StatusCreated},
+			{ID: "a", Name: "mainchain", Type: blockchain.TypeL1, VMType: blockchain.VMTypeEVM, Status: blockchain.StatusRunning},
+		},
+		metrics: map[string]*blockchain.Metrics{"a": m},
+	}, nil)
+	exporter.rpcStats = func() []evm.RPCCallStats { return nil }
+
+	const a = `blockchain="a",name="mainchain",type="L1",vm="evm"`
+	const b = `blockchain="b",name="pending",type="L2",vm="evm"`
+	assert.Equal(t, `# TYPE lux_blockchain_status gauge
+# HELP lux_blockchain_status Status of the blockchain, 1 for the current status.
+lux_blockchain_status{`+a+`,status="created"} 0
+lux_blockchain_status{`+a+`,status="deploying"} 0
+lux_blockchain_status{`+a+`,status="deployed"} 0
+lux_blockchain_status{`+a+`,status="running"} 1
+lux_blockchain_status{`+a+`,status="stopped"} 0
+lux_blockchain_status{`+a+`,status="error"} 0
+lux_blockchain_status{`+b+`,status="created"} 1
+lux_blockchain_status{`+b+`,status="deploying"} 0
+lux_blockchain_status{`+b+`,status="deployed"} 0
+lux_blockchain_status{`+b+`,status="running"} 0
+lux_blockchain_status{`+b+`,status="stopped"} 0
+lux_blockchain_status{`+b+`,status="error"} 0
+# TYPE lux_blockchain_blocks counter
+# HELP lux_blockchain_blocks Blocks produced since metrics collection started.
+lux_blockchain_blocks_total{`+a+`} 2
+# TYPE lux_blockchain_transactions counter
+# HELP lux_blockchain_transactions Transactions processed since metrics collection started, by result.
+lux_blockchain_transactions_total{`+a+`,result="success"} 2
+lux_blockchain_transactions_total{`+a+`,result="failure"} 1
+# TYPE lux_blockchain_tps gauge
+# HELP lux_blockchain_tps Transactions per second over recent blocks.
+lux_blockchain_tps{`+a+`} 1.5
+# TYPE lux_blockchain_block_time_seconds gauge
+# UNIT lux_blockchain_block_time_seconds seconds
+# HELP lux_blockchain_block_time_seconds Moving average of the time between blocks.
+lux_blockchain_block_time_seconds{`+a+`} 2
+# TYPE lux_blockchain_last_block_timestamp_seconds gauge
+# UNIT lux_blockchain_last_block_timestamp_seconds seconds
+# HELP lux_blockchain_last_block_timestamp_seconds Timestamp of the last block.
+lux_blockchain_last_block_timestamp_seconds{`+a+`} 1700000002
+# TYPE lux_blockchain_peers gauge
+# HELP lux_blockchain_peers Peers of the node serving the blockchain RPC endpoint.
+lux_blockchain_peers{`+a+`} 4
+# TYPE lux_blockchain_network_latency_seconds gauge
+# UNIT lux_blockchain_network_latency_seconds seconds
+# HELP lux_blockchain_network_latency_seconds Latency of the blockchain RPC endpoint.
+lux_blockchain_network_latency_seconds{`+a+`} 0.025
+# TYPE lux_blockchain_cpu_usage gauge
+# HELP lux_blockchain_cpu_usage CPU usage of the blockchain.
+lux_blockchain_cpu_usage{`+a+`} 0.25
+# TYPE lux_blockchain_memory_usage_bytes gauge
+# UNIT lux_blockchain_memory_usage_bytes bytes
+# HELP lux_blockchain_memory_usage_bytes Memory usage of the blockchain.
+lux_blockchain_memory_usage_bytes{`+a+`} 1024
+# TYPE lux_blockchain_disk_usage_bytes gauge
+# UNIT lux_blockchain_disk_usage_bytes bytes
+# HELP lux_blockchain_disk_usage_bytes Disk usage of the blockchain.
+lux_blockchain_disk_usage_bytes{`+a+`} 2048
+# EOF
+`, scrape(t, exporter))
+}
+
+func TestExporter_Networks(t *testing.T) {
+	exporter := NewExporter(nil, fakeNetworks{
+		{
+			ID:     "net-1",
+			Name:   "local",
+			Type:   network.NetworkTypeLocal,
+			Status: network.NetworkStatusRunning,
+			Nodes: []*network.Node{
+				{ID: "node1", NodeID: "NodeID-1", Type: network.NodeTypeValidator, Status: network.NodeStatusHealthy},
+				{ID: "node2", NodeID: "NodeID-2", Type: network.NodeTypeValidator, Status: network.NodeStatusPaused},
+			},
+		},
+	})
+	exporter.rpcStats = func() []evm.RPCCallStats { return nil }
+
+	body := scrape(t, exporter)
+	for _, line := range []string{
+		`lux_network_status{network="net-1",name="local",type="local",status="running"} 1`,
+		`lux_network_status{network="net-1",name="local",type="local",status="stopped"} 0`,
+		`lux_network_nodes{network="net-1",name="local",type="local"} 2`,
+		`lux_node_status{network="net-1",node="node1",node_id="NodeID-1",type="validator",status="healthy"} 1`,
+		`lux_node_status{network="net-1",node="node2",node_id="NodeID-2",type="validator",status="healthy"} 0`,
+		`lux_node_status{network="net-1",node="node2",node_id="NodeID-2",type="validator",status="paused"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, "lux_blockchain_")
+	assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+}
+
+func TestExporter_RPC(t *testing.T) {
+	exporter := NewExporter(nil, nil)
+	buckets := make([]uint64, len(evm.RPCLatencyBuckets))
+	for i := range buckets {
+		if i >= 3 {
+			buckets[i] = 2
+		}
+		if i >= 5 {
+			buckets[i] = 3
+		}
+	}
+	exporter.rpcStats = func() []evm.RPCCallStats {
+		return []evm.RPCCallStats{{
+			Method:   "eth_blockNumber",
+			Calls:    3,
+			Failures: 1,
+			Latency:  400 * time.Millisecond,
+			Buckets:  buckets,
+		}}
+	}
+
+	body := scrape(t, exporter)
+	for _, line := range []string{
+		"# TYPE lux_evm_rpc_requests counter",
+		`lux_evm_rpc_requests_total{method="eth_blockNumber"} 3`,
+		`lux_evm_rpc_failures_total{method="eth_blockNumber"} 1`,
+		"# TYPE lux_evm_rpc_request_duration_seconds histogram",
+		"# UNIT lux_evm_rpc_request_duration_seconds seconds",
+		`lux_evm_rpc_request_duration_seconds_bucket{method="eth_blockNumber",le="0.025"} 0`,
+		`lux_evm_rpc_request_duration_seconds_bucket{method="eth_blockNumber",le="0.05"} 2`,
+		`lux_evm_rpc_request_duration_seconds_bucket{method="eth_blockNumber",le="0.25"} 3`,
+		`lux_evm_rpc_request_duration_seconds_bucket{method="eth_blockNumber",le="+Inf"} 3`,
+		`lux_evm_rpc_request_duration_seconds_count{method="eth_blockNumber"} 3`,
+		`lux_evm_rpc_request_duration_seconds_sum{method="eth_blockNumber"} 0.4`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+}
+
+func TestWriteFamilies(t *testing.T) {
+	f := &family{name: "lux_test", typ: typeGauge, help: "Help with a \\ and a\nnewline."}
+	f.add("", 1, label{"value", "a \"quoted\" \\ value\n"})
+	f.add("", 1e21)
+	empty := &family{name: "lux_empty", typ: typeGauge, help: "Not written."}
+
+	var b strings.Builder
+	require.NoError(t, writeFamilies(&b, []*family{f, empty}))
+	assert.Equal(t, `# TYPE lux_test gauge
+# HELP lux_test Help with a \\ and a\nnewline.
+lux_test{value="a \"quoted\" \\ value\n"} 1
+lux_test 1e+21
+# EOF
+`, b.String())
+
+	assert.ErrorIs(t, writeFamilies(failingWriter{}, []*family{f}), errWrite)
+}
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the OpenMetrics text format
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// metricType is the type of a metric family
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// label is a label of a sample
+type label struct {
+	name  string
+	value string
+}
+
+// sample is a value of a metric family. Its name is the family name
+// followed by the suffix.
+type sample struct {
+	suffix string
+	labels []label
+	value  float64
+}
+
+// family is a metric family, written with its metadata
+type family struct {
+	name    string
+	typ     metricType
+	unit    string
+	help    string
+	samples []sample
+}
+
+// add adds a sample to the family
+func (f *family) add(suffix string, value float64, labels ...label) {
+	f.samples = append(f.samples, sample{suffix: suffix, labels: labels, value: value})
+}
+
+// writeFamilies writes metric families in the OpenMetrics text format.
+// Families without samples are left out.
+func writeFamilies(w io.Writer, families []*family) error {
+	var b strings.Builder
+	for _, f := range families {
+		if len(f.samples) == 0 {
+			continue
+		}
+		b.WriteString("# TYPE " + f.name + " " + string(f.typ) + "\n")
+		if f.unit != "" {
+			b.WriteString("# UNIT " + f.name + " " + f.unit + "\n")
+		}
+		b.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+		for _, s := range f.samples {
+			b.WriteString(f.name + s.suffix)
+			if len(s.labels) > 0 {
+				b.WriteByte('{')
+				for i, l := range s.labels {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteString(l.name + `="` + escapeLabelValue(l.value) + `"`)
+				}
+				b.WriteByte('}')
+			}
+			b.WriteString(" " + formatValue(s.value) + "\n")
+		}
+	}
+	b.WriteString("# EOF\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelValueEscaper escapes label values
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escapes help texts
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+// formatValue formats a sample value
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	case value == math.Trunc(value) && math.Abs(value) < 1e15:
+		// counters and timestamps read better without exponents
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -69,6 +69,11 @@ func NewClient(config *Config, logger log.Logger) (*Client, error) {
 	}, nil
 }
 
+// Endpoint returns the address of the netrunner server
+func (c *Client) Endpoint() string {
+	return c.config.Endpoint
+}
+
 // Start starts a new network with the given configuration
 func (c *Client) Start(ctx context.Context, execPath string, opts ...netrunner.OpOption) (*rpcpb.StartResponse, error) {
 	c.logger.Info("starting network", "execPath", execPath)
@@ -161,6 +166,45 @@ func (c *Client) RestartNode(ctx context.Context, name string, opts ...netrunner
 	return nil
 }
 
+// SaveSnapshot saves the state of the running network under the given name.
+// The network is stopped once the snapshot is taken.
+func (c *Client) SaveSnapshot(ctx context.Context, snapshotName string) error {
+	c.logger.Info("saving snapshot", "name", snapshotName)
+	_, err := c.client.SaveSnapshot(ctx, snapshotName)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	c.logger.Info("snapshot saved", "name", snapshotName)
+	return nil
+}
+
+// LoadSnapshot starts a network from a previously saved snapshot
+func (c *Client) LoadSnapshot(ctx context.Context, snapshotName string, opts ...netrunner.OpOption) (*rpcpb.LoadSnapshotResponse, error) {
+	c.logger.Info("loading snapshot", "name", snapshotName)
+	resp, err := c.client.LoadSnapshot(ctx, snapshotName, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+	c.logger.Info("snapshot loaded", "clusterInfo", resp.ClusterInfo)
+	return resp, nil
+}
+
+// RemoveSnapshot removes a saved snapshot
+func (c *Client) RemoveSnapshot(ctx context.Context, snapshotName string) error {
+	c.logger.Info("removing snapshot", "name", snapshotName)
+	_, err := c.client.RemoveSnapshot(ctx, snapshotName)
+	if err != nil {
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	c.logger.Info("snapshot removed", "name", snapshotName)
+	return nil
+}
+
+// GetSnapshotNames returns the names of the saved snapshots
+func (c *Client) GetSnapshotNames(ctx context.Context) ([]string, error) {
+	return c.client.GetSnapshotNames(ctx)
+}
+
 // WaitForHealthy waits for all nodes in the network to be healthy
 func (c *Client) WaitForHealthy(ctx context.Context, timeout time.Duration) error {
 	c.logger.Info("waiting for network to be healthy", "timeout", timeout)
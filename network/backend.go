@@ -0,0 +1,399 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	netrunnersdk "github.com/luxfi/netrunner-sdk"
+	"github.com/luxfi/netrunner-sdk/rpcpb"
+	"github.com/luxfi/sdk/netrunner"
+)
+
+// processContextFile is the file a node writes its pid, API and staking
+// addresses to, inside its data dir
+const processContextFile = "process.json"
+
+// procDir is where the command lines of processes are read from
+var procDir = "/proc"
+
+var (
+	// ErrRemoteNode is returned when signaling a node of a netrunner server
+	// running on another host
+	ErrRemoteNode = errors.New("cannot signal nodes of a remote netrunner server")
+	// ErrRestartNodeConfig is returned when restarting a node with a new node
+	// config, which netrunner does not forward to the node
+	ErrRestartNodeConfig = errors.New("netrunner cannot restart a node with a new node config")
+	// ErrNotNodeProcess is returned when the pid of a node runs another binary
+	ErrNotNodeProcess = errors.New("pid is not a node process")
+)
+
+// Backend runs the nodes of a network on behalf of NetworkManager.
+// The default implementation talks to a netrunner server; tests can
+// substitute a local fake.
+type Backend interface {
+	// Start starts a new cluster and returns its description
+	Start(ctx context.Context, execPath string, opts StartOptions) (*rpcpb.ClusterInfo, error)
+	// Stop stops the running cluster
+	Stop(ctx context.Context) error
+	// Status returns the description of the running cluster
+	Status(ctx context.Context) (*rpcpb.ClusterInfo, error)
+	// URIs returns the API endpoints of the running nodes
+	URIs(ctx context.Context) ([]string, error)
+	// AddNode starts a new node and returns the updated cluster description
+	AddNode(ctx context.Context, name string, execPath string, nodeConfig string) (*rpcpb.ClusterInfo, error)
+	// RemoveNode stops and removes a node
+	RemoveNode(ctx context.Context, name string) error
+	// RestartNode restarts a node with the restart options
+	RestartNode(ctx context.Context, name string, opts RestartOptions) error
+	// PauseNode suspends a node process
+	PauseNode(ctx context.Context, name string) error
+	// ResumeNode resumes a paused node process
+	ResumeNode(ctx context.Context, name string) error
+	// KillNode kills a node process without a graceful shutdown
+	KillNode(ctx context.Context, name string) error
+	// Close releases the backend resources
+	Close() error
+}
+
+// SnapshotBackend is implemented by backends that snapshot the cluster
+// themselves. Other backends get snapshots by copying the node data dirs.
+type SnapshotBackend interface {
+	// SaveSnapshot saves the running cluster and stops it
+	SaveSnapshot(ctx context.Context, name string) error
+	// LoadSnapshot starts a cluster from a snapshot and returns its description
+	LoadSnapshot(ctx context.Context, name string, execPath string) (*rpcpb.ClusterInfo, error)
+	// RemoveSnapshot removes a snapshot
+	RemoveSnapshot(ctx context.Context, name string) error
+	// SnapshotNames returns the names of the saved snapshots
+	SnapshotNames(ctx context.Context) ([]string, error)
+}
+
+// StartOptions defines how a backend starts a cluster
+type StartOptions struct {
+	NumNodes         int
+	RootDataDir      string
+	GlobalNodeConfig string
+	// CustomNodeConfigs are per-node configs keyed by node name. When set,
+	// they also name the nodes.
+	CustomNodeConfigs map[string]string
+}
+
+// RestartOptions defines how a backend restarts a node
+type RestartOptions struct {
+	// NodeConfig is applied on top of the node configuration when set
+	NodeConfig string
+	// UpgradeConfigs are the upgrade.json contents of chains, keyed by
+	// blockchain ID, the node is restarted with
+	UpgradeConfigs map[string]string
+}
+
+// netrunnerBackend implements Backend on top of a netrunner client
+type netrunnerBackend struct {
+	client *netrunner.Client
+}
+
+// NewNetrunnerBackend returns a Backend driven by the given netrunner client
+func NewNetrunnerBackend(client *netrunner.Client) Backend {
+	return &netrunnerBackend{client: client}
+}
+
+func (b *netrunnerBackend) Start(ctx context.Context, execPath string, opts StartOptions) (*rpcpb.ClusterInfo, error) {
+	runnerOpts := []netrunnersdk.OpOption{
+		netrunnersdk.WithNumNodes(uint32(opts.NumNodes)),
+	}
+	if opts.RootDataDir != "" {
+		runnerOpts = append(runnerOpts, netrunnersdk.WithRootDataDir(opts.RootDataDir))
+	}
+	if opts.GlobalNodeConfig != "" {
+		runnerOpts = append(runnerOpts, netrunnersdk.WithGlobalNodeConfig(opts.GlobalNodeConfig))
+	}
+	if len(opts.CustomNodeConfigs) > 0 {
+		runnerOpts = append(runnerOpts, netrunnersdk.WithCustomNodeConfigs(opts.CustomNodeConfigs))
+	}
+	resp, err := b.client.Start(ctx, execPath, runnerOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetClusterInfo(), nil
+}
+
+func (b *netrunnerBackend) Stop(ctx context.Context) error {
+	return b.client.Stop(ctx)
+}
+
+func (b *netrunnerBackend) Status(ctx context.Context) (*rpcpb.ClusterInfo, error) {
+	resp, err := b.client.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetClusterInfo(), nil
+}
+
+func (b *netrunnerBackend) URIs(ctx context.Context) ([]string, error) {
+	return b.client.URIs(ctx)
+}
+
+func (b *netrunnerBackend) AddNode(ctx context.Context, name string, execPath string, nodeConfig string) (*rpcpb.ClusterInfo, error) {
+	var runnerOpts []netrunnersdk.OpOption
+	if nodeConfig != "" {
+		runnerOpts = append(runnerOpts, netrunnersdk.WithGlobalNodeConfig(nodeConfig))
+	}
+	resp, err := b.client.AddNode(ctx, name, execPath, runnerOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetClusterInfo(), nil
+}
+
+func (b *netrunnerBackend) RemoveNode(ctx context.Context, name string) error {
+	return b.client.RemoveNode(ctx, name)
+}
+
+// RestartNode restarts a node. The netrunner restart request only carries
+// the binary, whitelisted subnets and chain configs of the node, so a node
+// config is rejected rather than silently dropped.
+func (b *netrunnerBackend) RestartNode(ctx context.Context, name string, opts RestartOptions) error {
+	if opts.NodeConfig != "" {
+		return ErrRestartNodeConfig
+	}
+	var runnerOpts []netrunnersdk.OpOption
+	if len(opts.UpgradeConfigs) > 0 {
+		runnerOpts = append(runnerOpts, netrunnersdk.WithUpgradeConfigs(opts.UpgradeConfigs))
+	}
+	return b.client.RestartNode(ctx, name, runnerOpts...)
+}
+
+// PauseNode sends SIGSTOP to the node process. netrunner has no pause
+// RPC, so the process is found the same way as by KillNode.
+func (b *netrunnerBackend) PauseNode(ctx context.Context, name string) error {
+	process, err := b.nodeProcess(ctx, name)
+	if err != nil {
+		return err
+	}
+	return suspendProcess(process)
+}
+
+// ResumeNode sends SIGCONT to a node process paused by PauseNode
+func (b *netrunnerBackend) ResumeNode(ctx context.Context, name string) error {
+	process, err := b.nodeProcess(ctx, name)
+	if err != nil {
+		return err
+	}
+	return resumeProcess(process)
+}
+
+// KillNode sends SIGKILL to the node process. netrunner has no kill RPC,
+// so the pid is read from the process context file the node writes to its
+// data dir.
+func (b *netrunnerBackend) KillNode(ctx context.Context, name string) error {
+	process, err := b.nodeProcess(ctx, name)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
+
+// nodeProcess returns the process of a node, from the pid in its process
+// context file. This only works when netrunner runs on this host, and the
+// process is only returned if it still runs the node binary.
+func (b *netrunnerBackend) nodeProcess(ctx context.Context, name string) (*os.Process, error) {
+	if !isLoopback(b.client.Endpoint()) {
+		return nil, fmt.Errorf("%w: %s", ErrRemoteNode, b.client.Endpoint())
+	}
+
+	resp, err := b.client.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info := resp.GetClusterInfo()
+	nodeInfo, ok := info.GetNodeInfos()[name]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", name)
+	}
+
+	pid, err := readNodePID(filepath.Join(info.GetRootDataDir(), name))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNodeProcess(pid, nodeInfo.GetExecPath()); err != nil {
+		return nil, err
+	}
+	return os.FindProcess(pid)
+}
+
+func (b *netrunnerBackend) SaveSnapshot(ctx context.Context, name string) error {
+	return b.client.SaveSnapshot(ctx, name)
+}
+
+func (b *netrunnerBackend) LoadSnapshot(ctx context.Context, name string, execPath string) (*rpcpb.ClusterInfo, error) {
+	var runnerOpts []netrunnersdk.OpOption
+	if execPath != "" {
+		runnerOpts = append(runnerOpts, netrunnersdk.WithExecPath(execPath))
+	}
+	resp, err := b.client.LoadSnapshot(ctx, name, runnerOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetClusterInfo(), nil
+}
+
+func (b *netrunnerBackend) RemoveSnapshot(ctx context.Context, name string) error {
+	return b.client.RemoveSnapshot(ctx, name)
+}
+
+func (b *netrunnerBackend) SnapshotNames(ctx context.Context) ([]string, error) {
+	return b.client.GetSnapshotNames(ctx)
+}
+
+func (b *netrunnerBackend) Close() error {
+	return b.client.Close()
+}
+
+// isLoopback reports whether a netrunner endpoint is on this host
+func isLoopback(endpoint string) bool {
+	host := endpoint
+	if h, _, err := net.SplitHostPort(endpoint); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkNodeProcess checks that the process pid runs the node binary
+// execPath, so a pid reused by another process is never killed
+func checkNodeProcess(pid int, execPath string) error {
+	if execPath == "" {
+		return fmt.Errorf("unknown binary of node process %d", pid)
+	}
+	cmdline, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return fmt.Errorf("failed to read command line of node process %d: %w", pid, err)
+	}
+	argv0, _, _ := strings.Cut(string(cmdline), "\x00")
+	if !sameFile(argv0, execPath) {
+		return fmt.Errorf("%w: process %d runs %q, not %q", ErrNotNodeProcess, pid, argv0, execPath)
+	}
+	return nil
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
+
+// readNodePID returns the pid recorded in the process context file of a node data dir
+func readNodePID(dataDir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, processContextFile))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read node process context: %w", err)
+	}
+
+	var processContext struct {
+		PID int `json:"pid"`
+	}
+	if err := json.Unmarshal(data, &processContext); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal node process context: %w", err)
+	}
+	if processContext.PID <= 0 {
+		return 0, fmt.Errorf("invalid pid %d in node process context", processContext.PID)
+	}
+	return processContext.PID, nil
+}
+
+// nodesFromClusterInfo converts the netrunner cluster view into SDK nodes,
+// ordered by node name
+func nodesFromClusterInfo(info *rpcpb.ClusterInfo, stakeAmount uint64) []*Node {
+	if info == nil {
+		return nil
+	}
+
+	names := append([]string(nil), info.GetNodeNames()...)
+	if len(names) == 0 {
+		for name := range info.GetNodeInfos() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	nodes := make([]*Node, 0, len(names))
+	for _, name := range names {
+		nodeInfo, ok := info.GetNodeInfos()[name]
+		if !ok {
+			continue
+		}
+		nodes = append(nodes, nodeFromInfo(nodeInfo, info.GetHealthy(), stakeAmount))
+	}
+	return nodes
+}
+
+// nodeFromInfo converts a single netrunner node into an SDK node
+func nodeFromInfo(info *rpcpb.NodeInfo, healthy bool, stakeAmount uint64) *Node {
+	status := NodeStatusBootstrapping
+	if healthy {
+		status = NodeStatusHealthy
+	}
+	return &Node{
+		ID:          info.GetName(),
+		NodeID:      info.GetId(),
+		Type:        NodeTypeValidator,
+		Status:      status,
+		Endpoint:    info.GetUri(),
+		StakingPort: configStakingPort(info.GetConfig()),
+		StakeAmount: stakeAmount,
+	}
+}
+
+// configStakingPort returns the staking port set in a node config, or 0
+func configStakingPort(nodeConfig []byte) int {
+	var flags map[string]interface{}
+	if err := json.Unmarshal(nodeConfig, &flags); err != nil {
+		return 0
+	}
+	switch port := flags[stakingPortKey].(type) {
+	case float64:
+		return int(port)
+	case string:
+		n, _ := strconv.Atoi(port)
+		return n
+	default:
+		return 0
+	}
+}
+
+// chainIDsFromClusterInfo returns the IDs of the custom chains running on the cluster
+func chainIDsFromClusterInfo(info *rpcpb.ClusterInfo) []string {
+	if info == nil {
+		return nil
+	}
+	chainIDs := make([]string, 0, len(info.GetCustomChains()))
+	for chainID := range info.GetCustomChains() {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+	return chainIDs
+}
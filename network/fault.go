@@ -0,0 +1,254 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// peerListGossipFrequencyKey controls how often a node gossips its known peers
+	peerListGossipFrequencyKey = "network-peer-list-gossip-frequency"
+	// defaultPeerListGossipFrequency is the node default, restored when a partition is healed
+	defaultPeerListGossipFrequency = "1m"
+	// partitionedPeerListGossipFrequency keeps partitioned nodes from learning about each other
+	partitionedPeerListGossipFrequency = "87600h"
+)
+
+// PauseNode suspends the process of a node. The node keeps its state and
+// can be resumed with ResumeNode.
+func (nm *NetworkManager) PauseNode(ctx context.Context, networkID, nodeID string) error {
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, node, err := nm.runningNode(networkID, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if node.Status == NodeStatusPaused {
+		return nil
+	}
+
+	if err := nm.backend.PauseNode(ctx, nodeID); err != nil {
+		return fmt.Errorf("failed to pause node %s in network %s: %w", nodeID, networkID, err)
+	}
+
+	nm.setNodeStatus(node, NodeStatusPaused)
+	return nm.save(network)
+}
+
+// ResumeNode resumes a paused node, or restarts a killed one
+func (nm *NetworkManager) ResumeNode(ctx context.Context, networkID, nodeID string) error {
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, node, err := nm.runningNode(networkID, nodeID)
+	if err != nil {
+		return err
+	}
+
+	switch node.Status {
+	case NodeStatusPaused:
+		err = nm.backend.ResumeNode(ctx, nodeID)
+	case NodeStatusStopped:
+		err = nm.backend.RestartNode(ctx, nodeID, RestartOptions{})
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resume node %s in network %s: %w", nodeID, networkID, err)
+	}
+
+	nm.setNodeStatus(node, NodeStatusBootstrapping)
+	return nm.save(network)
+}
+
+// KillNode kills the process of a node with SIGKILL, without a graceful
+// shutdown. The node can be brought back with ResumeNode or RestartNode.
+func (nm *NetworkManager) KillNode(ctx context.Context, networkID, nodeID string) error {
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, node, err := nm.runningNode(networkID, nodeID)
+	if err != nil {
+		return err
+	}
+
+	if err := nm.backend.KillNode(ctx, nodeID); err != nil {
+		return fmt.Errorf("failed to kill node %s in network %s: %w", nodeID, networkID, err)
+	}
+
+	nm.setNodeStatus(node, NodeStatusStopped)
+	return nm.save(network)
+}
+
+// Partition splits the network into groups of nodes that only connect to
+// each other. Every node of the network must be in exactly one group. Each
+// node is restarted with its group as bootstrap peers and with peer list
+// gossip disabled. A single group holding every node heals the network.
+//
+// The partition is best-effort: nodes have no flag to refuse peers, so it
+// is not enforced at the connection level. Nodes are restarted one at a
+// time, and a node not yet restarted can still gossip peers of other
+// groups. Handshake peer lists and inbound connections are not blocked
+// either, so groups may reconnect. Use it to disturb connectivity, not to
+// test behavior that requires a strict split.
+//
+// netrunner cannot restart a node with a new config, so partitioning a
+// network run by netrunner fails with ErrRestartNodeConfig before any node
+// is restarted.
+func (nm *NetworkManager) Partition(ctx context.Context, networkID string, groups [][]string) error {
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
+	}
+
+	if network.Status != NetworkStatusRunning {
+		return fmt.Errorf("network %s is not running", networkID)
+	}
+
+	if nm.backend == nil {
+		return ErrNoBackend
+	}
+
+	partition, err := partitionNodes(network, groups)
+	if err != nil {
+		return err
+	}
+
+	gossipFrequency := defaultPeerListGossipFrequency
+	if len(partition) > 1 {
+		gossipFrequency = partitionedPeerListGossipFrequency
+	}
+
+	for _, group := range partition {
+		for _, node := range group {
+			nodeConfig, err := partitionConfig(node, group, gossipFrequency)
+			if err != nil {
+				return err
+			}
+			err = nm.backend.RestartNode(ctx, node.ID, RestartOptions{NodeConfig: nodeConfig})
+			if errors.Is(err, ErrRestartNodeConfig) {
+				return fmt.Errorf("failed to partition network %s: %w", networkID, err)
+			}
+			if err != nil {
+				nm.setNodeStatus(node, NodeStatusUnhealthy)
+				nm.saveOnError(network)
+				return fmt.Errorf("failed to partition node %s in network %s: %w", node.ID, networkID, err)
+			}
+			nm.setNodeStatus(node, NodeStatusBootstrapping)
+		}
+	}
+
+	return nm.save(network)
+}
+
+// runningNode returns a node of a running network, the caller must hold opMu
+func (nm *NetworkManager) runningNode(networkID, nodeID string) (*Network, *Node, error) {
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if network.Status != NetworkStatusRunning {
+		return nil, nil, fmt.Errorf("network %s is not running", networkID)
+	}
+
+	node := findNode(network.Nodes, nodeID)
+	if node == nil {
+		return nil, nil, fmt.Errorf("node %s not found in network %s", nodeID, networkID)
+	}
+
+	if nm.backend == nil {
+		return nil, nil, ErrNoBackend
+	}
+
+	return network, node, nil
+}
+
+// partitionNodes resolves the node names of the groups, checking that every
+// node of the network is in exactly one group
+func partitionNodes(network *Network, groups [][]string) ([][]*Node, error) {
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no partition groups given for network %s", network.ID)
+	}
+
+	seen := make(map[string]bool, len(network.Nodes))
+	partition := make([][]*Node, 0, len(groups))
+	for i, names := range groups {
+		if len(names) == 0 {
+			return nil, fmt.Errorf("partition group %d is empty", i)
+		}
+		group := make([]*Node, 0, len(names))
+		for _, name := range names {
+			node := findNode(network.Nodes, name)
+			if node == nil {
+				return nil, fmt.Errorf("node %s not found in network %s", name, network.ID)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("node %s is in more than one partition group", name)
+			}
+			seen[name] = true
+			group = append(group, node)
+		}
+		partition = append(partition, group)
+	}
+
+	for _, node := range network.Nodes {
+		if !seen[node.ID] {
+			return nil, fmt.Errorf("node %s is not in any partition group", node.ID)
+		}
+	}
+	return partition, nil
+}
+
+// partitionConfig builds the node config restricting a node to the peers of its group
+func partitionConfig(node *Node, group []*Node, gossipFrequency string) (string, error) {
+	var ips, ids []string
+	for _, peer := range group {
+		if peer.ID == node.ID {
+			continue
+		}
+		address, err := stakingAddress(peer)
+		if err != nil {
+			return "", err
+		}
+		ips = append(ips, address)
+		ids = append(ids, peer.NodeID)
+	}
+
+	nodeConfig, err := json.Marshal(map[string]string{
+		"bootstrap-ips":            strings.Join(ips, ","),
+		"bootstrap-ids":            strings.Join(ids, ","),
+		peerListGossipFrequencyKey: gossipFrequency,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(nodeConfig), nil
+}
+
+// stakingAddress returns the P2P address of a node, made of the host of its
+// endpoint and the staking port of its node config
+func stakingAddress(node *Node) (string, error) {
+	endpoint, err := url.Parse(node.Endpoint)
+	if err != nil || endpoint.Hostname() == "" {
+		return "", fmt.Errorf("invalid endpoint %q for node %s", node.Endpoint, node.ID)
+	}
+	if node.StakingPort == 0 {
+		return "", fmt.Errorf("unknown staking port of node %s", node.ID)
+	}
+	return net.JoinHostPort(endpoint.Hostname(), strconv.Itoa(node.StakingPort)), nil
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkManager_PauseResumeKill(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "fault-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 3,
+	})
+	require.NoError(t, err)
+
+	nodeStatus := func(nodeID string) NodeStatus {
+		t.Helper()
+		got, err := nm.GetNetwork(network.ID)
+		require.NoError(t, err)
+		node := findNode(got.Nodes, nodeID)
+		require.NotNil(t, node)
+		return node.Status
+	}
+
+	require.NoError(t, nm.PauseNode(ctx, network.ID, "node1"))
+	assert.True(t, backend.paused["node1"])
+	assert.Equal(t, NodeStatusPaused, nodeStatus("node1"))
+
+	// a paused node is not probed, so its status sticks
+	status, err := nm.GetNodeStatus(ctx, network.ID, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusPaused, *status)
+
+	require.NoError(t, nm.PauseNode(ctx, network.ID, "node1"))
+	require.NoError(t, nm.ResumeNode(ctx, network.ID, "node1"))
+	assert.False(t, backend.paused["node1"])
+	assert.Equal(t, NodeStatusBootstrapping, nodeStatus("node1"))
+
+	require.NoError(t, nm.KillNode(ctx, network.ID, "node2"))
+	assert.True(t, backend.killed["node2"])
+	assert.Equal(t, NodeStatusStopped, nodeStatus("node2"))
+
+	// resuming a killed node restarts it
+	require.NoError(t, nm.ResumeNode(ctx, network.ID, "node2"))
+	assert.False(t, backend.killed["node2"])
+	assert.Equal(t, 1, backend.restarts["node2"])
+	assert.Equal(t, NodeStatusBootstrapping, nodeStatus("node2"))
+
+	assert.ErrorContains(t, nm.PauseNode(ctx, network.ID, "node9"), "not found")
+	assert.ErrorContains(t, nm.KillNode(ctx, "unknown", "node1"), "not found")
+
+	require.NoError(t, nm.StopNetwork(ctx, network.ID))
+	assert.ErrorContains(t, nm.PauseNode(ctx, network.ID, "node3"), "not running")
+}
+
+func TestNetworkManager_Partition(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "partition-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 4,
+	})
+	require.NoError(t, err)
+
+	nodeConfig := func(name string) map[string]string {
+		t.Helper()
+		var cfg map[string]string
+		require.NoError(t, json.Unmarshal([]byte(backend.configs[name]), &cfg))
+		return cfg
+	}
+
+	require.NoError(t, nm.Partition(ctx, network.ID, [][]string{{"node1", "node2"}, {"node3", "node4"}}))
+	for _, name := range []string{"node1", "node2", "node3", "node4"} {
+		assert.Equal(t, 1, backend.restarts[name])
+	}
+
+	// the partition is best-effort, only the bootstrap peers and the peer
+	// list gossip of the nodes change
+	cfg := nodeConfig("node1")
+	assert.Len(t, cfg, 3)
+	assert.Equal(t, "127.0.0.1:9653", cfg["bootstrap-ips"])
+	assert.Equal(t, "NodeID-node2", cfg["bootstrap-ids"])
+	assert.Equal(t, partitionedPeerListGossipFrequency, cfg[peerListGossipFrequencyKey])
+
+	cfg = nodeConfig("node4")
+	assert.Equal(t, "127.0.0.1:9655", cfg["bootstrap-ips"])
+	assert.Equal(t, "NodeID-node3", cfg["bootstrap-ids"])
+
+	got, err := nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	for _, node := range got.Nodes {
+		assert.Equal(t, NodeStatusBootstrapping, node.Status)
+	}
+
+	// a single group heals the partition
+	require.NoError(t, nm.Partition(ctx, network.ID, [][]string{{"node1", "node2", "node3", "node4"}}))
+	cfg = nodeConfig("node1")
+	assert.Equal(t, "127.0.0.1:9653,127.0.0.1:9655,127.0.0.1:9657", cfg["bootstrap-ips"])
+	assert.Equal(t, "NodeID-node2,NodeID-node3,NodeID-node4", cfg["bootstrap-ids"])
+	assert.Equal(t, defaultPeerListGossipFrequency, cfg[peerListGossipFrequencyKey])
+
+	tests := []struct {
+		name   string
+		groups [][]string
+		errMsg string
+	}{
+		{"no groups", nil, "no partition groups"},
+		{"empty group", [][]string{{"node1", "node2", "node3", "node4"}, {}}, "is empty"},
+		{"unknown node", [][]string{{"node1", "node2"}, {"node3", "node9"}}, "node node9 not found"},
+		{"duplicate node", [][]string{{"node1", "node2"}, {"node2", "node3", "node4"}}, "more than one partition group"},
+		{"missing node", [][]string{{"node1", "node2"}, {"node3"}}, "node node4 is not in any partition group"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, nm.Partition(ctx, network.ID, tt.groups), tt.errMsg)
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for endpoint, want := range map[string]bool{
+		"localhost:8080":     true,
+		"127.0.0.1:8080":     true,
+		"[::1]:8080":         true,
+		"localhost":          true,
+		"10.0.0.5:8080":      false,
+		"netrunner.lan:8080": false,
+	} {
+		assert.Equal(t, want, isLoopback(endpoint), endpoint)
+	}
+}
+
+func TestCheckNodeProcess(t *testing.T) {
+	procDir = t.TempDir()
+	t.Cleanup(func() { procDir = "/proc" })
+
+	execPath := filepath.Join(t.TempDir(), "luxd")
+	require.NoError(t, os.WriteFile(execPath, nil, 0o700))
+	writeCmdline := func(pid int, args ...string) {
+		dir := filepath.Join(procDir, strconv.Itoa(pid))
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cmdline"), []byte(strings.Join(args, "\x00")+"\x00"), 0o600))
+	}
+
+	writeCmdline(100, execPath, "--config-file", "node.json")
+	require.NoError(t, checkNodeProcess(100, execPath))
+	assert.ErrorContains(t, checkNodeProcess(100, ""), "unknown binary")
+
+	// the pid was reused by another process
+	writeCmdline(101, "/usr/bin/sshd", "-D")
+	assert.ErrorIs(t, checkNodeProcess(101, execPath), ErrNotNodeProcess)
+
+	assert.ErrorContains(t, checkNodeProcess(102, execPath), "failed to read command line")
+}
+
+func TestReadNodePID(t *testing.T) {
+	dataDir := t.TempDir()
+
+	_, err := readNodePID(dataDir)
+	assert.ErrorContains(t, err, "failed to read node process context")
+
+	processContext := filepath.Join(dataDir, processContextFile)
+	require.NoError(t, os.WriteFile(processContext, []byte(`{"pid":0}`), 0o600))
+	_, err = readNodePID(dataDir)
+	assert.ErrorContains(t, err, "invalid pid")
+
+	data := []byte(`{"pid":4242,"uri":"http://127.0.0.1:9650","stakingAddress":"127.0.0.1:9651"}`)
+	require.NoError(t, os.WriteFile(processContext, data, 0o600))
+	pid, err := readNodePID(dataDir)
+	require.NoError(t, err)
+	assert.Equal(t, 4242, pid)
+}
@@ -0,0 +1,340 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	healthPath = "/ext/health"
+	infoPath   = "/ext/info"
+
+	// bootstrapChain is the chain whose bootstrap state decides whether a node is bootstrapped
+	bootstrapChain = "P"
+
+	// DefaultHealthCheckInterval is how often WatchNetwork polls the nodes
+	DefaultHealthCheckInterval = 5 * time.Second
+	// nodeRequestTimeout bounds a single health or info request to a node
+	nodeRequestTimeout = 5 * time.Second
+	// watchBufferSize is the number of status changes buffered for a watcher
+	watchBufferSize = 64
+)
+
+// NodeStatusChange describes a status transition of a node. The first
+// change reported for a node has an empty Previous status.
+type NodeStatusChange struct {
+	NetworkID string
+	NodeID    string
+	Previous  NodeStatus
+	Status    NodeStatus
+	Time      time.Time
+}
+
+// nodeProber queries the health and bootstrap state of nodes over their HTTP API
+type nodeProber struct {
+	client *http.Client
+}
+
+func newNodeProber() *nodeProber {
+	return &nodeProber{
+		client: &http.Client{Timeout: nodeRequestTimeout},
+	}
+}
+
+// status maps the node API responses onto a NodeStatus. A node that does
+// not accept connections is reported as stopped.
+func (p *nodeProber) status(ctx context.Context, endpoint string) NodeStatus {
+	bootstrapped, err := p.isBootstrapped(ctx, endpoint)
+	if err != nil {
+		return statusFromError(err)
+	}
+	if !bootstrapped {
+		return NodeStatusBootstrapping
+	}
+
+	healthy, err := p.healthy(ctx, endpoint)
+	if err != nil {
+		return statusFromError(err)
+	}
+	if !healthy {
+		return NodeStatusUnhealthy
+	}
+	return NodeStatusHealthy
+}
+
+// isBootstrapped calls info.isBootstrapped on the node
+func (p *nodeProber) isBootstrapped(ctx context.Context, endpoint string) (bool, error) {
+	request, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "info.isBootstrapped",
+		"params":  map[string]string{"chain": bootstrapChain},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, nodeURL(endpoint, infoPath), bytes.NewReader(request))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("info.isBootstrapped returned status %d", resp.StatusCode)
+	}
+
+	var reply struct {
+		Result *struct {
+			IsBootstrapped bool `json:"isBootstrapped"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return false, fmt.Errorf("failed to decode info.isBootstrapped reply: %w", err)
+	}
+	if reply.Error != nil {
+		return false, fmt.Errorf("info.isBootstrapped failed: %s", reply.Error.Message)
+	}
+	if reply.Result == nil {
+		return false, errors.New("info.isBootstrapped returned no result")
+	}
+	return reply.Result.IsBootstrapped, nil
+}
+
+// healthy queries the node health endpoint. The node answers 200 when
+// healthy and 503 otherwise, with a JSON report in both cases.
+func (p *nodeProber) healthy(ctx context.Context, endpoint string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nodeURL(endpoint, healthPath), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusServiceUnavailable:
+	default:
+		return false, fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+
+	var report struct {
+		Healthy *bool `json:"healthy"`
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err == nil && json.Unmarshal(body, &report) == nil && report.Healthy != nil {
+		return *report.Healthy, nil
+	}
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// nodeURL joins a node endpoint and an API path
+func nodeURL(endpoint, path string) string {
+	return strings.TrimSuffix(endpoint, "/") + path
+}
+
+// statusFromError maps a failed node request onto a NodeStatus
+func statusFromError(err error) NodeStatus {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return NodeStatusStopped
+	}
+	return NodeStatusUnhealthy
+}
+
+// GetNodeStatus queries the node API and returns the current status of a node
+func (nm *NetworkManager) GetNodeStatus(ctx context.Context, networkID, nodeID string) (*NodeStatus, error) {
+	network, err := nm.GetNetwork(networkID)
+	if err != nil {
+		return nil, err
+	}
+	if findNode(network.Nodes, nodeID) == nil {
+		return nil, fmt.Errorf("node %s not found in network %s", nodeID, networkID)
+	}
+
+	statuses, err := nm.refreshNodeStatus(ctx, network, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	status := statuses[nodeID]
+	return &status, nil
+}
+
+// WatchNetwork polls the nodes of a network and streams their status
+// changes. The current status of every node is sent first. The channel is
+// closed when ctx is done or the network is deleted.
+func (nm *NetworkManager) WatchNetwork(ctx context.Context, networkID string) (<-chan NodeStatusChange, error) {
+	if _, err := nm.lookup(networkID); err != nil {
+		return nil, err
+	}
+
+	changes := make(chan NodeStatusChange, watchBufferSize)
+	go nm.watch(ctx, networkID, changes)
+	return changes, nil
+}
+
+// watch runs the polling loop of WatchNetwork
+func (nm *NetworkManager) watch(ctx context.Context, networkID string, changes chan<- NodeStatusChange) {
+	defer close(changes)
+
+	interval := DefaultHealthCheckInterval
+	if nm.config != nil && nm.config.HealthCheckInterval > 0 {
+		interval = nm.config.HealthCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := make(map[string]NodeStatus)
+	for {
+		network, err := nm.GetNetwork(networkID)
+		if err != nil {
+			nm.logger.Debug("stopped watching network", "network", networkID, "error", err)
+			return
+		}
+
+		statuses, err := nm.refreshNodeStatus(ctx, network)
+		if err != nil {
+			return
+		}
+
+		now := time.Now()
+		for _, node := range network.Nodes {
+			status, ok := statuses[node.ID]
+			if !ok || last[node.ID] == status {
+				continue
+			}
+			change := NodeStatusChange{
+				NetworkID: networkID,
+				NodeID:    node.ID,
+				Previous:  last[node.ID],
+				Status:    status,
+				Time:      now,
+			}
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return
+			}
+			last[node.ID] = status
+		}
+		for nodeID := range last {
+			if _, ok := statuses[nodeID]; !ok {
+				delete(last, nodeID)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refreshNodeStatus probes the given nodes of a network snapshot, or all of
+// them when none are given, and records the results in the manager
+func (nm *NetworkManager) refreshNodeStatus(ctx context.Context, network *Network, nodeIDs ...string) (map[string]NodeStatus, error) {
+	nodes := network.Nodes
+	if len(nodeIDs) > 0 {
+		nodes = make([]*Node, 0, len(nodeIDs))
+		for _, nodeID := range nodeIDs {
+			if node := findNode(network.Nodes, nodeID); node != nil {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	statuses := make(map[string]NodeStatus, len(nodes))
+	if network.Status == NetworkStatusStopped {
+		for _, node := range nodes {
+			statuses[node.ID] = NodeStatusStopped
+		}
+		return statuses, nil
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node *Node) {
+			defer wg.Done()
+			var status NodeStatus
+			switch {
+			case node.Status == NodeStatusPaused:
+				// a paused process does not answer, keep the status it was given
+				status = NodeStatusPaused
+			case node.Endpoint == "":
+				status = NodeStatusStopped
+			default:
+				status = nm.prober.status(ctx, node.Endpoint)
+			}
+			mu.Lock()
+			statuses[node.ID] = status
+			mu.Unlock()
+		}(node)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nm.recordNodeStatus(network.ID, statuses)
+	return statuses, nil
+}
+
+// recordNodeStatus stores polled node statuses and persists the network if
+// any of them changed
+func (nm *NetworkManager) recordNodeStatus(networkID string, statuses map[string]NodeStatus) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	// the network may have been stopped or deleted while its nodes were polled
+	network, ok := nm.networks[networkID]
+	if !ok || network.Status == NetworkStatusStopped {
+		return
+	}
+
+	changed := false
+	for _, node := range network.Nodes {
+		// pausing and resuming is tracked by the manager, not by polling
+		if node.Status == NodeStatusPaused {
+			continue
+		}
+		if status, ok := statuses[node.ID]; ok && status != NodeStatusPaused && node.Status != status {
+			node.Status = status
+			changed = true
+		}
+	}
+	if changed && nm.registry != nil {
+		if err := nm.registry.save(network); err != nil {
+			nm.logger.Warn("failed to save network", "network", networkID, "error", err)
+		}
+	}
+}
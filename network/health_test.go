@@ -0,0 +1,238 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNode serves the health and info APIs of a node
+type fakeNode struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	bootstrapped bool
+	healthy      bool
+	infoErr      bool
+}
+
+func newFakeNode(t *testing.T, bootstrapped, healthy bool) *fakeNode {
+	t.Helper()
+	node := &fakeNode{bootstrapped: bootstrapped, healthy: healthy}
+	node.Server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func (n *fakeNode) set(bootstrapped, healthy bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.bootstrapped = bootstrapped
+	n.healthy = healthy
+}
+
+func (n *fakeNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	switch r.URL.Path {
+	case infoPath:
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "info.isBootstrapped" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if n.infoErr {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"error":   map[string]interface{}{"code": -32000, "message": "chain not found"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  map[string]bool{"isBootstrapped": n.bootstrapped},
+		})
+	case healthPath:
+		if !n.healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]bool{"healthy": n.healthy})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestNodeProber_Status(t *testing.T) {
+	ctx := context.Background()
+	prober := newNodeProber()
+
+	stopped := newFakeNode(t, true, true)
+	stopped.Close()
+
+	infoErr := newFakeNode(t, true, true)
+	infoErr.infoErr = true
+
+	tests := []struct {
+		name     string
+		endpoint string
+		want     NodeStatus
+	}{
+		{"healthy", newFakeNode(t, true, true).URL, NodeStatusHealthy},
+		{"trailing slash", newFakeNode(t, true, true).URL + "/", NodeStatusHealthy},
+		{"bootstrapping", newFakeNode(t, false, false).URL, NodeStatusBootstrapping},
+		{"unhealthy", newFakeNode(t, true, false).URL, NodeStatusUnhealthy},
+		{"info error", infoErr.URL, NodeStatusUnhealthy},
+		{"stopped", stopped.URL, NodeStatusStopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, prober.status(ctx, tt.endpoint))
+		})
+	}
+}
+
+func TestNetworkManager_GetNodeStatusTransitions(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+	node := newFakeNode(t, false, false)
+	backend.endpoints["node1"] = node.URL
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "status-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+	})
+	require.NoError(t, err)
+
+	status, err := nm.GetNodeStatus(ctx, network.ID, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusBootstrapping, *status)
+
+	node.set(true, false)
+	status, err = nm.GetNodeStatus(ctx, network.ID, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusUnhealthy, *status)
+
+	stored, err := nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusUnhealthy, stored.Nodes[0].Status)
+
+	_, err = nm.GetNodeStatus(ctx, network.ID, "node9")
+	assert.ErrorContains(t, err, "not found")
+
+	require.NoError(t, nm.StopNetwork(ctx, network.ID))
+	status, err = nm.GetNodeStatus(ctx, network.ID, "node1")
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusStopped, *status)
+}
+
+func TestNetworkManager_WatchNetwork(t *testing.T) {
+	backend := newFakeBackend()
+	cfg := &config.NetworkConfig{HealthCheckInterval: 10 * time.Millisecond}
+	nm, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+	require.NoError(t, err)
+
+	node1 := newFakeNode(t, false, false)
+	node2 := newFakeNode(t, true, true)
+	backend.endpoints["node1"] = node1.URL
+	backend.endpoints["node2"] = node2.URL
+
+	network, err := nm.CreateNetwork(context.Background(), &NetworkParams{
+		Name:     "watch-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+	})
+	require.NoError(t, err)
+
+	_, err = nm.WatchNetwork(context.Background(), "unknown")
+	assert.ErrorContains(t, err, "not found")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := nm.WatchNetwork(ctx, network.ID)
+	require.NoError(t, err)
+
+	next := func() NodeStatusChange {
+		t.Helper()
+		select {
+		case change, ok := <-changes:
+			require.True(t, ok, "watch channel closed")
+			return change
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for a status change")
+			return NodeStatusChange{}
+		}
+	}
+
+	initial := map[string]NodeStatusChange{}
+	for i := 0; i < 2; i++ {
+		change := next()
+		initial[change.NodeID] = change
+	}
+	assert.Equal(t, NodeStatusChange{NetworkID: network.ID, NodeID: "node1", Status: NodeStatusBootstrapping, Time: initial["node1"].Time}, initial["node1"])
+	assert.Equal(t, NodeStatus(""), initial["node2"].Previous)
+	assert.Equal(t, NodeStatusHealthy, initial["node2"].Status)
+
+	node1.set(true, true)
+	change := next()
+	assert.Equal(t, "node1", change.NodeID)
+	assert.Equal(t, NodeStatusBootstrapping, change.Previous)
+	assert.Equal(t, NodeStatusHealthy, change.Status)
+
+	node2.Close()
+	change = next()
+	assert.Equal(t, "node2", change.NodeID)
+	assert.Equal(t, NodeStatusHealthy, change.Previous)
+	assert.Equal(t, NodeStatusStopped, change.Status)
+
+	t.Run("closed on delete", func(t *testing.T) {
+		deleted, err := nm.WatchNetwork(context.Background(), network.ID)
+		require.NoError(t, err)
+		require.NoError(t, nm.DeleteNetwork(context.Background(), network.ID))
+
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case _, ok := <-deleted:
+				if !ok {
+					return
+				}
+			case <-timeout:
+				require.FailNow(t, "watch channel not closed after delete")
+			}
+		}
+	})
+
+	t.Run("closed on cancel", func(t *testing.T) {
+		cancel()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case _, ok := <-changes:
+				if !ok {
+					return
+				}
+			case <-timeout:
+				require.FailNow(t, "watch channel not closed after cancel")
+			}
+		}
+	})
+}
@@ -5,43 +5,78 @@ package network
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/luxfi/log"
+	"github.com/luxfi/netrunner-sdk/rpcpb"
 	"github.com/luxfi/sdk/config"
+	"github.com/luxfi/sdk/constants"
+	"github.com/luxfi/sdk/events"
 	"github.com/luxfi/sdk/netrunner"
 )
 
-// NetworkManager handles all network operations using netrunner
+// NetworkManager handles all network operations using netrunner.
+// It is safe for concurrent use.
 type NetworkManager struct {
-	config          *config.NetworkConfig
-	logger          log.Logger
-	networks        map[string]*Network
-	netrunnerClient *netrunner.Client
+	config   *config.NetworkConfig
+	logger   log.Logger
+	backend  Backend
+	registry *registry
+	prober   *nodeProber
+	ports    *portAllocator
+	events   *events.Bus
+
+	// opMu serializes lifecycle operations, netrunner drives a single cluster
+	opMu sync.Mutex
+	// mu guards networks and the contents of the stored networks. Node
+	// statuses are also refreshed by health polling, under mu alone.
+	mu       sync.RWMutex
+	networks map[string]*Network
 }
 
 // Network represents a managed Lux network
 type Network struct {
-	ID        string
-	Name      string
-	Type      NetworkType
-	Status    NetworkStatus
-	Nodes     []*Node
-	ChainIDs  []string
-	CreatedAt time.Time
-	// netrunner   *netrunner.Network // TODO: Add netrunner integration
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Type      NetworkType   `json:"type"`
+	Status    NetworkStatus `json:"status"`
+	Nodes     []*Node       `json:"nodes"`
+	ChainIDs  []string      `json:"chainIds,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+
+	// BinaryPath is the node binary netrunner runs for this network
+	BinaryPath string `json:"binaryPath,omitempty"`
+	// RootDataDir is the netrunner root data directory of the network
+	RootDataDir string `json:"rootDataDir,omitempty"`
+	// ManagedDataDir is set when RootDataDir was created by the manager,
+	// which removes it when the network is deleted
+	ManagedDataDir bool `json:"managedDataDir,omitempty"`
+	// Snapshots are the netrunner snapshot names taken of this network
+	Snapshots []string `json:"snapshots,omitempty"`
+	// Ports is the range ports of nodes added to the network are taken from
+	Ports *PortRange `json:"ports,omitempty"`
+	// EnableStaking and EnableMonitoring are the node options the network
+	// was created with, reapplied when it is started again
+	EnableStaking    bool `json:"enableStaking,omitempty"`
+	EnableMonitoring bool `json:"enableMonitoring,omitempty"`
 }
 
 // Node represents a node in the network
 type Node struct {
-	ID          string
-	NodeID      string
-	Type        NodeType
-	Status      NodeStatus
-	Endpoint    string
-	StakeAmount uint64
-	PublicKey   string
+	ID          string     `json:"id"`
+	NodeID      string     `json:"nodeId"`
+	Type        NodeType   `json:"type"`
+	Status      NodeStatus `json:"status"`
+	Endpoint    string     `json:"endpoint"`
+	StakingPort int        `json:"stakingPort,omitempty"`
+	StakeAmount uint64     `json:"stakeAmount"`
+	PublicKey   string     `json:"publicKey,omitempty"`
 }
 
 // NetworkType defines the type of network
@@ -82,27 +117,210 @@ const (
 	NodeStatusHealthy       NodeStatus = "healthy"
 	NodeStatusUnhealthy     NodeStatus = "unhealthy"
 	NodeStatusStopped       NodeStatus = "stopped"
+	NodeStatusPaused        NodeStatus = "paused"
 )
 
-// NewNetworkManager creates a new network manager
+// ErrNoBackend is returned when a lifecycle operation needs a backend but
+// the manager was created without one
+var ErrNoBackend = errors.New("no netrunner backend configured")
+
+// NewNetworkManager creates a new network manager. If the config has a
+// NetrunnerEndpoint, a netrunner client is connected and used as backend,
+// otherwise lifecycle operations fail with ErrNoBackend. If the config has a
+// RegistryDir, previously created networks are reloaded.
 func NewNetworkManager(config *config.NetworkConfig, logger log.Logger) (*NetworkManager, error) {
-	// Initialize network manager without netrunner client for now
-	// Netrunner integration will be added when the API is stabilized
+	var backend Backend
+	if config != nil && config.NetrunnerEndpoint != "" {
+		client, err := netrunner.NewClient(&netrunner.Config{
+			Endpoint:    config.NetrunnerEndpoint,
+			DialTimeout: netrunner.DefaultConfig().DialTimeout,
+			LogLevel:    config.LogLevel,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to netrunner at %s: %w", config.NetrunnerEndpoint, err)
+		}
+		backend = NewNetrunnerBackend(client)
+	}
+
+	return NewNetworkManagerWithBackend(config, backend, logger)
+}
+
+// NewNetworkManagerWithBackend creates a network manager on top of the given backend
+func NewNetworkManagerWithBackend(config *config.NetworkConfig, backend Backend, logger log.Logger) (*NetworkManager, error) {
+	if logger == nil {
+		logger = log.NewNoOpLogger()
+	}
 	manager := &NetworkManager{
 		config:   config,
 		logger:   logger,
 		networks: make(map[string]*Network),
+		backend:  backend,
+		prober:   newNodeProber(),
+		ports:    newPortAllocator(),
 	}
 
-	// If netrunner endpoint is configured, log that it will be used in future
-	if config != nil && config.NetrunnerEndpoint != "" {
-		logger.Info("netrunner endpoint configured but not yet integrated",
-			"endpoint", config.NetrunnerEndpoint)
+	if config != nil && config.RegistryDir != "" {
+		reg, err := newRegistry(config.RegistryDir, logger)
+		if err != nil {
+			return nil, err
+		}
+		manager.registry = reg
+		if err := manager.reload(); err != nil {
+			return nil, err
+		}
 	}
 
 	return manager, nil
 }
 
+// reload loads the persisted networks and reconciles them with the backend
+func (nm *NetworkManager) reload() error {
+	networks, err := nm.registry.load()
+	if err != nil {
+		return err
+	}
+
+	var cluster *rpcpb.ClusterInfo
+	if nm.backend != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.ANRRequestTimeout)
+		cluster, err = nm.backend.Status(ctx)
+		cancel()
+		if err != nil {
+			nm.logger.Debug("no running netrunner cluster", "error", err)
+			cluster = nil
+		}
+	}
+
+	for _, network := range networks {
+		nm.networks[network.ID] = network
+		nm.ports.claim(network.ID, networkPorts(network))
+		if network.Status != NetworkStatusRunning && network.Status != NetworkStatusCreating {
+			continue
+		}
+
+		if cluster != nil && clusterMatches(network, cluster) {
+			nm.applyClusterInfo(network, cluster)
+			network.Status = NetworkStatusRunning
+		} else {
+			nm.logger.Info("network no longer running", "network", network.ID)
+			for _, node := range network.Nodes {
+				node.Status = NodeStatusStopped
+			}
+			network.Status = NetworkStatusStopped
+		}
+
+		if err := nm.save(network); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// save persists the network if a registry is configured
+func (nm *NetworkManager) save(network *Network) error {
+	if nm.registry == nil {
+		return nil
+	}
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
+	return nm.registry.save(network)
+}
+
+// saveOnError persists the network on a failure path, where the original
+// error is the one reported to the caller
+func (nm *NetworkManager) saveOnError(network *Network) {
+	if err := nm.save(network); err != nil {
+		nm.logger.Warn("failed to save network", "network", network.ID, "error", err)
+	}
+}
+
+// lookup returns the stored network by ID, falling back to the registry
+// for networks created by another process. The returned network is only
+// safe to read while holding opMu.
+func (nm *NetworkManager) lookup(networkID string) (*Network, error) {
+	nm.mu.RLock()
+	network, ok := nm.networks[networkID]
+	nm.mu.RUnlock()
+	if ok {
+		return network, nil
+	}
+
+	if nm.registry != nil {
+		network, err := nm.registry.get(networkID)
+		if err != nil {
+			return nil, err
+		}
+		if network != nil {
+			nm.mu.Lock()
+			defer nm.mu.Unlock()
+			if existing, ok := nm.networks[networkID]; ok {
+				return existing, nil
+			}
+			// the record may have been deleted while it was being read
+			if nm.registry.exists(networkID) {
+				nm.networks[network.ID] = network
+				return network, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("network %s not found", networkID)
+}
+
+// newNetworkID returns a unique network ID, the caller must hold mu
+func (nm *NetworkManager) newNetworkID() string {
+	return fmt.Sprintf("network-%d-%d", time.Now().UnixNano(), len(nm.networks))
+}
+
+// setStatus updates the status of a stored network
+func (nm *NetworkManager) setStatus(network *Network, status NetworkStatus) {
+	nm.mu.Lock()
+	previous := network.Status
+	network.Status = status
+	nm.mu.Unlock()
+
+	if previous != status {
+		nm.publish(network, previous)
+	}
+}
+
+// publish publishes the status transition of a network
+func (nm *NetworkManager) publish(network *Network, previous NetworkStatus) {
+	nm.mu.RLock()
+	event := events.Event{
+		Kind:     events.KindNetwork,
+		ID:       network.ID,
+		Name:     network.Name,
+		Previous: string(previous),
+		Status:   string(network.Status),
+	}
+	bus := nm.events
+	nm.mu.RUnlock()
+	bus.Publish(event)
+}
+
+// SetEventBus sets the bus network status transitions are published on
+func (nm *NetworkManager) SetEventBus(bus *events.Bus) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.events = bus
+}
+
+// setNodeStatus updates the status of a stored node
+func (nm *NetworkManager) setNodeStatus(node *Node, status NodeStatus) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	node.Status = status
+}
+
+// Close releases the backend connection
+func (nm *NetworkManager) Close() error {
+	if nm.backend == nil {
+		return nil
+	}
+	return nm.backend.Close()
+}
+
 // CreateNetwork creates a new network
 func (nm *NetworkManager) CreateNetwork(ctx context.Context, params *NetworkParams) (*Network, error) {
 	nm.logger.Info("creating network", "name", params.Name, "type", params.Type)
@@ -115,148 +333,685 @@ func (nm *NetworkManager) CreateNetwork(ctx context.Context, params *NetworkPara
 		return nil, fmt.Errorf("unsupported network type: %s", params.Type)
 	}
 
-	// TODO: Implement actual network creation with netrunner
-	// For now, create a mock network
+	numNodes := params.NumNodes
+	if len(params.Nodes) > 0 {
+		if numNodes != 0 && numNodes != len(params.Nodes) {
+			return nil, fmt.Errorf("number of nodes %d does not match the %d nodes given", numNodes, len(params.Nodes))
+		}
+		numNodes = len(params.Nodes)
+	}
+	if numNodes <= 0 {
+		return nil, fmt.Errorf("invalid number of nodes: %d", numNodes)
+	}
+
+	if nm.backend == nil {
+		return nil, ErrNoBackend
+	}
+
+	nodes, err := nodeParamsList(params.Nodes, numNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	nm.mu.Lock()
 	network := &Network{
-		ID:        fmt.Sprintf("network-%d-%d", time.Now().UnixNano(), len(nm.networks)),
-		Name:      params.Name,
-		Type:      params.Type,
-		Status:    NetworkStatusRunning,
-		Nodes:     nm.createMockNodes(params.NumNodes),
-		ChainIDs:  []string{"chain-1", "chain-2"},
-		CreatedAt: time.Now(),
+		ID:          nm.newNetworkID(),
+		Name:        params.Name,
+		Type:        params.Type,
+		Status:      NetworkStatusCreating,
+		BinaryPath:  params.BinaryPath,
+		RootDataDir: params.DataDir,
+		Ports:       params.Ports,
+		CreatedAt:   time.Now(),
+
+		EnableStaking:    params.EnableStaking,
+		EnableMonitoring: params.EnableMonitoring,
 	}
+	nm.mu.Unlock()
 
+	ports, err := nm.reservePorts(network.ID, params, numNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve ports for network %s: %w", params.Name, err)
+	}
+
+	opts, err := nm.startOptions(network, numNodes, params.LogLevel)
+	if err != nil {
+		nm.ports.release(network.ID)
+		return nil, err
+	}
+	opts.CustomNodeConfigs, err = customNodeConfigs(nodes, ports)
+	if err != nil {
+		nm.ports.release(network.ID)
+		return nil, err
+	}
+
+	nm.mu.Lock()
 	nm.networks[network.ID] = network
-	return network, nil
+	nm.mu.Unlock()
+	nm.publish(network, "")
+
+	info, err := nm.backend.Start(ctx, params.BinaryPath, opts)
+	if err != nil {
+		nm.ports.release(network.ID)
+		nm.setStatus(network, NetworkStatusError)
+		nm.saveOnError(network)
+		return nil, fmt.Errorf("failed to create network %s: %w", params.Name, err)
+	}
+
+	nm.mu.Lock()
+	nm.applyClusterInfo(network, info)
+	for i, nodeParams := range nodes {
+		node := findNode(network.Nodes, nodeParams.Name)
+		if node == nil {
+			continue
+		}
+		if node.Endpoint == "" {
+			node.Endpoint = nodeEndpoint(ports[i])
+		}
+		if nodeParams.Type != "" {
+			node.Type = nodeParams.Type
+		}
+		if nodeParams.StakeAmount != 0 {
+			node.StakeAmount = nodeParams.StakeAmount
+		}
+	}
+	nm.mu.Unlock()
+	nm.setStatus(network, NetworkStatusRunning)
+	if err := nm.save(network); err != nil {
+		return nil, err
+	}
+	return network.clone(), nil
 }
 
 // StartNetwork starts a stopped network
 func (nm *NetworkManager) StartNetwork(ctx context.Context, networkID string) error {
-	network, ok := nm.networks[networkID]
-	if !ok {
-		return fmt.Errorf("network %s not found", networkID)
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
 	}
 
-	// TODO: Implement actual network start with netrunner
-	network.Status = NetworkStatusRunning
-	return nil
+	if network.Status == NetworkStatusRunning {
+		return nil
+	}
+
+	if nm.backend == nil {
+		return ErrNoBackend
+	}
+
+	numNodes := len(network.Nodes)
+	if numNodes == 0 {
+		return fmt.Errorf("network %s has no nodes to start", networkID)
+	}
+
+	opts, err := nm.startOptions(network, numNodes, "")
+	if err != nil {
+		return err
+	}
+	if ports := networkPorts(network); len(ports) == numNodes {
+		if err := nm.ports.reserveFixed(networkID, ports); err != nil {
+			return fmt.Errorf("failed to reserve ports for network %s: %w", networkID, err)
+		}
+		nodes := make([]NodeParams, numNodes)
+		for i, node := range network.Nodes {
+			nodes[i].Name = node.ID
+		}
+		if opts.CustomNodeConfigs, err = customNodeConfigs(nodes, ports); err != nil {
+			return err
+		}
+	}
+
+	info, err := nm.backend.Start(ctx, network.BinaryPath, opts)
+	if err != nil {
+		nm.setStatus(network, NetworkStatusError)
+		nm.saveOnError(network)
+		return fmt.Errorf("failed to start network %s: %w", networkID, err)
+	}
+
+	nm.mu.Lock()
+	nm.applyClusterInfo(network, info)
+	nm.mu.Unlock()
+	nm.setStatus(network, NetworkStatusRunning)
+	return nm.save(network)
 }
 
 // StopNetwork stops a running network
 func (nm *NetworkManager) StopNetwork(ctx context.Context, networkID string) error {
-	network, ok := nm.networks[networkID]
-	if !ok {
-		return fmt.Errorf("network %s not found", networkID)
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	return nm.stopNetwork(ctx, networkID)
+}
+
+// stopNetwork stops a running network, the caller must hold opMu
+func (nm *NetworkManager) stopNetwork(ctx context.Context, networkID string) error {
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
 	}
 
-	// TODO: Implement actual network stop with netrunner
-	network.Status = NetworkStatusStopped
-	return nil
+	if network.Status == NetworkStatusStopped {
+		return nil
+	}
+
+	if nm.backend == nil {
+		return ErrNoBackend
+	}
+
+	if err := nm.backend.Stop(ctx); err != nil {
+		nm.setStatus(network, NetworkStatusError)
+		nm.saveOnError(network)
+		return fmt.Errorf("failed to stop network %s: %w", networkID, err)
+	}
+
+	nm.mu.Lock()
+	for _, node := range network.Nodes {
+		node.Status = NodeStatusStopped
+	}
+	nm.mu.Unlock()
+	nm.setStatus(network, NetworkStatusStopped)
+	return nm.save(network)
 }
 
-// DeleteNetwork deletes a network
+// DeleteNetwork stops the network if needed and removes it from the manager
 func (nm *NetworkManager) DeleteNetwork(ctx context.Context, networkID string) error {
-	// TODO: Implement actual network deletion with netrunner
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
+	}
+
+	if network.Status == NetworkStatusRunning {
+		if err := nm.stopNetwork(ctx, networkID); err != nil {
+			return err
+		}
+	}
+
+	nm.ports.release(networkID)
+
+	if err := nm.forget(networkID); err != nil {
+		return err
+	}
+
+	if network.ManagedDataDir && network.RootDataDir != "" {
+		if err := os.RemoveAll(network.RootDataDir); err != nil {
+			return fmt.Errorf("failed to remove data directory of network %s: %w", networkID, err)
+		}
+	}
+	return nil
+}
+
+// forget removes a network from the manager and the registry
+func (nm *NetworkManager) forget(networkID string) error {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
 	delete(nm.networks, networkID)
+	if nm.registry != nil {
+		return nm.registry.remove(networkID)
+	}
 	return nil
 }
 
-// GetNetwork returns a network by ID
+// GetNetwork returns a snapshot of a network by ID
 func (nm *NetworkManager) GetNetwork(networkID string) (*Network, error) {
-	network, ok := nm.networks[networkID]
-	if !ok {
-		return nil, fmt.Errorf("network %s not found", networkID)
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return nil, err
 	}
-	return network, nil
+
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
+	return network.clone(), nil
 }
 
-// ListNetworks returns all networks
+// ListNetworks returns snapshots of all networks, refreshed from the
+// registry so networks created, changed or deleted by other processes
+// sharing it are seen
 func (nm *NetworkManager) ListNetworks() []*Network {
+	nm.refresh()
+
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
 	networks := make([]*Network, 0, len(nm.networks))
 	for _, network := range nm.networks {
-		networks = append(networks, network)
+		networks = append(networks, network.clone())
 	}
 	return networks
 }
 
+// refresh reloads the stored networks from the registry. It is skipped
+// while a lifecycle operation runs, as this process is then the one
+// writing the networks it changes.
+func (nm *NetworkManager) refresh() {
+	if nm.registry == nil || !nm.opMu.TryLock() {
+		return
+	}
+	defer nm.opMu.Unlock()
+
+	persisted, err := nm.registry.load()
+	if err != nil {
+		nm.logger.Warn("failed to load network registry", "error", err)
+		return
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	loaded := make(map[string]bool, len(persisted))
+	for _, network := range persisted {
+		loaded[network.ID] = true
+		if existing, ok := nm.networks[network.ID]; ok {
+			*existing = *network
+			continue
+		}
+		nm.networks[network.ID] = network
+		nm.ports.claim(network.ID, networkPorts(network))
+	}
+	for networkID := range nm.networks {
+		// unreadable records are skipped by load, but still exist
+		if !loaded[networkID] && !nm.registry.exists(networkID) {
+			delete(nm.networks, networkID)
+			nm.ports.release(networkID)
+		}
+	}
+}
+
+// URIs returns the API endpoints of all nodes of a running network
+func (nm *NetworkManager) URIs(ctx context.Context, networkID string) ([]string, error) {
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if network.Status != NetworkStatusRunning {
+		return nil, fmt.Errorf("network %s is not running", networkID)
+	}
+
+	if nm.backend == nil {
+		return nil, ErrNoBackend
+	}
+
+	return nm.backend.URIs(ctx)
+}
+
 // AddNode adds a new node to the network
 func (nm *NetworkManager) AddNode(ctx context.Context, networkID string, nodeParams *NodeParams) (*Node, error) {
-	network, ok := nm.networks[networkID]
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if network.Status != NetworkStatusRunning {
+		return nil, fmt.Errorf("network %s is not running", networkID)
+	}
+
+	if nm.backend == nil {
+		return nil, ErrNoBackend
+	}
+
+	name := nodeParams.Name
+	if name == "" {
+		name = nextNodeName(network.Nodes)
+	}
+	if findNode(network.Nodes, name) != nil {
+		return nil, fmt.Errorf("node %s already exists in network %s", name, networkID)
+	}
+
+	ports, err := nm.ports.reserve(networkID, 1, nm.portRange(network.Ports))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve ports for node %s: %w", name, err)
+	}
+	// the ports are kept once the node is part of the network
+	added := false
+	defer func() {
+		if !added {
+			nm.ports.release(networkID, ports...)
+		}
+	}()
+
+	nodeConfigs, err := customNodeConfigs([]NodeParams{{Name: name, Flags: nodeParams.Flags}}, ports)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterInfo, err := nm.backend.AddNode(ctx, name, network.BinaryPath, nodeConfigs[name])
+	if err != nil {
+		return nil, fmt.Errorf("failed to add node %s to network %s: %w", name, networkID, err)
+	}
+
+	info, ok := clusterInfo.GetNodeInfos()[name]
 	if !ok {
-		return nil, fmt.Errorf("network %s not found", networkID)
+		return nil, fmt.Errorf("node %s missing from netrunner cluster info", name)
 	}
 
-	// TODO: Implement actual node addition with netrunner
-	node := &Node{
-		ID:          fmt.Sprintf("node-%d", time.Now().Unix()),
-		NodeID:      fmt.Sprintf("NodeID-%d", time.Now().Unix()),
-		Type:        nodeParams.Type,
-		Status:      NodeStatusBootstrapping,
-		Endpoint:    "http://127.0.0.1:9650",
-		StakeAmount: nodeParams.StakeAmount,
+	node := nodeFromInfo(info, false, nodeParams.StakeAmount)
+	if node.Endpoint == "" {
+		node.Endpoint = nodeEndpoint(ports[0])
+	}
+	if nodeParams.Type != "" {
+		node.Type = nodeParams.Type
 	}
 
+	nm.mu.Lock()
 	network.Nodes = append(network.Nodes, node)
-	return node, nil
+	nm.mu.Unlock()
+	added = true
+	if err := nm.save(network); err != nil {
+		return nil, err
+	}
+	nodeCopy := *node
+	return &nodeCopy, nil
 }
 
 // RemoveNode removes a node from the network
 func (nm *NetworkManager) RemoveNode(ctx context.Context, networkID, nodeID string) error {
-	network, ok := nm.networks[networkID]
-	if !ok {
-		return fmt.Errorf("network %s not found", networkID)
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
 	}
 
-	// TODO: Implement actual node removal with netrunner
-	// Remove node from network
-	for i, node := range network.Nodes {
-		if node.ID == nodeID {
-			network.Nodes = append(network.Nodes[:i], network.Nodes[i+1:]...)
-			break
+	removed := findNode(network.Nodes, nodeID)
+	if removed == nil {
+		return fmt.Errorf("node %s not found in network %s", nodeID, networkID)
+	}
+
+	if nm.backend == nil {
+		return ErrNoBackend
+	}
+
+	if err := nm.backend.RemoveNode(ctx, nodeID); err != nil {
+		return fmt.Errorf("failed to remove node %s from network %s: %w", nodeID, networkID, err)
+	}
+	if ports, ok := nodePorts(removed); ok {
+		nm.ports.release(networkID, ports)
+	}
+
+	nodes := make([]*Node, 0, len(network.Nodes)-1)
+	for _, node := range network.Nodes {
+		if node.ID != nodeID {
+			nodes = append(nodes, node)
 		}
 	}
+	nm.mu.Lock()
+	network.Nodes = nodes
+	nm.mu.Unlock()
 
-	return nil
+	return nm.save(network)
+}
+
+// RestartNode restarts a node of the network
+func (nm *NetworkManager) RestartNode(ctx context.Context, networkID, nodeID string) error {
+	return nm.restartNode(ctx, networkID, nodeID, RestartOptions{})
+}
+
+// RestartNodeWithUpgrades restarts a node of the network with the upgrade
+// bytes of chains, keyed by blockchain ID
+func (nm *NetworkManager) RestartNodeWithUpgrades(ctx context.Context, networkID, nodeID string, upgrades map[string][]byte) error {
+	upgradeConfigs := make(map[string]string, len(upgrades))
+	for blockchainID, upgrade := range upgrades {
+		upgradeConfigs[blockchainID] = string(upgrade)
+	}
+	return nm.restartNode(ctx, networkID, nodeID, RestartOptions{UpgradeConfigs: upgradeConfigs})
+}
+
+// restartNode restarts a node of the network with the restart options
+func (nm *NetworkManager) restartNode(ctx context.Context, networkID, nodeID string, opts RestartOptions) error {
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
+	}
+
+	node := findNode(network.Nodes, nodeID)
+	if node == nil {
+		return fmt.Errorf("node %s not found in network %s", nodeID, networkID)
+	}
+
+	if nm.backend == nil {
+		return ErrNoBackend
+	}
+
+	if err := nm.backend.RestartNode(ctx, nodeID, opts); err != nil {
+		nm.setNodeStatus(node, NodeStatusUnhealthy)
+		nm.saveOnError(network)
+		return fmt.Errorf("failed to restart node %s in network %s: %w", nodeID, networkID, err)
+	}
+
+	nm.setNodeStatus(node, NodeStatusBootstrapping)
+	return nm.save(network)
 }
 
-// GetNodeStatus returns the status of a node
-func (nm *NetworkManager) GetNodeStatus(ctx context.Context, networkID, nodeID string) (*NodeStatus, error) {
-	// TODO: Implement actual node status retrieval with netrunner
-	status := NodeStatusHealthy
-	return &status, nil
+// Node config keys set by the manager
+const (
+	logLevelKey       = "log-level"
+	stakingEnabledKey = "sybil-protection-enabled"
+	metricsEnabledKey = "api-metrics-enabled"
+)
+
+// startOptions builds the backend start options for a network. Options
+// the network leaves unset keep the node defaults.
+func (nm *NetworkManager) startOptions(network *Network, numNodes int, logLevel string) (StartOptions, error) {
+	opts := StartOptions{
+		NumNodes:    numNodes,
+		RootDataDir: network.RootDataDir,
+	}
+	if logLevel == "" && nm.config != nil {
+		logLevel = nm.config.LogLevel
+	}
+
+	nodeConfig := make(map[string]interface{})
+	if logLevel != "" {
+		nodeConfig[logLevelKey] = logLevel
+	}
+	if network.EnableStaking {
+		nodeConfig[stakingEnabledKey] = true
+	}
+	if network.EnableMonitoring {
+		nodeConfig[metricsEnabledKey] = true
+	}
+	if len(nodeConfig) == 0 {
+		return opts, nil
+	}
+	configBytes, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return opts, fmt.Errorf("failed to marshal node config: %w", err)
+	}
+	opts.GlobalNodeConfig = string(configBytes)
+	return opts, nil
 }
 
-// createMockNodes creates mock nodes for testing
-func (nm *NetworkManager) createMockNodes(numNodes int) []*Node {
-	nodes := make([]*Node, numNodes)
-	for i := 0; i < numNodes; i++ {
-		nodes[i] = &Node{
-			ID:          fmt.Sprintf("node-%d", i),
-			NodeID:      fmt.Sprintf("NodeID-%d", i),
-			Type:        NodeTypeValidator,
-			Status:      NodeStatusHealthy,
-			Endpoint:    fmt.Sprintf("http://127.0.0.1:%d", 9650+i),
-			StakeAmount: 2000,
+// nodeParamsList returns the parameters of each node of a new network,
+// naming the nodes that have no name
+func nodeParamsList(nodes []NodeParams, numNodes int) ([]NodeParams, error) {
+	list := make([]NodeParams, numNodes)
+	names := make(map[string]bool, numNodes)
+	for i := range list {
+		if i < len(nodes) {
+			list[i] = nodes[i]
+		}
+		if list[i].Name == "" {
+			list[i].Name = fmt.Sprintf("node%d", i+1)
+		}
+		if names[list[i].Name] {
+			return nil, fmt.Errorf("duplicate node name %s", list[i].Name)
+		}
+		names[list[i].Name] = true
+	}
+	return list, nil
+}
+
+// customNodeConfigs returns the config of each node, keyed by node name,
+// made of its flags and its reserved ports. netrunner names the nodes after
+// the keys.
+func customNodeConfigs(nodes []NodeParams, ports []NodePorts) (map[string]string, error) {
+	configs := make(map[string]string, len(nodes))
+	for i, node := range nodes {
+		flags := make(map[string]interface{}, len(node.Flags)+2)
+		for key, value := range node.Flags {
+			flags[key] = value
+		}
+		flags[httpPortKey] = ports[i].HTTP
+		flags[stakingPortKey] = ports[i].Staking
+
+		nodeConfig, err := json.Marshal(flags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flags for node %s: %w", node.Name, err)
+		}
+		configs[node.Name] = string(nodeConfig)
+	}
+	return configs, nil
+}
+
+// reservePorts reserves the ports of the nodes of a new network. Nodes get
+// consecutive pairs from HTTPPort when it is set without a port range.
+func (nm *NetworkManager) reservePorts(networkID string, params *NetworkParams, numNodes int) ([]NodePorts, error) {
+	if params.Ports != nil || params.HTTPPort == 0 {
+		return nm.ports.reserve(networkID, numNodes, nm.portRange(params.Ports))
+	}
+
+	if params.StakingPort != 0 && params.StakingPort != params.HTTPPort+1 {
+		return nil, fmt.Errorf("staking port %d must follow HTTP port %d", params.StakingPort, params.HTTPPort)
+	}
+	ports := make([]NodePorts, numNodes)
+	for i := range ports {
+		httpPort := params.HTTPPort + 2*i
+		ports[i] = NodePorts{HTTP: httpPort, Staking: httpPort + 1}
+	}
+	if err := nm.ports.reserveFixed(networkID, ports); err != nil {
+		return nil, err
+	}
+	return ports, nil
+}
+
+// portRange returns the given port range, or the default one of the manager
+func (nm *NetworkManager) portRange(ports *PortRange) PortRange {
+	if ports != nil {
+		return *ports
+	}
+	start := DefaultPortRangeStart
+	if nm.config != nil && nm.config.HTTPPort != 0 {
+		start = nm.config.HTTPPort
+	}
+	return PortRange{Start: start, End: start + defaultPortRangeSize - 1}
+}
+
+// applyClusterInfo refreshes the network nodes and chains from netrunner
+func (nm *NetworkManager) applyClusterInfo(network *Network, info *rpcpb.ClusterInfo) {
+	if info == nil {
+		return
+	}
+	var stakeAmount uint64
+	if nm.config != nil {
+		stakeAmount = nm.config.StakeAmount
+	}
+	nodes := nodesFromClusterInfo(info, stakeAmount)
+	// netrunner does not know about paused processes, the manager does
+	for _, node := range nodes {
+		if previous := findNode(network.Nodes, node.ID); previous != nil && previous.Status == NodeStatusPaused {
+			node.Status = NodeStatusPaused
+		}
+	}
+	network.Nodes = nodes
+	network.ChainIDs = chainIDsFromClusterInfo(info)
+	if rootDataDir := info.GetRootDataDir(); rootDataDir != "" {
+		network.RootDataDir = rootDataDir
+	}
+}
+
+// clusterMatches reports whether the running netrunner cluster belongs to the network
+func clusterMatches(network *Network, info *rpcpb.ClusterInfo) bool {
+	if network.RootDataDir != "" && info.GetRootDataDir() != "" {
+		return network.RootDataDir == info.GetRootDataDir()
+	}
+	if len(network.Nodes) == 0 || len(network.Nodes) != len(info.GetNodeInfos()) {
+		return false
+	}
+	for _, node := range network.Nodes {
+		nodeInfo, ok := info.GetNodeInfos()[node.ID]
+		if !ok || nodeInfo.GetId() != node.NodeID {
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a deep copy of the network, so callers never share state
+// with the manager
+func (n *Network) clone() *Network {
+	c := *n
+	if n.Nodes != nil {
+		c.Nodes = make([]*Node, len(n.Nodes))
+		for i, node := range n.Nodes {
+			nodeCopy := *node
+			c.Nodes[i] = &nodeCopy
+		}
+	}
+	c.ChainIDs = slices.Clone(n.ChainIDs)
+	c.Snapshots = slices.Clone(n.Snapshots)
+	if n.Ports != nil {
+		ports := *n.Ports
+		c.Ports = &ports
+	}
+	return &c
+}
+
+// findNode returns the node with the given ID or nil
+func findNode(nodes []*Node, nodeID string) *Node {
+	for _, node := range nodes {
+		if node.ID == nodeID {
+			return node
+		}
+	}
+	return nil
+}
+
+// nextNodeName returns the first free netrunner style node name (node1, node2, ...)
+func nextNodeName(nodes []*Node) string {
+	for i := len(nodes) + 1; ; i++ {
+		name := fmt.Sprintf("node%d", i)
+		if findNode(nodes, name) == nil {
+			return name
 		}
 	}
-	return nodes
 }
 
 // NetworkParams defines parameters for creating a network
 type NetworkParams struct {
-	Name             string
-	Type             NetworkType
-	NumNodes         int
-	BinaryPath       string
-	ConfigPath       string
-	DataDir          string
-	LogLevel         string
-	HTTPPort         int
-	StakingPort      int
-	EnableStaking    bool
+	Name        string
+	Type        NetworkType
+	NumNodes    int
+	BinaryPath  string
+	ConfigPath  string
+	DataDir     string
+	LogLevel    string
+	HTTPPort    int
+	StakingPort int
+	// EnableStaking runs the nodes with sybil protection
+	EnableStaking bool
+	// EnableMonitoring serves the metrics API of the nodes
 	EnableMonitoring bool
 	ChainConfigs     []ChainConfig
+	// Ports is the range node HTTP and staking ports are taken from
+	Ports *PortRange
+	// Nodes optionally names and configures each node, overriding NumNodes
+	Nodes []NodeParams
 }
 
 // NodeParams defines parameters for adding a node
@@ -264,12 +1019,17 @@ type NodeParams struct {
 	Name        string
 	Type        NodeType
 	StakeAmount uint64
+	// Flags are node config flags applied on top of the network config
+	Flags map[string]interface{}
 }
 
 // ChainConfig defines configuration for a chain
 type ChainConfig struct {
-	ChainID string
-	VMType  string
-	Genesis []byte
-	Config  []byte
+	Name       string
+	Type       string
+	ChainID    string
+	VMType     string
+	Genesis    []byte
+	Config     []byte
+	Validators []ChainValidator
 }
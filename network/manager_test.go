@@ -5,14 +5,231 @@ package network
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/luxfi/log"
+	"github.com/luxfi/netrunner-sdk/rpcpb"
 	"github.com/luxfi/sdk/config"
+	"github.com/luxfi/sdk/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// fakeBackend is an in-process stand-in for a netrunner server
+type fakeBackend struct {
+	mu          sync.Mutex
+	running     bool
+	rootDataDir string
+	nodes       map[string]*rpcpb.NodeInfo
+	nextPort    int
+	startErr    error
+	starts      int
+	restarts    map[string]int
+	configs     map[string]string
+	paused      map[string]bool
+	killed      map[string]bool
+	// endpoints overrides the URI of the named nodes
+	endpoints map[string]string
+	// globalConfig is the global node config of the last start
+	globalConfig string
+	// upgrades are the upgrade configs of the last restart of each node
+	upgrades map[string]map[string]string
+	// hideAdded leaves added nodes out of the cluster info AddNode returns
+	hideAdded bool
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		nodes:     make(map[string]*rpcpb.NodeInfo),
+		nextPort:  9650,
+		restarts:  make(map[string]int),
+		configs:   make(map[string]string),
+		upgrades:  make(map[string]map[string]string),
+		paused:    make(map[string]bool),
+		killed:    make(map[string]bool),
+		endpoints: make(map[string]string),
+	}
+}
+
+func (f *fakeBackend) newNode(name string) *rpcpb.NodeInfo {
+	port := f.nextPort
+	var flags struct {
+		HTTPPort int `json:"http-port"`
+	}
+	if err := json.Unmarshal([]byte(f.configs[name]), &flags); err == nil && flags.HTTPPort != 0 {
+		port = flags.HTTPPort
+	} else {
+		f.nextPort += 2
+	}
+	info := &rpcpb.NodeInfo{
+		Name:   name,
+		Id:     fmt.Sprintf("NodeID-%s", name),
+		Uri:    fmt.Sprintf("http://127.0.0.1:%d", port),
+		Config: []byte(f.configs[name]),
+	}
+	if endpoint, ok := f.endpoints[name]; ok {
+		info.Uri = endpoint
+	}
+	f.nodes[name] = info
+	return info
+}
+
+func (f *fakeBackend) clusterInfo() *rpcpb.ClusterInfo {
+	info := &rpcpb.ClusterInfo{
+		NodeInfos:   make(map[string]*rpcpb.NodeInfo, len(f.nodes)),
+		RootDataDir: f.rootDataDir,
+		Healthy:     true,
+	}
+	for name, node := range f.nodes {
+		info.NodeNames = append(info.NodeNames, name)
+		info.NodeInfos[name] = node
+	}
+	return info
+}
+
+func (f *fakeBackend) Start(_ context.Context, _ string, opts StartOptions) (*rpcpb.ClusterInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+	f.starts++
+	f.running = true
+	f.rootDataDir = opts.RootDataDir
+	f.globalConfig = opts.GlobalNodeConfig
+	if f.rootDataDir == "" {
+		f.rootDataDir = fmt.Sprintf("/tmp/netrunner-%d", f.starts)
+	}
+	f.nodes = make(map[string]*rpcpb.NodeInfo)
+	if len(opts.CustomNodeConfigs) > 0 {
+		for name, nodeConfig := range opts.CustomNodeConfigs {
+			f.configs[name] = nodeConfig
+			f.newNode(name)
+		}
+		return f.clusterInfo(), nil
+	}
+	for i := 1; i <= opts.NumNodes; i++ {
+		f.newNode(fmt.Sprintf("node%d", i))
+	}
+	return f.clusterInfo(), nil
+}
+
+func (f *fakeBackend) Stop(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.running = false
+	return nil
+}
+
+func (f *fakeBackend) Status(context.Context) (*rpcpb.ClusterInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.running {
+		return nil, errors.New("network not running")
+	}
+	return f.clusterInfo(), nil
+}
+
+func (f *fakeBackend) URIs(context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	uris := make([]string, 0, len(f.nodes))
+	for _, node := range f.nodes {
+		uris = append(uris, node.Uri)
+	}
+	return uris, nil
+}
+
+func (f *fakeBackend) AddNode(_ context.Context, name string, _ string, nodeConfig string) (*rpcpb.ClusterInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nodes[name]; ok {
+		return nil, fmt.Errorf("node %s already exists", name)
+	}
+	f.configs[name] = nodeConfig
+	f.newNode(name)
+	info := f.clusterInfo()
+	if f.hideAdded {
+		delete(info.NodeInfos, name)
+	}
+	return info, nil
+}
+
+func (f *fakeBackend) RemoveNode(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nodes[name]; !ok {
+		return fmt.Errorf("node %s not found", name)
+	}
+	delete(f.nodes, name)
+	return nil
+}
+
+func (f *fakeBackend) RestartNode(_ context.Context, name string, opts RestartOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nodes[name]; !ok {
+		return fmt.Errorf("node %s not found", name)
+	}
+	f.restarts[name]++
+	f.killed[name] = false
+	if opts.NodeConfig != "" {
+		f.configs[name] = opts.NodeConfig
+	}
+	f.upgrades[name] = opts.UpgradeConfigs
+	return nil
+}
+
+func (f *fakeBackend) PauseNode(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nodes[name]; !ok {
+		return fmt.Errorf("node %s not found", name)
+	}
+	f.paused[name] = true
+	return nil
+}
+
+func (f *fakeBackend) ResumeNode(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.paused[name] {
+		return fmt.Errorf("node %s is not paused", name)
+	}
+	f.paused[name] = false
+	return nil
+}
+
+func (f *fakeBackend) KillNode(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.nodes[name]; !ok {
+		return fmt.Errorf("node %s not found", name)
+	}
+	f.killed[name] = true
+	return nil
+}
+
+func (*fakeBackend) Close() error {
+	return nil
+}
+
+func newTestManager(t *testing.T) (*NetworkManager, *fakeBackend) {
+	t.Helper()
+	backend := newFakeBackend()
+	nm, err := NewNetworkManagerWithBackend(&config.NetworkConfig{StakeAmount: 2000}, backend, log.NewNoOpLogger())
+	require.NoError(t, err)
+	// keep test ports deterministic whatever is listening on the host
+	nm.ports.inUse = func(int) bool { return false }
+	return nm, backend
+}
+
 func TestNetworkManager_CreateNetwork(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -54,9 +271,7 @@ func TestNetworkManager_CreateNetwork(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			logger := log.NewNoOpLogger()
-			nm, err := NewNetworkManager(&config.NetworkConfig{}, logger)
-			require.NoError(t, err)
+			nm, _ := newTestManager(t)
 
 			ctx := context.Background()
 			network, err := nm.CreateNetwork(ctx, tt.params)
@@ -80,9 +295,7 @@ func TestNetworkManager_CreateNetwork(t *testing.T) {
 }
 
 func TestNetworkManager_StartNetwork(t *testing.T) {
-	logger := log.NewNoOpLogger()
-	nm, err := NewNetworkManager(&config.NetworkConfig{}, logger)
-	require.NoError(t, err)
+	nm, _ := newTestManager(t)
 	ctx := context.Background()
 
 	// Create a test network first
@@ -105,9 +318,7 @@ func TestNetworkManager_StartNetwork(t *testing.T) {
 }
 
 func TestNetworkManager_StopNetwork(t *testing.T) {
-	logger := log.NewNoOpLogger()
-	nm, err := NewNetworkManager(&config.NetworkConfig{}, logger)
-	require.NoError(t, err)
+	nm, _ := newTestManager(t)
 	ctx := context.Background()
 
 	// Create and start a test network
@@ -132,10 +343,36 @@ func TestNetworkManager_StopNetwork(t *testing.T) {
 	assert.Equal(t, NetworkStatusStopped, updatedNetwork.Status)
 }
 
-func TestNetworkManager_DeleteNetwork(t *testing.T) {
-	logger := log.NewNoOpLogger()
-	nm, err := NewNetworkManager(&config.NetworkConfig{}, logger)
+func TestNetworkManager_NodeOptions(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{Name: "defaults", Type: NetworkTypeLocal, NumNodes: 1})
+	require.NoError(t, err)
+	assert.Empty(t, backend.globalConfig)
+	require.NoError(t, nm.DeleteNetwork(ctx, network.ID))
+
+	network, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:             "options",
+		Type:             NetworkTypeLocal,
+		NumNodes:         1,
+		LogLevel:         "debug",
+		EnableStaking:    true,
+		EnableMonitoring: true,
+	})
 	require.NoError(t, err)
+	assert.True(t, network.EnableStaking)
+	assert.True(t, network.EnableMonitoring)
+	assert.JSONEq(t, `{"log-level":"debug","sybil-protection-enabled":true,"api-metrics-enabled":true}`, backend.globalConfig)
+
+	// the options are reapplied when the network is started again
+	require.NoError(t, nm.StopNetwork(ctx, network.ID))
+	require.NoError(t, nm.StartNetwork(ctx, network.ID))
+	assert.JSONEq(t, `{"sybil-protection-enabled":true,"api-metrics-enabled":true}`, backend.globalConfig)
+}
+
+func TestNetworkManager_DeleteNetwork(t *testing.T) {
+	nm, _ := newTestManager(t)
 	ctx := context.Background()
 
 	// Create a test network
@@ -158,10 +395,10 @@ func TestNetworkManager_DeleteNetwork(t *testing.T) {
 }
 
 func TestNetworkManager_GetNodeStatus(t *testing.T) {
-	logger := log.NewNoOpLogger()
-	nm, err := NewNetworkManager(&config.NetworkConfig{}, logger)
-	require.NoError(t, err)
+	nm, backend := newTestManager(t)
 	ctx := context.Background()
+	node := newFakeNode(t, true, true)
+	backend.endpoints["node1"] = node.URL
 
 	// Create a test network
 	params := &NetworkParams{
@@ -183,9 +420,7 @@ func TestNetworkManager_GetNodeStatus(t *testing.T) {
 }
 
 func TestNetworkManager_ListNetworks(t *testing.T) {
-	logger := log.NewNoOpLogger()
-	nm, err := NewNetworkManager(&config.NetworkConfig{}, logger)
-	require.NoError(t, err)
+	nm, _ := newTestManager(t)
 	ctx := context.Background()
 
 	// Test empty list initially
@@ -217,3 +452,406 @@ func TestNetworkManager_ListNetworks(t *testing.T) {
 		assert.True(t, names[expectedName], "Network %s should be in the list", expectedName)
 	}
 }
+
+func TestNetworkManager_CreateNetworkFromClusterInfo(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:       "cluster-test",
+		Type:       NetworkTypeLocal,
+		NumNodes:   3,
+		BinaryPath: "/usr/local/bin/luxd",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, backend.starts)
+	require.Len(t, network.Nodes, 3)
+
+	for i, node := range network.Nodes {
+		name := fmt.Sprintf("node%d", i+1)
+		assert.Equal(t, name, node.ID)
+		assert.Equal(t, "NodeID-"+name, node.NodeID)
+		assert.Equal(t, fmt.Sprintf("http://127.0.0.1:%d", 9650+2*i), node.Endpoint)
+		assert.Equal(t, NodeStatusHealthy, node.Status)
+		assert.Equal(t, uint64(2000), node.StakeAmount)
+	}
+
+	uris, err := nm.URIs(ctx, network.ID)
+	require.NoError(t, err)
+	assert.Len(t, uris, 3)
+}
+
+func TestNetworkManager_CreateNetworkErrors(t *testing.T) {
+	ctx := context.Background()
+	params := &NetworkParams{
+		Name:     "error-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 3,
+	}
+
+	t.Run("no backend", func(t *testing.T) {
+		nm, err := NewNetworkManagerWithBackend(&config.NetworkConfig{}, nil, log.NewNoOpLogger())
+		require.NoError(t, err)
+
+		_, err = nm.CreateNetwork(ctx, params)
+		assert.ErrorIs(t, err, ErrNoBackend)
+	})
+
+	t.Run("backend failure", func(t *testing.T) {
+		nm, backend := newTestManager(t)
+		backend.startErr = errors.New("exec path not found")
+
+		_, err := nm.CreateNetwork(ctx, params)
+		assert.ErrorContains(t, err, "exec path not found")
+
+		list := nm.ListNetworks()
+		require.Len(t, list, 1)
+		assert.Equal(t, NetworkStatusError, list[0].Status)
+	})
+
+	t.Run("invalid number of nodes", func(t *testing.T) {
+		nm, _ := newTestManager(t)
+
+		_, err := nm.CreateNetwork(ctx, &NetworkParams{
+			Name: "no-nodes",
+			Type: NetworkTypeLocal,
+		})
+		assert.ErrorContains(t, err, "invalid number of nodes")
+	})
+}
+
+func TestNetworkManager_NoEndpoint(t *testing.T) {
+	ctx := context.Background()
+	nm, err := NewNetworkManager(&config.NetworkConfig{}, log.NewNoOpLogger())
+	require.NoError(t, err)
+
+	_, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "no-endpoint",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+	})
+	assert.ErrorIs(t, err, ErrNoBackend)
+	assert.Empty(t, nm.ListNetworks())
+
+	_, err = nm.LoadSnapshot(ctx, "snapshot")
+	assert.ErrorIs(t, err, ErrNoBackend)
+	assert.NoError(t, nm.Close())
+}
+
+func TestNetworkManager_NodeLifecycle(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "node-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+	})
+	require.NoError(t, err)
+
+	node, err := nm.AddNode(ctx, network.ID, &NodeParams{
+		Type:        NodeTypeAPI,
+		StakeAmount: 500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "node3", node.ID)
+	assert.Equal(t, "NodeID-node3", node.NodeID)
+	assert.Equal(t, NodeTypeAPI, node.Type)
+	assert.Equal(t, uint64(500), node.StakeAmount)
+
+	network, err = nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Len(t, network.Nodes, 3)
+
+	_, err = nm.AddNode(ctx, network.ID, &NodeParams{Name: "node3"})
+	assert.ErrorContains(t, err, "already exists")
+
+	require.NoError(t, nm.RestartNode(ctx, network.ID, "node1"))
+	assert.Equal(t, 1, backend.restarts["node1"])
+	assert.Empty(t, backend.upgrades["node1"])
+
+	upgrade := []byte(`{"precompileUpgrades":[]}`)
+	require.NoError(t, nm.RestartNodeWithUpgrades(ctx, network.ID, "node1", map[string][]byte{"chain": upgrade}))
+	assert.Equal(t, 2, backend.restarts["node1"])
+	assert.Equal(t, map[string]string{"chain": string(upgrade)}, backend.upgrades["node1"])
+
+	require.NoError(t, nm.RemoveNode(ctx, network.ID, "node2"))
+	network, err = nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	require.Len(t, network.Nodes, 2)
+	assert.Equal(t, "node1", network.Nodes[0].ID)
+	assert.Equal(t, "node3", network.Nodes[1].ID)
+
+	err = nm.RemoveNode(ctx, network.ID, "node2")
+	assert.ErrorContains(t, err, "not found")
+
+	require.NoError(t, nm.StopNetwork(ctx, network.ID))
+	network, err = nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	for _, node := range network.Nodes {
+		assert.Equal(t, NodeStatusStopped, node.Status)
+	}
+	_, err = nm.AddNode(ctx, network.ID, &NodeParams{})
+	assert.ErrorContains(t, err, "not running")
+}
+
+func TestNetworkManager_Registry(t *testing.T) {
+	ctx := context.Background()
+	registryDir := t.TempDir()
+	cfg := &config.NetworkConfig{StakeAmount: 2000, RegistryDir: registryDir}
+	backend := newFakeBackend()
+
+	nm, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+	require.NoError(t, err)
+
+	running, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "ci-network",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+	})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(registryDir, running.ID+".json"))
+
+	t.Run("reload in another process", func(t *testing.T) {
+		other, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+		require.NoError(t, err)
+
+		network, err := other.GetNetwork(running.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "ci-network", network.Name)
+		assert.Equal(t, NetworkStatusRunning, network.Status)
+		require.Len(t, network.Nodes, 2)
+		assert.Equal(t, running.Nodes[0].NodeID, network.Nodes[0].NodeID)
+		assert.Equal(t, running.Nodes[1].Endpoint, network.Nodes[1].Endpoint)
+	})
+
+	t.Run("visible without restart", func(t *testing.T) {
+		other, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+		require.NoError(t, err)
+
+		require.Len(t, other.ListNetworks(), 1)
+		node, err := nm.AddNode(ctx, running.ID, &NodeParams{})
+		require.NoError(t, err)
+		// networks already listed are refreshed
+		listed := other.ListNetworks()
+		require.Len(t, listed, 1)
+		assert.Len(t, listed[0].Nodes, 3)
+		require.NoError(t, nm.RemoveNode(ctx, running.ID, node.ID))
+		listed = other.ListNetworks()
+		require.Len(t, listed, 1)
+		assert.Len(t, listed[0].Nodes, 2)
+
+		created, err := nm.CreateNetwork(ctx, &NetworkParams{
+			Name:     "late-network",
+			Type:     NetworkTypeLocal,
+			NumNodes: 1,
+		})
+		require.NoError(t, err)
+
+		network, err := other.GetNetwork(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "late-network", network.Name)
+		assert.Len(t, other.ListNetworks(), 2)
+
+		require.NoError(t, nm.DeleteNetwork(ctx, created.ID))
+		assert.NoFileExists(t, filepath.Join(registryDir, created.ID+".json"))
+		assert.Len(t, other.ListNetworks(), 1)
+	})
+
+	t.Run("corrupt record", func(t *testing.T) {
+		corrupt := filepath.Join(registryDir, "network-corrupt.json")
+		require.NoError(t, os.WriteFile(corrupt, []byte("{"), 0o600))
+		defer os.Remove(corrupt)
+
+		other, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+		require.NoError(t, err)
+		networks := other.ListNetworks()
+		require.Len(t, networks, 1)
+		assert.Equal(t, running.ID, networks[0].ID)
+	})
+
+	t.Run("reconcile stopped cluster", func(t *testing.T) {
+		require.NoError(t, backend.Stop(ctx))
+
+		other, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+		require.NoError(t, err)
+
+		network, err := other.GetNetwork(running.ID)
+		require.NoError(t, err)
+		assert.Equal(t, NetworkStatusStopped, network.Status)
+		for _, node := range network.Nodes {
+			assert.Equal(t, NodeStatusStopped, node.Status)
+		}
+	})
+
+	t.Run("reconcile without backend", func(t *testing.T) {
+		_, err := NewNetworkManagerWithBackend(cfg, nil, log.NewNoOpLogger())
+		require.NoError(t, err)
+	})
+}
+
+func TestNetworkManager_Snapshots(t *testing.T) {
+	nm, _ := newTestManager(t)
+	ctx := context.Background()
+
+	created, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "snapshot-test",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+	})
+	require.NoError(t, err)
+
+	// mutating returned values must not leak into the manager
+	created.Status = NetworkStatusError
+	created.Nodes[0].Status = NodeStatusUnhealthy
+	created.Nodes = created.Nodes[:1]
+
+	got, err := nm.GetNetwork(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NetworkStatusRunning, got.Status)
+	require.Len(t, got.Nodes, 2)
+	assert.Equal(t, NodeStatusHealthy, got.Nodes[0].Status)
+
+	got.Nodes[1].Endpoint = "http://example.invalid"
+	list := nm.ListNetworks()
+	require.Len(t, list, 1)
+	assert.NotEqual(t, "http://example.invalid", list[0].Nodes[1].Endpoint)
+
+	node, err := nm.AddNode(ctx, created.ID, &NodeParams{})
+	require.NoError(t, err)
+	node.Status = NodeStatusStopped
+
+	got, err = nm.GetNetwork(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusBootstrapping, got.Nodes[2].Status)
+}
+
+func TestNetworkManager_ConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	backend := newFakeBackend()
+	cfg := &config.NetworkConfig{StakeAmount: 2000, RegistryDir: t.TempDir()}
+	nm, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+	require.NoError(t, err)
+
+	const workers = 8
+	var (
+		wg   sync.WaitGroup
+		kept = make([]string, workers)
+		done = make(chan struct{})
+	)
+
+	// readers walk snapshots while the registry is being mutated
+	var readers sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for _, network := range nm.ListNetworks() {
+					for _, node := range network.Nodes {
+						node.Status = NodeStatusStopped
+					}
+					if got, err := nm.GetNetwork(network.ID); err == nil {
+						_ = len(got.Nodes)
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			network, err := nm.CreateNetwork(ctx, &NetworkParams{
+				Name:     fmt.Sprintf("parallel-%d", i),
+				Type:     NetworkTypeLocal,
+				NumNodes: 2,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = nm.AddNode(ctx, network.ID, &NodeParams{Name: fmt.Sprintf("extra-%d", i)})
+			assert.NoError(t, err)
+
+			if i%2 == 0 {
+				assert.NoError(t, nm.DeleteNetwork(ctx, network.ID))
+				return
+			}
+			kept[i] = network.ID
+		}(i)
+	}
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	list := nm.ListNetworks()
+	assert.Len(t, list, workers/2)
+	for i, networkID := range kept {
+		if i%2 == 0 {
+			continue
+		}
+		network, err := nm.GetNetwork(networkID)
+		require.NoError(t, err)
+		require.Len(t, network.Nodes, 3)
+		assert.Equal(t, fmt.Sprintf("extra-%d", i), network.Nodes[2].ID)
+	}
+
+	// a fresh manager sees exactly the surviving networks
+	other, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+	require.NoError(t, err)
+	assert.Len(t, other.ListNetworks(), workers/2)
+}
+
+func TestNetworkManager_Events(t *testing.T) {
+	nm, backend := newTestManager(t)
+	bus := events.NewBus()
+	nm.SetEventBus(bus)
+	ch := bus.Subscribe(events.Filter{Kinds: []events.Kind{events.KindNetwork}})
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "events",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, nm.StopNetwork(ctx, network.ID))
+	require.NoError(t, nm.StartNetwork(ctx, network.ID))
+
+	backend.startErr = errors.New("start failed")
+	_, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "broken",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+	})
+	require.Error(t, err)
+
+	transitions := []struct {
+		name     string
+		previous NetworkStatus
+		status   NetworkStatus
+	}{
+		{"events", "", NetworkStatusCreating},
+		{"events", NetworkStatusCreating, NetworkStatusRunning},
+		{"events", NetworkStatusRunning, NetworkStatusStopped},
+		{"events", NetworkStatusStopped, NetworkStatusRunning},
+		{"broken", "", NetworkStatusCreating},
+		{"broken", NetworkStatusCreating, NetworkStatusError},
+	}
+	for _, want := range transitions {
+		event := <-ch
+		assert.Equal(t, events.KindNetwork, event.Kind)
+		assert.Equal(t, want.name, event.Name)
+		assert.Equal(t, string(want.previous), event.Previous)
+		assert.Equal(t, string(want.status), event.Status)
+	}
+	assert.Empty(t, ch)
+}
@@ -0,0 +1,378 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/netrunner-sdk/rpcpb"
+	"github.com/luxfi/sdk/config"
+	"github.com/luxfi/sdk/netrunner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeNetrunnerServer is a netrunner control service serving a cluster
+// description, so tests drive netrunnerBackend through real gRPC calls
+type fakeNetrunnerServer struct {
+	rpcpb.UnimplementedControlServiceServer
+
+	mu          sync.Mutex
+	running     bool
+	rootDataDir string
+	nodes       map[string]*rpcpb.NodeInfo
+	// restarts are the restart requests received
+	restarts []*rpcpb.RestartNodeRequest
+	// snapshots are the nodes of the saved snapshots
+	snapshots map[string]map[string]*rpcpb.NodeInfo
+}
+
+// newNetrunnerTestManager returns a manager whose backend talks to a fake
+// netrunner server over gRPC
+func newNetrunnerTestManager(t *testing.T) (*NetworkManager, *fakeNetrunnerServer) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeNetrunnerServer{
+		nodes:     make(map[string]*rpcpb.NodeInfo),
+		snapshots: make(map[string]map[string]*rpcpb.NodeInfo),
+	}
+	grpcServer := grpc.NewServer()
+	rpcpb.RegisterControlServiceServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	client, err := netrunner.NewClient(&netrunner.Config{
+		Endpoint:    listener.Addr().String(),
+		DialTimeout: 5 * time.Second,
+		LogLevel:    "error",
+	}, log.NewNoOpLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	nm, err := NewNetworkManagerWithBackend(&config.NetworkConfig{StakeAmount: 2000}, NewNetrunnerBackend(client), log.NewNoOpLogger())
+	require.NoError(t, err)
+	nm.ports.inUse = func(int) bool { return false }
+	return nm, server
+}
+
+// addNode registers a node started with the given config, the caller must hold mu
+func (s *fakeNetrunnerServer) addNode(name string, execPath string, nodeConfig string) *rpcpb.NodeInfo {
+	var flags struct {
+		HTTPPort int `json:"http-port"`
+	}
+	_ = json.Unmarshal([]byte(nodeConfig), &flags)
+	info := &rpcpb.NodeInfo{
+		Name:     name,
+		ExecPath: execPath,
+		Uri:      fmt.Sprintf("http://127.0.0.1:%d", flags.HTTPPort),
+		Id:       "NodeID-" + name,
+		Config:   []byte(nodeConfig),
+	}
+	s.nodes[name] = info
+	return info
+}
+
+// clusterInfo describes the running cluster, the caller must hold mu
+func (s *fakeNetrunnerServer) clusterInfo() *rpcpb.ClusterInfo {
+	info := &rpcpb.ClusterInfo{
+		NodeInfos:   make(map[string]*rpcpb.NodeInfo, len(s.nodes)),
+		RootDataDir: s.rootDataDir,
+		Healthy:     true,
+	}
+	for name, node := range s.nodes {
+		info.NodeNames = append(info.NodeNames, name)
+		info.NodeInfos[name] = node
+	}
+	sort.Strings(info.NodeNames)
+	return info
+}
+
+func (s *fakeNetrunnerServer) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.StartResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = true
+	s.rootDataDir = req.GetRootDataDir()
+	s.nodes = make(map[string]*rpcpb.NodeInfo)
+	for name, nodeConfig := range req.GetCustomNodeConfigs() {
+		s.addNode(name, req.GetExecPath(), nodeConfig)
+	}
+	return &rpcpb.StartResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) Status(context.Context, *rpcpb.StatusRequest) (*rpcpb.StatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running {
+		return nil, fmt.Errorf("network not running")
+	}
+	return &rpcpb.StatusResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) Stop(context.Context, *rpcpb.StopRequest) (*rpcpb.StopResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	return &rpcpb.StopResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) URIs(context.Context, *rpcpb.URIsRequest) (*rpcpb.URIsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uris := make([]string, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		uris = append(uris, node.Uri)
+	}
+	sort.Strings(uris)
+	return &rpcpb.URIsResponse{Uris: uris}, nil
+}
+
+func (s *fakeNetrunnerServer) AddNode(_ context.Context, req *rpcpb.AddNodeRequest) (*rpcpb.AddNodeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nodes[req.GetName()]; ok {
+		return nil, fmt.Errorf("node %s already exists", req.GetName())
+	}
+	s.addNode(req.GetName(), req.GetExecPath(), req.GetNodeConfig())
+	return &rpcpb.AddNodeResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) RemoveNode(_ context.Context, req *rpcpb.RemoveNodeRequest) (*rpcpb.RemoveNodeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nodes[req.GetName()]; !ok {
+		return nil, fmt.Errorf("node %s not found", req.GetName())
+	}
+	delete(s.nodes, req.GetName())
+	return &rpcpb.RemoveNodeResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) RestartNode(_ context.Context, req *rpcpb.RestartNodeRequest) (*rpcpb.RestartNodeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nodes[req.GetName()]; !ok {
+		return nil, fmt.Errorf("node %s not found", req.GetName())
+	}
+	s.restarts = append(s.restarts, req)
+	return &rpcpb.RestartNodeResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) SaveSnapshot(_ context.Context, req *rpcpb.SaveSnapshotRequest) (*rpcpb.SaveSnapshotResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running {
+		return nil, fmt.Errorf("network not running")
+	}
+	s.snapshots[req.GetSnapshotName()] = s.nodes
+	s.nodes = make(map[string]*rpcpb.NodeInfo)
+	s.running = false
+	return &rpcpb.SaveSnapshotResponse{SnapshotPath: filepath.Join(s.rootDataDir, req.GetSnapshotName())}, nil
+}
+
+func (s *fakeNetrunnerServer) LoadSnapshot(_ context.Context, req *rpcpb.LoadSnapshotRequest) (*rpcpb.LoadSnapshotResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nodes, ok := s.snapshots[req.GetSnapshotName()]
+	if !ok {
+		return nil, fmt.Errorf("snapshot %s not found", req.GetSnapshotName())
+	}
+	s.nodes = make(map[string]*rpcpb.NodeInfo, len(nodes))
+	for name, node := range nodes {
+		s.nodes[name] = node
+	}
+	s.running = true
+	return &rpcpb.LoadSnapshotResponse{ClusterInfo: s.clusterInfo()}, nil
+}
+
+func (s *fakeNetrunnerServer) RemoveSnapshot(_ context.Context, req *rpcpb.RemoveSnapshotRequest) (*rpcpb.RemoveSnapshotResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.snapshots[req.GetSnapshotName()]; !ok {
+		return nil, fmt.Errorf("snapshot %s not found", req.GetSnapshotName())
+	}
+	delete(s.snapshots, req.GetSnapshotName())
+	return &rpcpb.RemoveSnapshotResponse{}, nil
+}
+
+func (s *fakeNetrunnerServer) GetSnapshotNames(context.Context, *rpcpb.GetSnapshotNamesRequest) (*rpcpb.GetSnapshotNamesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.snapshots))
+	for name := range s.snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &rpcpb.GetSnapshotNamesResponse{SnapshotNames: names}, nil
+}
+
+func TestNetrunnerBackend_Lifecycle(t *testing.T) {
+	nm, server := newNetrunnerTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "netrunner-lifecycle",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+		Ports:    &PortRange{Start: 20200, End: 20299},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, NetworkStatusRunning, network.Status)
+
+	// the netrunner node infos are converted into SDK nodes, ordered by name
+	require.Len(t, network.Nodes, 2)
+	for i, node := range network.Nodes {
+		name := fmt.Sprintf("node%d", i+1)
+		httpPort := 20200 + 2*i
+		assert.Equal(t, name, node.ID)
+		assert.Equal(t, "NodeID-"+name, node.NodeID)
+		assert.Equal(t, NodeTypeValidator, node.Type)
+		assert.Equal(t, NodeStatusHealthy, node.Status)
+		assert.Equal(t, fmt.Sprintf("http://127.0.0.1:%d", httpPort), node.Endpoint)
+		assert.Equal(t, httpPort+1, node.StakingPort)
+		assert.Equal(t, uint64(2000), node.StakeAmount)
+	}
+
+	node, err := nm.AddNode(ctx, network.ID, &NodeParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "node3", node.ID)
+	assert.Equal(t, "NodeID-node3", node.NodeID)
+	assert.Equal(t, "http://127.0.0.1:20204", node.Endpoint)
+	assert.Equal(t, 20205, node.StakingPort)
+
+	uris, err := nm.URIs(ctx, network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://127.0.0.1:20200", "http://127.0.0.1:20202", "http://127.0.0.1:20204"}, uris)
+
+	require.NoError(t, nm.RemoveNode(ctx, network.ID, "node3"))
+	assert.NotContains(t, server.nodes, "node3")
+
+	// snapshots are held by the netrunner server
+	require.NoError(t, nm.SaveSnapshot(ctx, network.ID, "lifecycle"))
+	assert.False(t, server.running)
+	snapshots, err := nm.ListSnapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lifecycle"}, snapshots)
+
+	loaded, err := nm.LoadSnapshot(ctx, "lifecycle")
+	require.NoError(t, err)
+	assert.Equal(t, NetworkStatusRunning, loaded.Status)
+	require.Len(t, loaded.Nodes, 2)
+	assert.Equal(t, "NodeID-node1", loaded.Nodes[0].NodeID)
+
+	require.NoError(t, nm.StopNetwork(ctx, loaded.ID))
+	assert.False(t, server.running)
+	require.NoError(t, nm.RemoveSnapshot("lifecycle"))
+	assert.Empty(t, server.snapshots)
+}
+
+func TestNetrunnerBackend_Partition(t *testing.T) {
+	nm, server := newNetrunnerTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "netrunner-partition",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+		Ports:    &PortRange{Start: 20000, End: 20099},
+	})
+	require.NoError(t, err)
+
+	// the staking ports come from the node configs, not from the endpoints
+	for _, node := range network.Nodes {
+		ports, ok := nodePorts(node)
+		require.True(t, ok)
+		assert.Equal(t, ports.HTTP+1, node.StakingPort)
+	}
+
+	err = nm.Partition(ctx, network.ID, [][]string{{"node1"}, {"node2"}})
+	require.ErrorIs(t, err, ErrRestartNodeConfig)
+	assert.Empty(t, server.restarts)
+
+	got, err := nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	for _, node := range got.Nodes {
+		assert.Equal(t, NodeStatusHealthy, node.Status)
+	}
+
+	// restarts without a node config still reach netrunner
+	upgrades := map[string]string{"chain": `{"precompileUpgrades":[]}`}
+	require.NoError(t, nm.backend.RestartNode(ctx, "node1", RestartOptions{UpgradeConfigs: upgrades}))
+	require.Len(t, server.restarts, 1)
+	assert.Equal(t, "node1", server.restarts[0].GetName())
+	assert.Equal(t, upgrades, server.restarts[0].GetUpgradeConfigs())
+}
+
+func TestNetrunnerBackend_PauseResume(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("node processes are found through /proc")
+	}
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("no sleep binary to stand in for a node")
+	}
+
+	nm, _ := newNetrunnerTestManager(t)
+	ctx := context.Background()
+
+	rootDataDir := t.TempDir()
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:       "netrunner-pause",
+		Type:       NetworkTypeLocal,
+		NumNodes:   1,
+		BinaryPath: sleep,
+		DataDir:    rootDataDir,
+		Ports:      &PortRange{Start: 20100, End: 20199},
+	})
+	require.NoError(t, err)
+
+	// a sleeping process stands in for the node
+	cmd := exec.Command(sleep, "60")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	nodeDir := filepath.Join(rootDataDir, "node1")
+	require.NoError(t, os.MkdirAll(nodeDir, 0o700))
+	processContext := fmt.Sprintf(`{"pid":%d}`, cmd.Process.Pid)
+	require.NoError(t, os.WriteFile(filepath.Join(nodeDir, processContextFile), []byte(processContext), 0o600))
+
+	processState := func() string {
+		t.Helper()
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", cmd.Process.Pid))
+		require.NoError(t, err)
+		// the state follows the parenthesized command name
+		fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+		return fields[0]
+	}
+
+	require.NoError(t, nm.PauseNode(ctx, network.ID, "node1"))
+	assert.Eventually(t, func() bool { return processState() == "T" }, 5*time.Second, 10*time.Millisecond)
+
+	// the paused state is kept by the manager, netrunner does not report it
+	got, err := nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusPaused, findNode(got.Nodes, "node1").Status)
+
+	require.NoError(t, nm.ResumeNode(ctx, network.ID, "node1"))
+	assert.Eventually(t, func() bool { return processState() != "T" }, 5*time.Second, 10*time.Millisecond)
+	got, err = nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NodeStatusBootstrapping, findNode(got.Nodes, "node1").Status)
+}
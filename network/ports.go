@@ -0,0 +1,209 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultPortRangeStart is the first port handed out to local nodes
+	DefaultPortRangeStart = 9650
+	// defaultPortRangeSize is the number of ports handed out to local nodes
+	defaultPortRangeSize = 1000
+
+	httpPortKey    = "http-port"
+	stakingPortKey = "staking-port"
+)
+
+// ErrPortInUse is returned when a port a node needs is bound by another process
+var ErrPortInUse = errors.New("port already in use")
+
+// NodePorts are the ports reserved for a node. The staking port always
+// follows the HTTP port.
+type NodePorts struct {
+	HTTP    int
+	Staking int
+}
+
+// portAllocator hands out HTTP and staking port pairs to the nodes of local
+// networks, so networks of the same manager never share ports and ports
+// bound by other processes are detected before a node starts
+type portAllocator struct {
+	mu sync.Mutex
+	// reserved maps each reserved port to the network holding it
+	reserved map[int]string
+	// inUse reports whether a port is bound by another process
+	inUse func(port int) bool
+}
+
+func newPortAllocator() *portAllocator {
+	return &portAllocator{
+		reserved: make(map[int]string),
+		inUse:    portInUse,
+	}
+}
+
+// reserve reserves a port pair for each of count nodes of a network, taking
+// the first free pairs of the range
+func (a *portAllocator) reserve(networkID string, count int, ports PortRange) ([]NodePorts, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var (
+		pairs []NodePorts
+		bound []int
+	)
+	for port := ports.Start; port < ports.End && len(pairs) < count; {
+		free := true
+		for _, p := range []int{port, port + 1} {
+			if _, ok := a.reserved[p]; ok {
+				free = false
+			} else if a.inUse(p) {
+				bound = append(bound, p)
+				free = false
+			}
+		}
+		if !free {
+			port++
+			continue
+		}
+		pairs = append(pairs, NodePorts{HTTP: port, Staking: port + 1})
+		port += 2
+	}
+
+	if len(pairs) < count {
+		err := fmt.Errorf("port range %d-%d has %d free port pairs, %d nodes need %d", ports.Start, ports.End, len(pairs), count, count)
+		if len(bound) > 0 {
+			err = fmt.Errorf("%w (%w: %s)", err, ErrPortInUse, joinPorts(bound))
+		}
+		return nil, err
+	}
+
+	a.claimLocked(networkID, pairs)
+	return pairs, nil
+}
+
+// reserveFixed reserves the given port pairs for a network, failing if any
+// of them is reserved by another network or bound by any process
+func (a *portAllocator) reserveFixed(networkID string, pairs []NodePorts) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var bound []int
+	for _, pair := range pairs {
+		for _, port := range []int{pair.HTTP, pair.Staking} {
+			if port <= 0 || port > maxPort {
+				return fmt.Errorf("invalid port %d", port)
+			}
+			if owner, ok := a.reserved[port]; ok && owner != networkID {
+				return fmt.Errorf("port %d is reserved by network %s", port, owner)
+			}
+			if a.inUse(port) {
+				bound = append(bound, port)
+			}
+		}
+	}
+	if len(bound) > 0 {
+		return fmt.Errorf("%w: %s", ErrPortInUse, joinPorts(bound))
+	}
+
+	a.claimLocked(networkID, pairs)
+	return nil
+}
+
+// claim records ports already held by a network, such as those of networks
+// reloaded from the registry
+func (a *portAllocator) claim(networkID string, pairs []NodePorts) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.claimLocked(networkID, pairs)
+}
+
+func (a *portAllocator) claimLocked(networkID string, pairs []NodePorts) {
+	for _, pair := range pairs {
+		a.reserved[pair.HTTP] = networkID
+		a.reserved[pair.Staking] = networkID
+	}
+}
+
+// release releases the given ports of a network, or all of its ports when
+// none are given
+func (a *portAllocator) release(networkID string, pairs ...NodePorts) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(pairs) == 0 {
+		for port, owner := range a.reserved {
+			if owner == networkID {
+				delete(a.reserved, port)
+			}
+		}
+		return
+	}
+	for _, pair := range pairs {
+		for _, port := range []int{pair.HTTP, pair.Staking} {
+			if a.reserved[port] == networkID {
+				delete(a.reserved, port)
+			}
+		}
+	}
+}
+
+// portInUse reports whether a TCP port is bound on any local address
+func portInUse(port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return true
+	}
+	_ = listener.Close()
+	return false
+}
+
+// nodePorts returns the ports of a node, derived from its endpoint
+func nodePorts(node *Node) (NodePorts, bool) {
+	endpoint, err := url.Parse(node.Endpoint)
+	if err != nil {
+		return NodePorts{}, false
+	}
+	port, err := strconv.Atoi(endpoint.Port())
+	if err != nil {
+		return NodePorts{}, false
+	}
+	staking := node.StakingPort
+	if staking == 0 {
+		staking = port + 1
+	}
+	return NodePorts{HTTP: port, Staking: staking}, true
+}
+
+// networkPorts returns the ports of the nodes of a network
+func networkPorts(network *Network) []NodePorts {
+	pairs := make([]NodePorts, 0, len(network.Nodes))
+	for _, node := range network.Nodes {
+		if pair, ok := nodePorts(node); ok {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+// nodeEndpoint returns the local endpoint of a node listening on the given ports
+func nodeEndpoint(ports NodePorts) string {
+	return "http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(ports.HTTP))
+}
+
+func joinPorts(ports []int) string {
+	s := make([]string, len(ports))
+	for i, port := range ports {
+		s[i] = strconv.Itoa(port)
+	}
+	return strings.Join(s, ", ")
+}
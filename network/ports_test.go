@@ -0,0 +1,169 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/luxfi/netrunner-sdk/rpcpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortAllocator(t *testing.T) {
+	allocator := newPortAllocator()
+	bound := map[int]bool{9652: true}
+	allocator.inUse = func(port int) bool { return bound[port] }
+
+	ports, err := allocator.reserve("net1", 2, PortRange{Start: 9650, End: 9659})
+	require.NoError(t, err)
+	// 9652 is bound, so the second pair starts after it
+	assert.Equal(t, []NodePorts{{HTTP: 9650, Staking: 9651}, {HTTP: 9653, Staking: 9654}}, ports)
+
+	ports, err = allocator.reserve("net2", 2, PortRange{Start: 9650, End: 9659})
+	require.NoError(t, err)
+	assert.Equal(t, []NodePorts{{HTTP: 9655, Staking: 9656}, {HTTP: 9657, Staking: 9658}}, ports)
+
+	_, err = allocator.reserve("net3", 1, PortRange{Start: 9650, End: 9659})
+	assert.ErrorIs(t, err, ErrPortInUse)
+	assert.ErrorContains(t, err, "port range 9650-9659 has 0 free port pairs, 1 nodes need 1")
+
+	err = allocator.reserveFixed("net3", []NodePorts{{HTTP: 9651, Staking: 9652}})
+	assert.ErrorContains(t, err, "port 9651 is reserved by network net1")
+	err = allocator.reserveFixed("net3", []NodePorts{{HTTP: 9652, Staking: 9653}})
+	assert.ErrorContains(t, err, "reserved by network net1")
+
+	allocator.release("net1", NodePorts{HTTP: 9653, Staking: 9654})
+	err = allocator.reserveFixed("net3", []NodePorts{{HTTP: 9652, Staking: 9653}})
+	assert.ErrorIs(t, err, ErrPortInUse)
+	assert.ErrorContains(t, err, "9652")
+	require.NoError(t, allocator.reserveFixed("net3", []NodePorts{{HTTP: 9653, Staking: 9654}}))
+
+	allocator.release("net2")
+	ports, err = allocator.reserve("net4", 2, PortRange{Start: 9650, End: 9659})
+	require.NoError(t, err)
+	assert.Equal(t, []NodePorts{{HTTP: 9655, Staking: 9656}, {HTTP: 9657, Staking: 9658}}, ports)
+}
+
+func TestPortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	assert.True(t, portInUse(port))
+
+	require.NoError(t, listener.Close())
+	assert.False(t, portInUse(port))
+}
+
+func TestNetworkManager_PortAllocation(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	first, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "first",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+		Ports:    &PortRange{Start: 20000, End: 20099},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:20000", findNode(first.Nodes, "node1").Endpoint)
+	assert.Equal(t, "http://127.0.0.1:20002", findNode(first.Nodes, "node2").Endpoint)
+	assert.JSONEq(t, `{"http-port":20002,"staking-port":20003}`, backend.configs["node2"])
+
+	// a second network on the same range gets the next free ports
+	second, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "second",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+		Ports:    &PortRange{Start: 20000, End: 20099},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:20004", second.Nodes[0].Endpoint)
+
+	node, err := nm.AddNode(ctx, first.ID, &NodeParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:20006", node.Endpoint)
+	require.NoError(t, nm.RemoveNode(ctx, first.ID, node.ID))
+
+	_, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "fixed",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+		HTTPPort: 20004,
+	})
+	assert.ErrorContains(t, err, "port 20004 is reserved by network "+second.ID)
+
+	require.NoError(t, nm.DeleteNetwork(ctx, first.ID))
+	fixed, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "fixed",
+		Type:     NetworkTypeLocal,
+		NumNodes: 2,
+		HTTPPort: 20000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:20002", findNode(fixed.Nodes, "node2").Endpoint)
+}
+
+func TestNetworkManager_PortInUse(t *testing.T) {
+	nm, backend := newTestManager(t)
+	nm.ports.inUse = portInUse
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	_, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "bound",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+		HTTPPort: port,
+	})
+	assert.ErrorIs(t, err, ErrPortInUse)
+	assert.Zero(t, backend.starts)
+	assert.Empty(t, nm.ListNetworks())
+
+	_, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "bound",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+		Ports:    &PortRange{Start: port, End: port + 1},
+	})
+	assert.ErrorIs(t, err, ErrPortInUse)
+	assert.Zero(t, backend.starts)
+}
+
+func TestNetworkManager_AddNodeReleasesPorts(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "add-node",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+		Ports:    &PortRange{Start: 20000, End: 20099},
+	})
+	require.NoError(t, err)
+
+	// the backend already runs a node with that name
+	backend.nodes["taken"] = &rpcpb.NodeInfo{Name: "taken"}
+	_, err = nm.AddNode(ctx, network.ID, &NodeParams{Name: "taken"})
+	assert.ErrorContains(t, err, "already exists")
+	assert.NotContains(t, nm.ports.reserved, 20002)
+
+	backend.hideAdded = true
+	_, err = nm.AddNode(ctx, network.ID, &NodeParams{})
+	assert.ErrorContains(t, err, "missing from netrunner cluster info")
+	assert.NotContains(t, nm.ports.reserved, 20002)
+
+	backend.hideAdded = false
+	node, err := nm.AddNode(ctx, network.ID, &NodeParams{Name: "node3"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:20002", node.Endpoint)
+	assert.Equal(t, network.ID, nm.ports.reserved[20002])
+	assert.Equal(t, network.ID, nm.ports.reserved[20003])
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !windows
+
+package network
+
+import (
+	"os"
+	"syscall"
+)
+
+// suspendProcess stops a process with SIGSTOP
+func suspendProcess(process *os.Process) error {
+	return process.Signal(syscall.SIGSTOP)
+}
+
+// resumeProcess continues a process stopped by suspendProcess
+func resumeProcess(process *os.Process) error {
+	return process.Signal(syscall.SIGCONT)
+}
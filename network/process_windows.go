@@ -0,0 +1,22 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build windows
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// suspendProcess is not supported, windows has no SIGSTOP
+func suspendProcess(*os.Process) error {
+	return fmt.Errorf("pausing a node process on windows: %w", errors.ErrUnsupported)
+}
+
+// resumeProcess is not supported, windows has no SIGCONT
+func resumeProcess(*os.Process) error {
+	return fmt.Errorf("resuming a node process on windows: %w", errors.ErrUnsupported)
+}
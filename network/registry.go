@@ -0,0 +1,133 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/sdk/constants"
+)
+
+const registryFileExt = ".json"
+
+// registry persists networks as one JSON record per network so that
+// separate processes sharing a data dir see the same networks
+type registry struct {
+	dir    string
+	logger log.Logger
+}
+
+// newRegistry creates the registry directory if needed
+func newRegistry(dir string, logger log.Logger) (*registry, error) {
+	if err := os.MkdirAll(dir, constants.UserOnlyWriteReadExecPerms); err != nil {
+		return nil, fmt.Errorf("failed to create network registry directory %s: %w", dir, err)
+	}
+	return &registry{dir: dir, logger: logger}, nil
+}
+
+// path returns the record file of a network
+func (r *registry) path(networkID string) string {
+	return filepath.Join(r.dir, networkID+registryFileExt)
+}
+
+// save writes the network record atomically
+func (r *registry) save(network *Network) error {
+	data, err := json.MarshalIndent(network, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal network %s: %w", network.ID, err)
+	}
+
+	tmpFile, err := os.CreateTemp(r.dir, network.ID+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create network record for %s: %w", network.ID, err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write network record for %s: %w", network.ID, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write network record for %s: %w", network.ID, err)
+	}
+	if err := os.Rename(tmpPath, r.path(network.ID)); err != nil {
+		return fmt.Errorf("failed to write network record for %s: %w", network.ID, err)
+	}
+	return nil
+}
+
+// remove deletes the network record
+func (r *registry) remove(networkID string) error {
+	if err := os.Remove(r.path(networkID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove network record for %s: %w", networkID, err)
+	}
+	return nil
+}
+
+// exists reports whether a network record exists
+func (r *registry) exists(networkID string) bool {
+	_, err := os.Stat(r.path(networkID))
+	return err == nil
+}
+
+// load reads all network records. Records that cannot be read are logged
+// and skipped, so one corrupt record does not hide the other networks.
+func (r *registry) load() ([]*Network, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read network registry %s: %w", r.dir, err)
+	}
+
+	networks := make([]*Network, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), registryFileExt) {
+			continue
+		}
+
+		network, err := readRecord(filepath.Join(r.dir, entry.Name()))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// deleted while the registry was being read
+			continue
+		case err != nil:
+			r.logger.Warn("skipping unreadable network record", "record", entry.Name(), "error", err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// get reads a single network record, returning nil if it does not exist
+func (r *registry) get(networkID string) (*Network, error) {
+	network, err := readRecord(r.path(networkID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return network, err
+}
+
+// readRecord reads and decodes a network record file
+func readRecord(path string) (*Network, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network record %s: %w", filepath.Base(path), err)
+	}
+
+	var network Network
+	if err := json.Unmarshal(data, &network); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal network record %s: %w", filepath.Base(path), err)
+	}
+	return &network, nil
+}
@@ -0,0 +1,495 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxfi/netrunner-sdk/rpcpb"
+	"github.com/luxfi/sdk/constants"
+)
+
+const (
+	// snapshotRecordFile holds the metadata of a snapshot inside its directory
+	snapshotRecordFile = "network.json"
+	// snapshotDataDir holds the copied node data dirs of a snapshot taken without netrunner
+	snapshotDataDir = "data"
+	// restoredDataDirs holds the data dirs of networks loaded from snapshots,
+	// inside the configured data dir
+	restoredDataDirs = "networks"
+)
+
+// ErrNoSnapshotsDir is returned when a snapshot needs local storage but no SnapshotsDir is configured
+var ErrNoSnapshotsDir = errors.New("no snapshots directory configured")
+
+// snapshotRecord is the metadata kept for every snapshot
+type snapshotRecord struct {
+	Name    string   `json:"name"`
+	Network *Network `json:"network"`
+	// Netrunner is set when the snapshot state is held by the netrunner server
+	Netrunner bool      `json:"netrunner"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SaveSnapshot saves the state of a network under the given name. With a
+// netrunner backend the snapshot is taken by netrunner, otherwise the node
+// data dirs are copied into the snapshots directory. In both cases the
+// network is stopped once the snapshot is taken.
+func (nm *NetworkManager) SaveSnapshot(ctx context.Context, networkID, name string) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	network, err := nm.lookup(networkID)
+	if err != nil {
+		return err
+	}
+
+	if nm.backend == nil {
+		return ErrNoBackend
+	}
+
+	exists, err := nm.snapshotExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("snapshot %s already exists", name)
+	}
+
+	snapshotter, useNetrunner := nm.backend.(SnapshotBackend)
+	if useNetrunner {
+		if network.Status != NetworkStatusRunning {
+			return fmt.Errorf("network %s is not running", networkID)
+		}
+		if err := snapshotter.SaveSnapshot(ctx, name); err != nil {
+			return fmt.Errorf("failed to save snapshot %s of network %s: %w", name, networkID, err)
+		}
+		nm.mu.Lock()
+		for _, node := range network.Nodes {
+			node.Status = NodeStatusStopped
+		}
+		nm.mu.Unlock()
+		nm.setStatus(network, NetworkStatusStopped)
+	} else {
+		if err := nm.copySnapshot(ctx, network, name); err != nil {
+			return err
+		}
+	}
+
+	nm.mu.Lock()
+	network.Snapshots = append(network.Snapshots, name)
+	record := &snapshotRecord{
+		Name:      name,
+		Network:   network.clone(),
+		Netrunner: useNetrunner,
+		CreatedAt: time.Now(),
+	}
+	nm.mu.Unlock()
+
+	if nm.snapshotsDir() != "" {
+		if err := nm.writeSnapshotRecord(record); err != nil {
+			return err
+		}
+	}
+	return nm.save(network)
+}
+
+// copySnapshot stops the network and copies its data dir into the snapshot
+// directory, the caller must hold opMu
+func (nm *NetworkManager) copySnapshot(ctx context.Context, network *Network, name string) error {
+	snapshotsDir := nm.snapshotsDir()
+	if snapshotsDir == "" {
+		return ErrNoSnapshotsDir
+	}
+	if network.RootDataDir == "" {
+		return fmt.Errorf("network %s has no data dir to snapshot", network.ID)
+	}
+
+	if network.Status == NetworkStatusRunning {
+		if err := nm.stopNetwork(ctx, network.ID); err != nil {
+			return err
+		}
+	}
+
+	snapshotPath := filepath.Join(snapshotsDir, name)
+	if err := copyDir(ctx, network.RootDataDir, filepath.Join(snapshotPath, snapshotDataDir)); err != nil {
+		_ = os.RemoveAll(snapshotPath)
+		return fmt.Errorf("failed to save snapshot %s of network %s: %w", name, network.ID, err)
+	}
+	return nil
+}
+
+// LoadSnapshot starts a new network from a saved snapshot
+func (nm *NetworkManager) LoadSnapshot(ctx context.Context, name string) (*Network, error) {
+	if err := validateSnapshotName(name); err != nil {
+		return nil, err
+	}
+
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	if nm.backend == nil {
+		return nil, ErrNoBackend
+	}
+
+	record, err := nm.readSnapshotRecord(name)
+	if err != nil {
+		return nil, err
+	}
+	snapshotter, ok := nm.backend.(SnapshotBackend)
+	useNetrunner := ok && (record == nil || record.Netrunner)
+	if record == nil && !useNetrunner {
+		return nil, fmt.Errorf("snapshot %s not found", name)
+	}
+
+	nm.mu.Lock()
+	network := &Network{
+		ID:        nm.newNetworkID(),
+		Name:      name,
+		Type:      NetworkTypeLocal,
+		Status:    NetworkStatusCreating,
+		CreatedAt: time.Now(),
+	}
+	nm.mu.Unlock()
+	if record != nil && record.Network != nil {
+		network.Name = record.Network.Name
+		network.Type = record.Network.Type
+		network.BinaryPath = record.Network.BinaryPath
+		network.Ports = record.Network.Ports
+		network.EnableStaking = record.Network.EnableStaking
+		network.EnableMonitoring = record.Network.EnableMonitoring
+	}
+
+	var info *rpcpb.ClusterInfo
+	if useNetrunner {
+		info, err = snapshotter.LoadSnapshot(ctx, name, network.BinaryPath)
+	} else {
+		info, err = nm.restoreSnapshot(ctx, network, record)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot %s: %w", name, err)
+	}
+
+	nm.mu.Lock()
+	nm.applyClusterInfo(network, info)
+	network.Status = NetworkStatusRunning
+	nm.networks[network.ID] = network
+	nm.mu.Unlock()
+	nm.ports.claim(network.ID, networkPorts(network))
+	nm.publish(network, "")
+
+	if err := nm.save(network); err != nil {
+		return nil, err
+	}
+	return network.clone(), nil
+}
+
+// restoreSnapshot copies the snapshot data into a fresh data dir under the
+// configured data dir and starts the network on it, with ports reserved like
+// for a new network. The data dir is removed with the network.
+func (nm *NetworkManager) restoreSnapshot(ctx context.Context, network *Network, record *snapshotRecord) (*rpcpb.ClusterInfo, error) {
+	numNodes := 0
+	if record.Network != nil {
+		numNodes = len(record.Network.Nodes)
+	}
+	if numNodes == 0 {
+		return nil, errors.New("snapshot has no nodes")
+	}
+
+	parentDir := filepath.Join(nm.dataDir(), restoredDataDirs)
+	if err := os.MkdirAll(parentDir, constants.UserOnlyWriteReadExecPerms); err != nil {
+		return nil, fmt.Errorf("failed to create data directory %s: %w", parentDir, err)
+	}
+	rootDataDir, err := os.MkdirTemp(parentDir, record.Name+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	started := false
+	defer func() {
+		if !started {
+			nm.ports.release(network.ID)
+			_ = os.RemoveAll(rootDataDir)
+		}
+	}()
+
+	snapshotData := filepath.Join(nm.snapshotsDir(), record.Name, snapshotDataDir)
+	if err := copyDir(ctx, snapshotData, rootDataDir); err != nil {
+		return nil, err
+	}
+	network.RootDataDir = rootDataDir
+	network.ManagedDataDir = true
+
+	// the nodes of the snapshot may still run on their old ports
+	ports, err := nm.ports.reserve(network.ID, numNodes, nm.portRange(network.Ports))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve ports: %w", err)
+	}
+	opts, err := nm.startOptions(network, numNodes, "")
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]NodeParams, numNodes)
+	for i, node := range record.Network.Nodes {
+		nodes[i].Name = node.ID
+	}
+	if opts.CustomNodeConfigs, err = customNodeConfigs(nodes, ports); err != nil {
+		return nil, err
+	}
+
+	info, err := nm.backend.Start(ctx, network.BinaryPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	started = true
+	return info, nil
+}
+
+// ListSnapshots returns the names of all saved snapshots
+func (nm *NetworkManager) ListSnapshots() ([]string, error) {
+	names := make(map[string]struct{})
+
+	if snapshotter, ok := nm.backend.(SnapshotBackend); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.ANRRequestTimeout)
+		defer cancel()
+		netrunnerNames, err := snapshotter.SnapshotNames(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		for _, name := range netrunnerNames {
+			names[name] = struct{}{}
+		}
+	}
+
+	if snapshotsDir := nm.snapshotsDir(); snapshotsDir != "" {
+		entries, err := os.ReadDir(snapshotsDir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(snapshotsDir, entry.Name(), snapshotRecordFile)); err == nil {
+				names[entry.Name()] = struct{}{}
+			}
+		}
+	}
+
+	snapshots := make([]string, 0, len(names))
+	for name := range names {
+		snapshots = append(snapshots, name)
+	}
+	sort.Strings(snapshots)
+	return snapshots, nil
+}
+
+// RemoveSnapshot removes a saved snapshot
+func (nm *NetworkManager) RemoveSnapshot(name string) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+
+	nm.opMu.Lock()
+	defer nm.opMu.Unlock()
+
+	record, err := nm.readSnapshotRecord(name)
+	if err != nil {
+		return err
+	}
+
+	snapshotter, ok := nm.backend.(SnapshotBackend)
+	if ok && (record == nil || record.Netrunner) {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.ANRRequestTimeout)
+		defer cancel()
+		if err := snapshotter.RemoveSnapshot(ctx, name); err != nil {
+			return fmt.Errorf("failed to remove snapshot %s: %w", name, err)
+		}
+	} else if record == nil {
+		return fmt.Errorf("snapshot %s not found", name)
+	}
+
+	if record != nil {
+		if err := os.RemoveAll(filepath.Join(nm.snapshotsDir(), name)); err != nil {
+			return fmt.Errorf("failed to remove snapshot %s: %w", name, err)
+		}
+	}
+
+	nm.mu.Lock()
+	var changed []*Network
+	for _, network := range nm.networks {
+		if i := slices.Index(network.Snapshots, name); i >= 0 {
+			network.Snapshots = slices.Delete(slices.Clone(network.Snapshots), i, i+1)
+			changed = append(changed, network)
+		}
+	}
+	nm.mu.Unlock()
+
+	for _, network := range changed {
+		if err := nm.save(network); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotExists reports whether a snapshot with the given name was saved
+func (nm *NetworkManager) snapshotExists(ctx context.Context, name string) (bool, error) {
+	record, err := nm.readSnapshotRecord(name)
+	if err != nil || record != nil {
+		return record != nil, err
+	}
+
+	if snapshotter, ok := nm.backend.(SnapshotBackend); ok {
+		names, err := snapshotter.SnapshotNames(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		return slices.Contains(names, name), nil
+	}
+	return false, nil
+}
+
+// dataDir returns the configured data dir, or the temporary directory when
+// none is configured
+func (nm *NetworkManager) dataDir() string {
+	if nm.config == nil || nm.config.DataDir == "" {
+		return os.TempDir()
+	}
+	dataDir := nm.config.DataDir
+	if strings.HasPrefix(dataDir, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataDir = filepath.Join(home, dataDir[1:])
+		}
+	}
+	return dataDir
+}
+
+// snapshotsDir returns the configured snapshots directory
+func (nm *NetworkManager) snapshotsDir() string {
+	if nm.config == nil {
+		return ""
+	}
+	return nm.config.SnapshotsDir
+}
+
+// readSnapshotRecord reads the metadata of a snapshot, returning nil if it does not exist
+func (nm *NetworkManager) readSnapshotRecord(name string) (*snapshotRecord, error) {
+	snapshotsDir := nm.snapshotsDir()
+	if snapshotsDir == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(snapshotsDir, name, snapshotRecordFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", name, err)
+	}
+
+	var record snapshotRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot %s: %w", name, err)
+	}
+	return &record, nil
+}
+
+// writeSnapshotRecord writes the metadata of a snapshot
+func (nm *NetworkManager) writeSnapshotRecord(record *snapshotRecord) error {
+	snapshotPath := filepath.Join(nm.snapshotsDir(), record.Name)
+	if err := os.MkdirAll(snapshotPath, constants.UserOnlyWriteReadExecPerms); err != nil {
+		return fmt.Errorf("failed to create snapshot directory %s: %w", snapshotPath, err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot %s: %w", record.Name, err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshotPath, snapshotRecordFile), data, constants.WriteReadUserOnlyPerms); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %w", record.Name, err)
+	}
+	return nil
+}
+
+// validateSnapshotName rejects names that cannot be used as a directory name
+func validateSnapshotName(name string) error {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
+
+// copyDir recursively copies the src directory into dst, keeping file
+// modes and symlinks
+func copyDir(ctx context.Context, src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|constants.UserOnlyWriteReadExecPerms)
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case entry.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			// sockets and pipes are runtime state, not node data
+			return nil
+		}
+	})
+}
+
+// copyFile copies a regular file
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
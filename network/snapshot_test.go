@@ -0,0 +1,232 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxfi/log"
+	"github.com/luxfi/netrunner-sdk/rpcpb"
+	"github.com/luxfi/sdk/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSnapshotBackend adds netrunner style snapshots to fakeBackend
+type fakeSnapshotBackend struct {
+	*fakeBackend
+	snapshots map[string]int
+	execPaths []string
+}
+
+func (f *fakeSnapshotBackend) SaveSnapshot(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.running {
+		return fmt.Errorf("network not running")
+	}
+	f.snapshots[name] = len(f.nodes)
+	f.running = false
+	return nil
+}
+
+func (f *fakeSnapshotBackend) LoadSnapshot(ctx context.Context, name string, execPath string) (*rpcpb.ClusterInfo, error) {
+	f.mu.Lock()
+	numNodes, ok := f.snapshots[name]
+	f.execPaths = append(f.execPaths, execPath)
+	f.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("snapshot %s not found", name)
+	}
+	return f.Start(ctx, execPath, StartOptions{NumNodes: numNodes})
+}
+
+func (f *fakeSnapshotBackend) RemoveSnapshot(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.snapshots[name]; !ok {
+		return fmt.Errorf("snapshot %s not found", name)
+	}
+	delete(f.snapshots, name)
+	return nil
+}
+
+func (f *fakeSnapshotBackend) SnapshotNames(context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(f.snapshots))
+	for name := range f.snapshots {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func TestNetworkManager_CopySnapshots(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	cfg := &config.NetworkConfig{StakeAmount: 2000, SnapshotsDir: t.TempDir(), DataDir: dataDir}
+	nm, err := NewNetworkManagerWithBackend(cfg, newFakeBackend(), log.NewNoOpLogger())
+	require.NoError(t, err)
+
+	rootDataDir := t.TempDir()
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:       "funded",
+		Type:       NetworkTypeLocal,
+		NumNodes:   2,
+		BinaryPath: "/usr/local/bin/luxd",
+		DataDir:    rootDataDir,
+	})
+	require.NoError(t, err)
+
+	stateFile := filepath.Join(rootDataDir, "node1", "db", "state")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stateFile), 0o700))
+	require.NoError(t, os.WriteFile(stateFile, []byte("funded"), 0o600))
+	require.NoError(t, os.Symlink("db", filepath.Join(rootDataDir, "node1", "current")))
+
+	require.NoError(t, nm.SaveSnapshot(ctx, network.ID, "deployed"))
+
+	saved, err := nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NetworkStatusStopped, saved.Status)
+	assert.Equal(t, []string{"deployed"}, saved.Snapshots)
+
+	snapshots, err := nm.ListSnapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deployed"}, snapshots)
+
+	err = nm.SaveSnapshot(ctx, network.ID, "deployed")
+	assert.ErrorContains(t, err, "already exists")
+	err = nm.SaveSnapshot(ctx, network.ID, "../escape")
+	assert.ErrorContains(t, err, "invalid snapshot name")
+
+	// later changes to the original network do not leak into the snapshot
+	require.NoError(t, os.WriteFile(stateFile, []byte("spent"), 0o600))
+	require.NoError(t, nm.StartNetwork(ctx, network.ID))
+
+	restored, err := nm.LoadSnapshot(ctx, "deployed")
+	require.NoError(t, err)
+	assert.NotEqual(t, network.ID, restored.ID)
+	assert.Equal(t, "funded", restored.Name)
+	assert.Equal(t, "/usr/local/bin/luxd", restored.BinaryPath)
+	assert.Equal(t, NetworkStatusRunning, restored.Status)
+	assert.Len(t, restored.Nodes, 2)
+
+	// the restored nodes get their own ports, the original network still runs
+	for _, node := range restored.Nodes {
+		ports, ok := nodePorts(node)
+		require.True(t, ok)
+		assert.Equal(t, restored.ID, nm.ports.reserved[ports.HTTP])
+		assert.Equal(t, restored.ID, nm.ports.reserved[ports.Staking])
+		for _, original := range network.Nodes {
+			assert.NotEqual(t, original.Endpoint, node.Endpoint)
+		}
+	}
+	require.NoError(t, nm.StopNetwork(ctx, network.ID))
+	require.NotEqual(t, rootDataDir, restored.RootDataDir)
+	assert.Equal(t, filepath.Join(dataDir, restoredDataDirs), filepath.Dir(restored.RootDataDir))
+
+	data, err := os.ReadFile(filepath.Join(restored.RootDataDir, "node1", "db", "state"))
+	require.NoError(t, err)
+	assert.Equal(t, "funded", string(data))
+	link, err := os.Readlink(filepath.Join(restored.RootDataDir, "node1", "current"))
+	require.NoError(t, err)
+	assert.Equal(t, "db", link)
+
+	_, err = nm.LoadSnapshot(ctx, "missing")
+	assert.ErrorContains(t, err, "not found")
+
+	require.NoError(t, nm.RemoveSnapshot("deployed"))
+	snapshots, err = nm.ListSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+	assert.NoDirExists(t, filepath.Join(cfg.SnapshotsDir, "deployed"))
+
+	saved, err = nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Empty(t, saved.Snapshots)
+
+	assert.ErrorContains(t, nm.RemoveSnapshot("deployed"), "not found")
+
+	// the data dir of the restored network goes away with it, the one given
+	// by the caller is kept
+	require.NoError(t, nm.DeleteNetwork(ctx, restored.ID))
+	assert.NoDirExists(t, restored.RootDataDir)
+	require.NoError(t, nm.DeleteNetwork(ctx, network.ID))
+	assert.DirExists(t, rootDataDir)
+}
+
+func TestNetworkManager_CopySnapshotsNeedDir(t *testing.T) {
+	nm, _ := newTestManager(t)
+	ctx := context.Background()
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "no-dir",
+		Type:     NetworkTypeLocal,
+		NumNodes: 1,
+		DataDir:  t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, nm.SaveSnapshot(ctx, network.ID, "snap"), ErrNoSnapshotsDir)
+}
+
+func TestNetworkManager_NetrunnerSnapshots(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeSnapshotBackend{
+		fakeBackend: newFakeBackend(),
+		snapshots:   make(map[string]int),
+	}
+	cfg := &config.NetworkConfig{StakeAmount: 2000, SnapshotsDir: t.TempDir()}
+	nm, err := NewNetworkManagerWithBackend(cfg, backend, log.NewNoOpLogger())
+	require.NoError(t, err)
+
+	network, err := nm.CreateNetwork(ctx, &NetworkParams{
+		Name:       "netrunner",
+		Type:       NetworkTypeLocal,
+		NumNodes:   3,
+		BinaryPath: "/usr/local/bin/luxd",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, nm.SaveSnapshot(ctx, network.ID, "bootstrapped"))
+	assert.Equal(t, 3, backend.snapshots["bootstrapped"])
+
+	saved, err := nm.GetNetwork(network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, NetworkStatusStopped, saved.Status)
+
+	err = nm.SaveSnapshot(ctx, network.ID, "stopped")
+	assert.ErrorContains(t, err, "not running")
+
+	// snapshots taken directly through netrunner are listed too
+	backend.snapshots["external"] = 1
+	snapshots, err := nm.ListSnapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bootstrapped", "external"}, snapshots)
+
+	restored, err := nm.LoadSnapshot(ctx, "bootstrapped")
+	require.NoError(t, err)
+	assert.Equal(t, "netrunner", restored.Name)
+	assert.Equal(t, NetworkStatusRunning, restored.Status)
+	assert.Len(t, restored.Nodes, 3)
+	assert.Equal(t, []string{"/usr/local/bin/luxd"}, backend.execPaths)
+
+	external, err := nm.LoadSnapshot(ctx, "external")
+	require.NoError(t, err)
+	assert.Equal(t, "external", external.Name)
+	assert.Len(t, external.Nodes, 1)
+
+	require.NoError(t, nm.RemoveSnapshot("bootstrapped"))
+	require.NoError(t, nm.RemoveSnapshot("external"))
+	assert.Empty(t, backend.snapshots)
+	snapshots, err = nm.ListSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	assert.ErrorContains(t, nm.RemoveSnapshot("external"), "not found")
+}
@@ -0,0 +1,283 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const maxPort = 65535
+
+// Topology is the file format describing a network, its nodes and the
+// chains to create on it. Relative paths are resolved against the
+// directory of the topology file.
+type Topology struct {
+	Name             string          `json:"name" yaml:"name"`
+	Type             NetworkType     `json:"type,omitempty" yaml:"type,omitempty"`
+	BinaryPath       string          `json:"binaryPath,omitempty" yaml:"binaryPath,omitempty"`
+	DataDir          string          `json:"dataDir,omitempty" yaml:"dataDir,omitempty"`
+	LogLevel         string          `json:"logLevel,omitempty" yaml:"logLevel,omitempty"`
+	NumNodes         int             `json:"numNodes,omitempty" yaml:"numNodes,omitempty"`
+	Ports            *PortRange      `json:"ports,omitempty" yaml:"ports,omitempty"`
+	EnableStaking    bool            `json:"enableStaking,omitempty" yaml:"enableStaking,omitempty"`
+	EnableMonitoring bool            `json:"enableMonitoring,omitempty" yaml:"enableMonitoring,omitempty"`
+	Nodes            []TopologyNode  `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Chains           []TopologyChain `json:"chains,omitempty" yaml:"chains,omitempty"`
+}
+
+// TopologyNode describes a node of the topology
+type TopologyNode struct {
+	Name        string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Type        NodeType               `json:"type,omitempty" yaml:"type,omitempty"`
+	StakeAmount uint64                 `json:"stakeAmount,omitempty" yaml:"stakeAmount,omitempty"`
+	Flags       map[string]interface{} `json:"flags,omitempty" yaml:"flags,omitempty"`
+}
+
+// TopologyChain describes a chain to create on the network
+type TopologyChain struct {
+	Name       string           `json:"name" yaml:"name"`
+	Type       string           `json:"type,omitempty" yaml:"type,omitempty"`
+	VM         string           `json:"vm" yaml:"vm"`
+	ChainID    uint64           `json:"chainId,omitempty" yaml:"chainId,omitempty"`
+	Genesis    string           `json:"genesis,omitempty" yaml:"genesis,omitempty"`
+	Config     string           `json:"config,omitempty" yaml:"config,omitempty"`
+	Validators []ChainValidator `json:"validators,omitempty" yaml:"validators,omitempty"`
+}
+
+// PortRange is an inclusive range of ports
+type PortRange struct {
+	Start int `json:"start" yaml:"start"`
+	End   int `json:"end" yaml:"end"`
+}
+
+// Size returns the number of ports in the range
+func (r PortRange) Size() int {
+	return r.End - r.Start + 1
+}
+
+// ChainValidator is a validator of a chain
+type ChainValidator struct {
+	NodeID string `json:"nodeId" yaml:"nodeId"`
+	Weight uint64 `json:"weight" yaml:"weight"`
+}
+
+// LoadTopology reads a YAML or JSON topology file, validates it and
+// returns the parameters to create the network with. The chains of the
+// topology are returned in NetworkParams.ChainConfigs with their genesis
+// and config files loaded; blockchain.CreateParamsFromChainConfigs turns
+// them into blockchain parameters.
+func LoadTopology(path string) (*NetworkParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology %s: %w", path, err)
+	}
+
+	var topology Topology
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&topology)
+	default:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&topology)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topology %s: %w", path, err)
+	}
+
+	params, err := topology.params(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology %s: %w", path, err)
+	}
+	return params, nil
+}
+
+// params validates the topology and converts it into network parameters.
+// All validation errors are reported together.
+func (t *Topology) params(baseDir string) (*NetworkParams, error) {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if t.Name == "" {
+		fail("name: required")
+	}
+
+	networkType := t.Type
+	if networkType == "" {
+		networkType = NetworkTypeLocal
+	}
+	switch networkType {
+	case NetworkTypeMainnet, NetworkTypeTestnet, NetworkTypeLocal, NetworkTypeCustom:
+	default:
+		fail("type: unsupported network type %s", t.Type)
+	}
+
+	numNodes := t.NumNodes
+	switch {
+	case len(t.Nodes) > 0 && numNodes != 0 && numNodes != len(t.Nodes):
+		fail("numNodes: %d does not match the %d nodes listed", numNodes, len(t.Nodes))
+	case len(t.Nodes) > 0:
+		numNodes = len(t.Nodes)
+	case numNodes <= 0:
+		fail("nodes: at least one node is required")
+	}
+
+	nodes := make([]NodeParams, 0, len(t.Nodes))
+	nodeNames := make(map[string]bool, len(t.Nodes))
+	for i, node := range t.Nodes {
+		name := node.Name
+		if name == "" {
+			name = fmt.Sprintf("node%d", i+1)
+		}
+		if nodeNames[name] {
+			fail("nodes[%d].name: duplicate node name %s", i, name)
+		}
+		nodeNames[name] = true
+
+		switch node.Type {
+		case "", NodeTypeValidator, NodeTypeAPI, NodeTypeFull, NodeTypeLight:
+		default:
+			fail("nodes[%d].type: unsupported node type %s", i, node.Type)
+		}
+
+		nodes = append(nodes, NodeParams{
+			Name:        name,
+			Type:        node.Type,
+			StakeAmount: node.StakeAmount,
+			Flags:       node.Flags,
+		})
+	}
+
+	params := &NetworkParams{
+		Name:             t.Name,
+		Type:             networkType,
+		NumNodes:         numNodes,
+		BinaryPath:       resolveBinaryPath(baseDir, t.BinaryPath),
+		DataDir:          resolvePath(baseDir, t.DataDir),
+		LogLevel:         t.LogLevel,
+		EnableStaking:    t.EnableStaking,
+		EnableMonitoring: t.EnableMonitoring,
+		Nodes:            nodes,
+	}
+
+	if t.Ports != nil {
+		ports := *t.Ports
+		switch {
+		case ports.Start <= 0 || ports.End > maxPort:
+			fail("ports: range %d-%d is outside 1-%d", ports.Start, ports.End, maxPort)
+		case ports.End < ports.Start:
+			fail("ports: end %d is before start %d", ports.End, ports.Start)
+		case numNodes > 0 && ports.Size() < 2*numNodes:
+			fail("ports: range %d-%d has %d ports, %d nodes need %d", ports.Start, ports.End, ports.Size(), numNodes, 2*numNodes)
+		default:
+			params.Ports = &ports
+			params.HTTPPort = ports.Start
+			params.StakingPort = ports.Start + 1
+		}
+	}
+
+	chainNames := make(map[string]bool, len(t.Chains))
+	for i, chain := range t.Chains {
+		if chain.Name == "" {
+			fail("chains[%d].name: required", i)
+		} else if chainNames[chain.Name] {
+			fail("chains[%d].name: duplicate chain name %s", i, chain.Name)
+		}
+		chainNames[chain.Name] = true
+
+		switch chain.VM {
+		case "":
+			fail("chains[%d].vm: required", i)
+		// the VM types of the blockchain package
+		case "evm", "wasm", "custom", "tokenvm", "morpheusvm":
+		default:
+			fail("chains[%d].vm: unsupported VM type %s", i, chain.VM)
+		}
+
+		chainConfig := ChainConfig{
+			Name:       chain.Name,
+			Type:       chain.Type,
+			VMType:     chain.VM,
+			Validators: chain.Validators,
+		}
+
+		if chain.ChainID != 0 {
+			chainConfig.ChainID = strconv.FormatUint(chain.ChainID, 10)
+		}
+
+		var err error
+		if chainConfig.Genesis, err = readJSONFile(baseDir, chain.Genesis); err != nil {
+			fail("chains[%d].genesis: %w", i, err)
+		}
+		if chainConfig.Config, err = readJSONFile(baseDir, chain.Config); err != nil {
+			fail("chains[%d].config: %w", i, err)
+		}
+
+		validators := make(map[string]bool, len(chain.Validators))
+		for j, validator := range chain.Validators {
+			switch {
+			case !strings.HasPrefix(validator.NodeID, "NodeID-"):
+				fail("chains[%d].validators[%d].nodeId: invalid node ID %q", i, j, validator.NodeID)
+			case validators[validator.NodeID]:
+				fail("chains[%d].validators[%d].nodeId: duplicate validator %s", i, j, validator.NodeID)
+			}
+			validators[validator.NodeID] = true
+			if validator.Weight == 0 {
+				fail("chains[%d].validators[%d].weight: must be positive", i, j)
+			}
+		}
+
+		params.ChainConfigs = append(params.ChainConfigs, chainConfig)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// resolvePath resolves a path of the topology against its directory
+func resolvePath(baseDir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+// resolveBinaryPath resolves the node binary path, leaving bare command
+// names as they are
+func resolveBinaryPath(baseDir, path string) string {
+	if !strings.ContainsRune(path, filepath.Separator) {
+		return path
+	}
+	return resolvePath(baseDir, path)
+}
+
+// readJSONFile reads a JSON file referenced by the topology, returning nil for an empty path
+func readJSONFile(baseDir, path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(resolvePath(baseDir, path))
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", path)
+	}
+	return data, nil
+}
@@ -0,0 +1,227 @@
+// Copyright (C) 2020-2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTopology = `
+name: devnet
+type: local
+binaryPath: bin/luxd
+dataDir: data
+logLevel: debug
+ports:
+  start: 9650
+  end: 9659
+nodes:
+  - name: validator1
+    type: validator
+    stakeAmount: 5000
+    flags:
+      http-allowed-hosts: "*"
+  - name: api1
+    type: api
+chains:
+  - name: devchain
+    vm: evm
+    chainId: 96369
+    genesis: genesis.json
+    validators:
+      - nodeId: NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg
+        weight: 100
+`
+
+func writeTopologyFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestLoadTopology(t *testing.T) {
+	dir := writeTopologyFiles(t, map[string]string{
+		"devnet.yaml":  testTopology,
+		"genesis.json": `{"config":{"chainId":96369},"alloc":{}}`,
+	})
+
+	params, err := LoadTopology(filepath.Join(dir, "devnet.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "devnet", params.Name)
+	assert.Equal(t, NetworkTypeLocal, params.Type)
+	assert.Equal(t, filepath.Join(dir, "bin", "luxd"), params.BinaryPath)
+	assert.Equal(t, filepath.Join(dir, "data"), params.DataDir)
+	assert.Equal(t, 2, params.NumNodes)
+	assert.Equal(t, &PortRange{Start: 9650, End: 9659}, params.Ports)
+	assert.Equal(t, 9650, params.HTTPPort)
+	assert.Equal(t, 9651, params.StakingPort)
+
+	require.Len(t, params.Nodes, 2)
+	assert.Equal(t, NodeParams{
+		Name:        "validator1",
+		Type:        NodeTypeValidator,
+		StakeAmount: 5000,
+		Flags:       map[string]interface{}{"http-allowed-hosts": "*"},
+	}, params.Nodes[0])
+	assert.Equal(t, NodeTypeAPI, params.Nodes[1].Type)
+
+	require.Len(t, params.ChainConfigs, 1)
+	chain := params.ChainConfigs[0]
+	assert.Equal(t, "devchain", chain.Name)
+	assert.Equal(t, "evm", chain.VMType)
+	assert.Equal(t, "96369", chain.ChainID)
+	assert.JSONEq(t, `{"config":{"chainId":96369},"alloc":{}}`, string(chain.Genesis))
+	assert.Equal(t, []ChainValidator{{NodeID: "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", Weight: 100}}, chain.Validators)
+}
+
+func TestLoadTopology_JSON(t *testing.T) {
+	dir := writeTopologyFiles(t, map[string]string{
+		"devnet.json": `{"name":"devnet","numNodes":3,"binaryPath":"luxd"}`,
+	})
+
+	params, err := LoadTopology(filepath.Join(dir, "devnet.json"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, params.NumNodes)
+	assert.Equal(t, "luxd", params.BinaryPath)
+	assert.Empty(t, params.Nodes)
+
+	dir = writeTopologyFiles(t, map[string]string{
+		"devnet.json": `{"name":"devnet","numNodes":3,"numValidators":3}`,
+	})
+	_, err = LoadTopology(filepath.Join(dir, "devnet.json"))
+	assert.ErrorContains(t, err, "unknown field")
+}
+
+func TestLoadTopology_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology string
+		errMsgs  []string
+	}{
+		{
+			name:     "unknown field",
+			topology: "name: devnet\nnumNodes: 1\nnodeCount: 2\n",
+			errMsgs:  []string{"field nodeCount not found"},
+		},
+		{
+			name:     "no nodes",
+			topology: "name: devnet\n",
+			errMsgs:  []string{"nodes: at least one node is required"},
+		},
+		{
+			name:     "mismatched node count",
+			topology: "name: devnet\nnumNodes: 3\nnodes:\n  - name: a\n",
+			errMsgs:  []string{"numNodes: 3 does not match the 1 nodes listed"},
+		},
+		{
+			name:     "duplicate and invalid nodes",
+			topology: "name: devnet\nnodes:\n  - name: a\n  - name: a\n    type: miner\n",
+			errMsgs: []string{
+				"nodes[1].name: duplicate node name a",
+				"nodes[1].type: unsupported node type miner",
+			},
+		},
+		{
+			name:     "port range too small",
+			topology: "name: devnet\nnumNodes: 3\nports:\n  start: 9650\n  end: 9653\n",
+			errMsgs:  []string{"ports: range 9650-9653 has 4 ports, 3 nodes need 6"},
+		},
+		{
+			name:     "port range reversed",
+			topology: "name: devnet\nnumNodes: 1\nports:\n  start: 9660\n  end: 9650\n",
+			errMsgs:  []string{"ports: end 9650 is before start 9660"},
+		},
+		{
+			name: "invalid chains",
+			topology: `name: devnet
+numNodes: 1
+chains:
+  - name: one
+    genesis: missing.json
+    validators:
+      - nodeId: node1
+        weight: 0
+  - name: one
+    vm: evm
+    config: invalid.json
+  - name: two
+    vm: ethereum
+`,
+			errMsgs: []string{
+				"chains[0].vm: required",
+				"chains[0].genesis: open",
+				`chains[0].validators[0].nodeId: invalid node ID "node1"`,
+				"chains[0].validators[0].weight: must be positive",
+				"chains[1].name: duplicate chain name one",
+				"chains[1].config: invalid.json is not valid JSON",
+				"chains[2].vm: unsupported VM type ethereum",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTopologyFiles(t, map[string]string{
+				"topology.yml": tt.topology,
+				"invalid.json": "{",
+			})
+			_, err := LoadTopology(filepath.Join(dir, "topology.yml"))
+			require.Error(t, err)
+			for _, errMsg := range tt.errMsgs {
+				assert.ErrorContains(t, err, errMsg)
+			}
+		})
+	}
+}
+
+func TestNetworkManager_CreateNetworkFromTopology(t *testing.T) {
+	nm, backend := newTestManager(t)
+	ctx := context.Background()
+
+	dir := writeTopologyFiles(t, map[string]string{
+		"devnet.yaml":  testTopology,
+		"genesis.json": `{}`,
+	})
+	params, err := LoadTopology(filepath.Join(dir, "devnet.yaml"))
+	require.NoError(t, err)
+
+	network, err := nm.CreateNetwork(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, network.Nodes, 2)
+
+	validator := findNode(network.Nodes, "validator1")
+	require.NotNil(t, validator)
+	assert.Equal(t, NodeTypeValidator, validator.Type)
+	assert.Equal(t, uint64(5000), validator.StakeAmount)
+
+	api := findNode(network.Nodes, "api1")
+	require.NotNil(t, api)
+	assert.Equal(t, NodeTypeAPI, api.Type)
+
+	// node flags are passed with the ports taken from the topology range
+	var flags map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(backend.configs["validator1"]), &flags))
+	assert.Equal(t, "*", flags["http-allowed-hosts"])
+	assert.Equal(t, "http://127.0.0.1:9650", validator.Endpoint)
+	assert.JSONEq(t, `{"http-port":9652,"staking-port":9653}`, backend.configs["api1"])
+	assert.Equal(t, "http://127.0.0.1:9652", api.Endpoint)
+
+	_, err = nm.CreateNetwork(ctx, &NetworkParams{
+		Name:     "mismatch",
+		Type:     NetworkTypeLocal,
+		NumNodes: 3,
+		Nodes:    []NodeParams{{Name: "a"}},
+	})
+	assert.ErrorContains(t, err, "does not match")
+}
@@ -5,19 +5,31 @@ package sdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"path/filepath"
 
+	"github.com/luxfi/crypto/secp256k1"
+	"github.com/luxfi/geth/common"
 	"github.com/luxfi/log"
+	"github.com/luxfi/node/vms/secp256k1fx"
 	"github.com/luxfi/sdk/blockchain"
 	"github.com/luxfi/sdk/config"
+	"github.com/luxfi/sdk/constants"
+	"github.com/luxfi/sdk/events"
+	"github.com/luxfi/sdk/key"
+	"github.com/luxfi/sdk/metrics"
 	"github.com/luxfi/sdk/network"
+	"github.com/luxfi/sdk/utils"
 )
 
 // LuxSDK is the main SDK interface providing comprehensive blockchain development capabilities
 type LuxSDK struct {
 	networkManager    *network.NetworkManager
 	blockchainBuilder *blockchain.Builder
+	events            *events.Bus
 	config            *config.Config
 	logger            log.Logger
 }
@@ -32,8 +44,21 @@ func New(cfg *config.Config) (*LuxSDK, error) {
 	// Use the global logger with SDK context
 	logger := log.New("sdk")
 
+	// Persist networks and snapshots under the SDK data dir unless configured otherwise
+	networkConfig := cfg.Network
+	if networkConfig != nil && cfg.DataDir != "" {
+		withDataDir := *networkConfig
+		if withDataDir.RegistryDir == "" {
+			withDataDir.RegistryDir = filepath.Join(utils.ExpandHome(cfg.DataDir), "networks")
+		}
+		if withDataDir.SnapshotsDir == "" {
+			withDataDir.SnapshotsDir = filepath.Join(utils.ExpandHome(cfg.DataDir), constants.SnapshotsDirName)
+		}
+		networkConfig = &withDataDir
+	}
+
 	// Initialize network manager
-	networkManager, err := network.NewNetworkManager(cfg.Network, logger)
+	networkManager, err := network.NewNetworkManager(networkConfig, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network manager: %w", err)
 	}
@@ -41,14 +66,48 @@ func New(cfg *config.Config) (*LuxSDK, error) {
 	// Initialize blockchain builder
 	blockchainBuilder := blockchain.NewBuilder(logger)
 
+	// Publish network and blockchain status transitions on a shared bus
+	bus := events.NewBus()
+	networkManager.SetEventBus(bus)
+	blockchainBuilder.SetEventBus(bus)
+
+	// Upgrades are applied by restarting the nodes of the network
+	blockchainBuilder.SetNodeRestarter(networkManager)
+
+	// L1s are deployed with the P-Chain wallet of the configured key
+	if cfg.KeyPath != "" {
+		keychain, err := loadKeychain(cfg.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		blockchainBuilder.SetKeychain(keychain)
+	}
+
 	return &LuxSDK{
 		networkManager:    networkManager,
 		blockchainBuilder: blockchainBuilder,
+		events:            bus,
 		config:            cfg,
 		logger:            logger,
 	}, nil
 }
 
+// loadKeychain loads the secp256k1 key at keyPath into a keychain
+func loadKeychain(keyPath string) (*secp256k1fx.Keychain, error) {
+	softKey, err := key.LoadSoft(0, utils.ExpandHome(keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key %s: %w", keyPath, err)
+	}
+	if softKey.Secp256k1PrivateKey == nil {
+		return nil, fmt.Errorf("key %s is a %s key, P-Chain wallets need a secp256k1 key", keyPath, softKey.Type)
+	}
+	privateKey, err := secp256k1.ToPrivateKey(softKey.Secp256k1PrivateKey.Serialize())
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %s: %w", keyPath, err)
+	}
+	return secp256k1fx.NewKeychain(privateKey), nil
+}
+
 // Networks returns the network manager for network operations
 func (sdk *LuxSDK) Networks() *network.NetworkManager {
 	return sdk.networkManager
@@ -59,6 +118,32 @@ func (sdk *LuxSDK) Blockchains() *blockchain.Builder {
 	return sdk.blockchainBuilder
 }
 
+// Close stops the L2 sequencers started by the SDK and closes the
+// connection to the network backend
+func (sdk *LuxSDK) Close() error {
+	return errors.Join(sdk.blockchainBuilder.Close(), sdk.networkManager.Close())
+}
+
+// MetricsHandler returns an http.Handler serving the metrics of the SDK's
+// blockchains and networks, and of the RPC calls of evm clients, in the
+// OpenMetrics text format. Blockchain metrics are collected with
+// Blockchains().CollectMetrics.
+func (sdk *LuxSDK) MetricsHandler() http.Handler {
+	return metrics.NewExporter(sdk.blockchainBuilder, sdk.networkManager)
+}
+
+// Subscribe returns a channel receiving the network and blockchain status
+// transitions matching the filter. A subscriber that falls behind loses its
+// oldest events instead of blocking the SDK.
+func (sdk *LuxSDK) Subscribe(filter events.Filter) <-chan events.Event {
+	return sdk.events.Subscribe(filter)
+}
+
+// Unsubscribe stops delivering events to a channel returned by Subscribe and closes it
+func (sdk *LuxSDK) Unsubscribe(ch <-chan events.Event) {
+	sdk.events.Unsubscribe(ch)
+}
+
 // LaunchNetwork launches a network using the network manager
 func (sdk *LuxSDK) LaunchNetwork(ctx context.Context, networkType string, numNodes int) (*network.Network, error) {
 	params := &network.NetworkParams{
@@ -69,15 +154,34 @@ func (sdk *LuxSDK) LaunchNetwork(ctx context.Context, networkType string, numNod
 	return sdk.networkManager.CreateNetwork(ctx, params)
 }
 
+// LoadTopology loads a network topology file and returns the parameters of
+// the network together with those of the chains to create on it
+func LoadTopology(path string) (*network.NetworkParams, []*blockchain.CreateParams, error) {
+	networkParams, err := network.LoadTopology(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	chainParams, err := blockchain.CreateParamsFromChainConfigs(networkParams.ChainConfigs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid topology %s: %w", path, err)
+	}
+	return networkParams, chainParams, nil
+}
+
 // CreateAndDeployBlockchain creates and deploys a blockchain
 func (sdk *LuxSDK) CreateAndDeployBlockchain(ctx context.Context, params *BlockchainParams) (*blockchain.Blockchain, error) {
 	// Create blockchain configuration
 	createParams := &blockchain.CreateParams{
-		Name:    params.Name,
-		Type:    params.Type,
-		VMType:  params.VMType,
-		ChainID: params.ChainID,
-		Genesis: params.Genesis,
+		Name:        params.Name,
+		Type:        params.Type,
+		VMType:      params.VMType,
+		ChainID:     params.ChainID,
+		Genesis:     params.Genesis,
+		Allocations: params.Allocations,
+		FeePreset:   params.FeePreset,
+		Precompiles: params.Precompiles,
+		Admins:      params.Admins,
+		Wallet:      params.Wallet,
 	}
 
 	// Create blockchain
@@ -104,6 +208,15 @@ type BlockchainParams struct {
 	ChainID *big.Int
 	Genesis []byte
 	Network *network.Network
+	// Allocations, FeePreset, Precompiles and Admins configure the
+	// generated genesis of an EVM chain, when Genesis is not set
+	Allocations map[common.Address]blockchain.GenesisAccount
+	FeePreset   blockchain.FeePreset
+	Precompiles blockchain.EVMPrecompiles
+	Admins      []common.Address
+	// Wallet issues the P-Chain transactions deploying an L1, instead of
+	// the wallet of Config.KeyPath
+	Wallet blockchain.PChainWallet
 }
 
 // NodeInfo contains information about a node